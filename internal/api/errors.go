@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -18,6 +19,16 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API error %d: %s", e.Code, e.Message)
 }
 
+// CodeAuthenticationError is returned for calls without a valid session, e.g. after
+// the session has expired
+const CodeAuthenticationError = 2200
+
+// IsAuthenticationError reports whether err is an API authentication error
+func IsAuthenticationError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == CodeAuthenticationError
+}
+
 var errorCodeMap = map[int]string{
 	1000: "Command completed successfully",
 	1001: "Command completed successfully; action pending",
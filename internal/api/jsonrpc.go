@@ -12,6 +12,23 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// sensitiveParams are the parameters whose values are redacted in logs
+var sensitiveParams = []string{"pass", "password", "currentpassword"}
+
+// redactParams returns a copy of the parameters with sensitive values redacted
+func redactParams(params map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		redacted[key] = value
+	}
+	for _, key := range sensitiveParams {
+		if _, ok := redacted[key]; ok {
+			redacted[key] = "***REDACTED***"
+		}
+	}
+	return redacted
+}
+
 // sanitizeForLogging redacts sensitive fields from JSON data
 func sanitizeForLogging(data []byte) []byte {
 	var obj map[string]interface{}
@@ -22,13 +39,7 @@ func sanitizeForLogging(data []byte) []byte {
 
 	// Check if params exist and redact sensitive fields
 	if params, ok := obj["params"].(map[string]interface{}); ok {
-		// Redact password fields
-		if _, hasPass := params["pass"]; hasPass {
-			params["pass"] = "***REDACTED***"
-		}
-		if _, hasPassword := params["password"]; hasPassword {
-			params["password"] = "***REDACTED***"
-		}
+		obj["params"] = redactParams(params)
 	}
 
 	// Re-marshal the sanitized data
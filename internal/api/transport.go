@@ -2,10 +2,12 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"net"
 	"net/http"
 	"os"
+	"sync"
 	"syscall"
 	"time"
 
@@ -25,6 +27,13 @@ type Transport struct {
 	userAgent string
 	session   *Session
 	jsonrpc   *JSONRPCClient
+
+	// Credentials of the current session, used to log in again when it expires
+	credentials struct {
+		sync.Mutex
+		username string
+		password string
+	}
 }
 
 func NewTransport() (*Transport, error) {
@@ -62,7 +71,14 @@ func (t *Transport) SetUserAgent(userAgent string) {
 }
 
 func (t *Transport) Login(ctx context.Context, username, password string) error {
-	return t.loginWithRetry(ctx, username, password, DefaultMaxRetries)
+	if err := t.loginWithRetry(ctx, username, password, DefaultMaxRetries); err != nil {
+		return err
+	}
+
+	t.credentials.Lock()
+	t.credentials.username, t.credentials.password = username, password
+	t.credentials.Unlock()
+	return nil
 }
 
 func (t *Transport) loginWithRetry(ctx context.Context, username, password string, maxRetries int) error {
@@ -133,6 +149,10 @@ func (t *Transport) loginWithRetry(ctx context.Context, username, password strin
 }
 
 func (t *Transport) Logout(ctx context.Context) error {
+	t.credentials.Lock()
+	t.credentials.username, t.credentials.password = "", ""
+	t.credentials.Unlock()
+
 	return t.logoutWithRetry(ctx, DefaultMaxRetries)
 }
 
@@ -176,14 +196,32 @@ func (t *Transport) logoutWithRetry(ctx context.Context, maxRetries int) error {
 	return lastErr
 }
 
+// Call calls an API method. If the session has expired, e.g. in a long running server,
+// it logs in again with the credentials of the session and repeats the call once.
 func (t *Transport) Call(ctx context.Context, method string, params map[string]interface{}) (map[string]interface{}, error) {
+	response, err := t.callWithRetry(ctx, method, params, DefaultMaxRetries)
+	if !IsAuthenticationError(err) {
+		return response, err
+	}
+
+	t.credentials.Lock()
+	username, password := t.credentials.username, t.credentials.password
+	t.credentials.Unlock()
+	if username == "" {
+		return nil, err
+	}
+
+	log.Info().Str("method", method).Msg("Session expired, logging in again")
+	if loginErr := t.loginWithRetry(ctx, username, password, DefaultMaxRetries); loginErr != nil {
+		return nil, fmt.Errorf("%w (login after session expiry failed: %v)", err, loginErr)
+	}
 	return t.callWithRetry(ctx, method, params, DefaultMaxRetries)
 }
 
 func (t *Transport) callWithRetry(ctx context.Context, method string, params map[string]interface{}, maxRetries int) (map[string]interface{}, error) {
 	log.Debug().
 		Str("method", method).
-		Interface("params", params).
+		Interface("params", redactParams(params)).
 		Msg("Making API call")
 
 	var lastErr error
@@ -73,8 +73,20 @@ func NewApp() *cli.App {
 		},
 		Commands: []*cli.Command{
 			commands.DNSCommand(),
+			commands.MailCommand(),
+			commands.RedirectCommand(),
 			commands.DomainCommand(),
+			commands.ContactCommand(),
+			commands.HostCommand(),
+			commands.NameserverSetCommand(),
+			commands.TagCommand(),
+			commands.CertCommand(),
+			commands.ACMECommand(),
+			commands.ServeCommand(),
+			commands.AccountingCommand(),
 			commands.AccountCommand(),
+			commands.CustomerCommand(),
+			commands.MessagesCommand(),
 			commands.BackupCommand(),
 		},
 		Before: func(c *cli.Context) error {
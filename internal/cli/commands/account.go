@@ -2,11 +2,19 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v2"
 
 	"github.com/nmeilick/inwx-cli/internal/cli/output"
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
 )
 
 func AccountCommand() *cli.Command {
@@ -19,6 +27,151 @@ func AccountCommand() *cli.Command {
 				Usage:  "Show account information",
 				Action: accountInfo,
 			},
+			{
+				Name:  "change-password",
+				Usage: "Change the API password of the current account",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "update-config",
+						Usage: "Write the new password to the config file",
+					},
+					&cli.BoolFlag{
+						Name:  "generate",
+						Usage: "Generate a random password instead of prompting for one",
+					},
+					&cli.IntFlag{
+						Name:  "password-length",
+						Usage: "Length of the generated password",
+						Value: 24,
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the change in API testing mode without executing it",
+					},
+				},
+				Action: changeAccountPassword,
+			},
+			{
+				Name:  "update",
+				Usage: "Update account profile settings",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "email", Usage: "Email address"},
+					&cli.StringFlag{Name: "email-billing", Usage: "Email address for invoices"},
+					&cli.StringFlag{Name: "email-automated", Usage: "Email address for automated emails"},
+					&cli.StringFlag{Name: "firstname", Usage: "First name"},
+					&cli.StringFlag{Name: "lastname", Usage: "Last name"},
+					&cli.StringFlag{Name: "org", Usage: "Organisation"},
+					&cli.StringFlag{Name: "street", Usage: "Street"},
+					&cli.StringFlag{Name: "pc", Usage: "Postal code"},
+					&cli.StringFlag{Name: "city", Usage: "City"},
+					&cli.StringFlag{Name: "cc", Usage: "Country code"},
+					&cli.StringFlag{Name: "voice", Usage: "Phone number (e.g., +49.30123456)"},
+					&cli.StringFlag{Name: "language", Usage: "Interface language (e.g., en, de)"},
+					&cli.Float64Flag{Name: "low-balance", Usage: "Balance below which a notification is sent"},
+					&cli.BoolFlag{Name: "renewal-report", Usage: "Receive renewal report emails (use --renewal-report=false to disable)"},
+					&cli.BoolFlag{Name: "notification-email", Usage: "Receive notification emails (use --notification-email=false to disable)"},
+					&cli.IntFlag{Name: "default-registrant", Usage: "Default registrant contact handle ID"},
+					&cli.IntFlag{Name: "default-admin", Usage: "Default admin contact handle ID"},
+					&cli.IntFlag{Name: "default-tech", Usage: "Default tech contact handle ID"},
+					&cli.IntFlag{Name: "default-billing", Usage: "Default billing contact handle ID"},
+					&cli.StringFlag{Name: "default-renewal-mode", Usage: "Default renewal mode for new domains (AUTORENEW, AUTODELETE, AUTOEXPIRE)"},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the update in API testing mode without executing it",
+					},
+				},
+				Action: updateAccount,
+			},
+			{
+				Name:  "users",
+				Usage: "Sub-account and role management",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "List sub-accounts",
+						Action: listSubAccounts,
+					},
+					{
+						Name:      "create",
+						Usage:     "Create a sub-account",
+						ArgsUsage: "<username>",
+						Description: "The API cannot set the password of a sub-account, so set it in the INWX\n" +
+							"   web interface before using the account.",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "email",
+								Usage:    "Email address of the account",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "firstname",
+								Usage: "First name (default: first name of the main account)",
+							},
+							&cli.StringFlag{
+								Name:  "lastname",
+								Usage: "Last name (default: last name of the main account)",
+							},
+							&cli.StringFlag{
+								Name:  "title",
+								Usage: "Salutation title (MISS, MISTER, COMPANY)",
+								Value: "COMPANY",
+							},
+							&cli.StringFlag{
+								Name:  "org",
+								Usage: "Organisation (default: organisation of the main account)",
+							},
+							&cli.StringFlag{
+								Name:  "street",
+								Usage: "Street (default: address of the main account)",
+							},
+							&cli.StringFlag{
+								Name:  "pc",
+								Usage: "Postal code (default: address of the main account)",
+							},
+							&cli.StringFlag{
+								Name:  "city",
+								Usage: "City (default: address of the main account)",
+							},
+							&cli.StringFlag{
+								Name:  "cc",
+								Usage: "Country code (default: address of the main account)",
+							},
+							&cli.StringSliceFlag{
+								Name:    "role",
+								Aliases: []string{"r"},
+								Usage:   "Grant role(s) after creation (e.g., dns)",
+							},
+						},
+						Action: createSubAccount,
+					},
+					{
+						Name:      "delete",
+						Usage:     "Delete sub-account(s)",
+						ArgsUsage: "<id|username...>",
+						Action:    deleteSubAccounts,
+					},
+					{
+						Name:      "roles",
+						Usage:     "Show the roles of an account (or all assignable roles)",
+						ArgsUsage: "[id|username]",
+						Action:    showAccountRoles,
+					},
+					{
+						Name:      "grant",
+						Usage:     "Grant role(s) to an account",
+						ArgsUsage: "<id|username> <role...>",
+						Action:    grantAccountRoles,
+					},
+					{
+						Name:      "revoke",
+						Usage:     "Revoke role(s) from an account",
+						ArgsUsage: "<id|username> <role...>",
+						Action:    revokeAccountRoles,
+					},
+				},
+			},
 		},
 	}
 }
@@ -58,3 +211,567 @@ func accountInfo(c *cli.Context) error {
 		}
 	})
 }
+
+// resolveSubAccount finds a sub-account by numeric ID or username
+func resolveSubAccount(accounts []inwx.SubAccount, ref string) (*inwx.SubAccount, error) {
+	id, _ := strconv.Atoi(ref)
+	for i, account := range accounts {
+		if (id > 0 && account.ID == id) || strings.EqualFold(account.Username, ref) {
+			return &accounts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("sub-account '%s' not found", ref)
+}
+
+// parseRoles converts role names or IDs into role IDs
+func parseRoles(args []string) ([]int, error) {
+	var roles []int
+	for _, arg := range parseCommaSeparatedValues(args) {
+		role, err := inwx.ParseRole(arg)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("at least one role must be specified")
+	}
+	return roles, nil
+}
+
+func listSubAccounts(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	accounts, err := client.Account().List(ctx)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatSubAccounts(accounts)
+		case *output.JSONFormatter:
+			return f.FormatSubAccounts(accounts)
+		case *output.YAMLFormatter:
+			return f.FormatSubAccounts(accounts)
+		case *output.CSVFormatter:
+			return f.FormatSubAccounts(accounts)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func createSubAccount(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one username must be specified")
+	}
+
+	email := c.String("email")
+	if err := utils.ValidateEmail(email); err != nil {
+		return err
+	}
+
+	var roles []int
+	if len(c.StringSlice("role")) > 0 {
+		var err error
+		if roles, err = parseRoles(c.StringSlice("role")); err != nil {
+			return err
+		}
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	accountService := client.Account()
+
+	// Address fields are mandatory for accounts; fall back to the main account's data
+	mainAccount, err := accountService.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get main account details: %w", err)
+	}
+	value := func(flag, fallback string) string {
+		if v := c.String(flag); v != "" {
+			return v
+		}
+		return fallback
+	}
+
+	account := inwx.SubAccount{
+		Username:    c.Args().First(),
+		Title:       c.String("title"),
+		Firstname:   value("firstname", mainAccount.Firstname),
+		Lastname:    value("lastname", mainAccount.Lastname),
+		Org:         value("org", mainAccount.Org),
+		Street:      value("street", mainAccount.Street),
+		PostalCode:  value("pc", mainAccount.PostalCode),
+		City:        value("city", mainAccount.City),
+		CountryCode: value("cc", mainAccount.CountryCode),
+		Email:       email,
+	}
+
+	id, err := accountService.Create(ctx, account)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Sub-account %s created (ID: %d)\n", account.Username, id)
+
+	failed := 0
+	for _, role := range roles {
+		if err := accountService.AddRole(ctx, id, role); err != nil {
+			log.Error().Err(err).Str("role", inwx.RoleNames[role]).Msg("Failed to grant role")
+			failed++
+			continue
+		}
+		fmt.Printf("✓ Granted role %s\n", inwx.RoleNames[role])
+	}
+
+	// account.create takes no password, the account cannot log in until one is set
+	fmt.Println("⚠️  The sub-account has no password yet - set it in the INWX web interface before using it.")
+
+	if failed > 0 {
+		return fmt.Errorf("failed to grant %d role(s)", failed)
+	}
+
+	return nil
+}
+
+func deleteSubAccounts(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("at least one account ID or username must be specified")
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	accountService := client.Account()
+	accounts, err := accountService.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	skipPrompt := c.Bool("yes")
+	var ids []int
+	for _, ref := range parseCommaSeparatedValues(c.Args().Slice()) {
+		account, err := resolveSubAccount(accounts, ref)
+		if err != nil {
+			return err
+		}
+
+		if !skipPrompt {
+			result, err := utils.AskConfirmation(fmt.Sprintf("Delete sub-account %s (ID: %d)?", account.Username, account.ID), false)
+			if err != nil {
+				return err
+			}
+			if result == utils.ConfirmationCancel {
+				fmt.Println("Operation cancelled")
+				return nil
+			}
+			if result == utils.ConfirmationNo {
+				continue
+			}
+			if result == utils.ConfirmationAll {
+				skipPrompt = true
+			}
+		}
+		ids = append(ids, account.ID)
+	}
+
+	if len(ids) == 0 {
+		fmt.Println("No sub-accounts selected")
+		return nil
+	}
+
+	deleted, err := accountService.Delete(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Deleted %d sub-account(s)\n", deleted)
+
+	if deleted < len(ids) {
+		return fmt.Errorf("failed to delete %d of %d sub-account(s)", len(ids)-deleted, len(ids))
+	}
+
+	return nil
+}
+
+func showAccountRoles(c *cli.Context) error {
+	var roles []inwx.Role
+
+	if c.NArg() == 0 {
+		for id, name := range inwx.RoleNames {
+			roles = append(roles, inwx.Role{ID: id, Name: name})
+		}
+		sort.Slice(roles, func(i, j int) bool { return roles[i].ID < roles[j].ID })
+	} else {
+		client, err := createClient(c)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		if err := client.Login(ctx); err != nil {
+			return err
+		}
+		defer func() {
+			if err := client.Logout(ctx); err != nil {
+				log.Error().Err(err).Msg("Failed to logout")
+			}
+		}()
+
+		accountService := client.Account()
+		accounts, err := accountService.List(ctx)
+		if err != nil {
+			return err
+		}
+		account, err := resolveSubAccount(accounts, c.Args().First())
+		if err != nil {
+			return err
+		}
+
+		if roles, err = accountService.Roles(ctx, account.ID); err != nil {
+			return err
+		}
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatRoles(roles)
+		case *output.JSONFormatter:
+			return f.FormatRoles(roles)
+		case *output.YAMLFormatter:
+			return f.FormatRoles(roles)
+		case *output.CSVFormatter:
+			return f.FormatRoles(roles)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func grantAccountRoles(c *cli.Context) error {
+	return changeAccountRoles(c, "Granted", func(ctx context.Context, s *inwx.AccountService, accountID, roleID int) error {
+		return s.AddRole(ctx, accountID, roleID)
+	})
+}
+
+func revokeAccountRoles(c *cli.Context) error {
+	return changeAccountRoles(c, "Revoked", func(ctx context.Context, s *inwx.AccountService, accountID, roleID int) error {
+		return s.RemoveRole(ctx, accountID, roleID)
+	})
+}
+
+// changeAccountRoles applies change for every role given after the account argument
+func changeAccountRoles(c *cli.Context, verb string, change func(ctx context.Context, s *inwx.AccountService, accountID, roleID int) error) error {
+	if c.NArg() < 2 {
+		return fmt.Errorf("an account and at least one role must be specified")
+	}
+	roles, err := parseRoles(c.Args().Slice()[1:])
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	accountService := client.Account()
+	accounts, err := accountService.List(ctx)
+	if err != nil {
+		return err
+	}
+	account, err := resolveSubAccount(accounts, c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, role := range roles {
+		if err := change(ctx, accountService, account.ID, role); err != nil {
+			log.Error().Err(err).Str("role", inwx.RoleNames[role]).Msg("Failed to change role")
+			failed++
+			continue
+		}
+		fmt.Printf("✓ %s role %s for %s\n", verb, inwx.RoleNames[role], account.Username)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to change %d role(s)", failed)
+	}
+
+	return nil
+}
+
+func changeAccountPassword(c *cli.Context) error {
+	dryRun := c.Bool("dry-run")
+
+	config, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+	if config.API.Username == "" || config.API.Password == "" {
+		return fmt.Errorf("username and password must be configured to change the password")
+	}
+
+	var configPath string
+	if c.Bool("update-config") {
+		if configPath, err = locateConfigFile(c); err != nil {
+			return err
+		}
+		if configPath == "" {
+			return fmt.Errorf("no config file found to update")
+		}
+		if _, err := os.Stat(configPath); err != nil {
+			return fmt.Errorf("config file %s not accessible: %w", configPath, err)
+		}
+		if c.String("password") != "" {
+			log.Warn().Msg("The password is also set via flag or environment variable, which overrides the config file")
+		}
+	}
+
+	var current string
+	err = survey.AskOne(&survey.Password{
+		Message: "Current password:",
+	}, &current, survey.WithValidator(survey.Required))
+	if err != nil {
+		return err
+	}
+	if current != config.API.Password {
+		return fmt.Errorf("current password does not match the configured password")
+	}
+
+	var newPassword string
+	if c.Bool("generate") {
+		if newPassword, err = utils.GeneratePassword(c.Int("password-length")); err != nil {
+			return err
+		}
+	} else {
+		err = survey.AskOne(&survey.Password{
+			Message: "New password:",
+		}, &newPassword, survey.WithValidator(func(val interface{}) error {
+			str, _ := val.(string)
+			if len(str) < 8 {
+				return fmt.Errorf("password must be at least 8 characters long")
+			}
+			if str == current {
+				return fmt.Errorf("new password must differ from the current password")
+			}
+			return nil
+		}))
+		if err != nil {
+			return err
+		}
+
+		var confirm string
+		if err := survey.AskOne(&survey.Password{Message: "Repeat new password:"}, &confirm); err != nil {
+			return err
+		}
+		if confirm != newPassword {
+			return fmt.Errorf("passwords do not match")
+		}
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	if err := client.Account(inwx.WithAccountTesting(dryRun)).ChangePassword(ctx, config.API.Username, current, newPassword); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Println("✓ Password change validated in API testing mode - password was not changed")
+		return nil
+	}
+	fmt.Printf("✓ Password of %s changed\n", config.API.Username)
+
+	if configPath != "" {
+		if err := updateConfigPassword(configPath, newPassword); err != nil {
+			// The API password already changed, so the new password must not get lost
+			fmt.Printf("\nNew password: %s\n", newPassword)
+			return fmt.Errorf("password changed, but updating %s failed: %w", configPath, err)
+		}
+		fmt.Printf("✓ Updated password in %s\n", configPath)
+	} else if c.Bool("generate") {
+		fmt.Printf("\nNew password: %s\n", newPassword)
+		fmt.Println("⚠️  Store this password now - it will not be shown again.")
+	}
+
+	return nil
+}
+
+func updateAccount(c *cli.Context) error {
+	fields := make(map[string]interface{})
+
+	stringFields := map[string]string{
+		"email":           "email",
+		"email-billing":   "emailBilling",
+		"email-automated": "emailAutomated",
+		"firstname":       "firstname",
+		"lastname":        "lastname",
+		"org":             "org",
+		"street":          "street",
+		"pc":              "pc",
+		"city":            "city",
+		"cc":              "cc",
+		"voice":           "voice",
+		"language":        "language",
+	}
+	for flag, key := range stringFields {
+		if c.IsSet(flag) {
+			fields[key] = c.String(flag)
+		}
+	}
+
+	intFields := map[string]string{
+		"default-registrant": "defaultRegistrant",
+		"default-admin":      "defaultAdmin",
+		"default-tech":       "defaultTech",
+		"default-billing":    "defaultBilling",
+	}
+	for flag, key := range intFields {
+		if c.IsSet(flag) {
+			fields[key] = c.Int(flag)
+		}
+	}
+
+	boolFields := map[string]string{
+		"renewal-report":     "renewalReport",
+		"notification-email": "notificationEmail",
+	}
+	for flag, key := range boolFields {
+		if c.IsSet(flag) {
+			fields[key] = c.Bool(flag)
+		}
+	}
+
+	if c.IsSet("low-balance") {
+		fields["lowBalance"] = c.Float64("low-balance")
+	}
+	if c.IsSet("default-renewal-mode") {
+		mode := strings.ToUpper(c.String("default-renewal-mode"))
+		if !inwx.IsValidRenewalMode(mode) {
+			return fmt.Errorf("invalid renewal mode %q (must be one of %s)", mode, strings.Join(inwx.RenewalModes, ", "))
+		}
+		fields["defaultRenewalMode"] = mode
+	}
+
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields to update specified")
+	}
+
+	for _, key := range []string{"email", "emailBilling", "emailAutomated"} {
+		if value, ok := fields[key].(string); ok && value != "" {
+			if err := utils.ValidateEmail(value); err != nil {
+				return fmt.Errorf("invalid %s: %w", key, err)
+			}
+		}
+	}
+	if value, ok := fields["voice"].(string); ok && value != "" {
+		if err := utils.ValidatePhone(value); err != nil {
+			return err
+		}
+	}
+	if value, ok := fields["cc"].(string); ok {
+		if err := utils.ValidateCountryCode(value); err != nil {
+			return err
+		}
+		fields["cc"] = strings.ToUpper(value)
+	}
+
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	if err := client.Account(inwx.WithAccountTesting(dryRun)).Update(ctx, fields); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if dryRun {
+		fmt.Printf("✓ Update of %s validated in API testing mode - account was not modified\n", strings.Join(keys, ", "))
+		return nil
+	}
+
+	fmt.Printf("✓ Updated %s\n", strings.Join(keys, ", "))
+	return nil
+}
@@ -0,0 +1,454 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/cli/output"
+)
+
+func AccountingCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "accounting",
+		Usage: "Account balance, invoices and transactions",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "balance",
+				Usage:  "Show the account balance",
+				Action: showAccountBalance,
+			},
+			{
+				Name:   "invoices",
+				Usage:  "List invoices",
+				Action: listInvoices,
+			},
+			{
+				Name:      "invoice",
+				Usage:     "Download an invoice as PDF",
+				ArgsUsage: "<invoice-id>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "pdf",
+						Usage: "Write the PDF to `FILE` (default: <invoice-id>.pdf)",
+					},
+				},
+				Action: downloadInvoice,
+			},
+			{
+				Name:  "statement",
+				Usage: "Download the statement of all transactions in a date range",
+				Flags: []cli.Flag{
+					&cli.TimestampFlag{
+						Name:     "from",
+						Usage:    "Start date (YYYY-MM-DD)",
+						Layout:   "2006-01-02",
+						Required: true,
+					},
+					&cli.TimestampFlag{
+						Name:     "to",
+						Usage:    "End date (YYYY-MM-DD)",
+						Layout:   "2006-01-02",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "pdf",
+						Usage: "Write the PDF to `FILE` (default: statement-<from>-<to>.pdf)",
+					},
+				},
+				Action: downloadStatement,
+			},
+			{
+				Name:  "log",
+				Usage: "List account transactions",
+				Flags: []cli.Flag{
+					&cli.TimestampFlag{
+						Name:   "from",
+						Usage:  "Only transactions on or after date (YYYY-MM-DD)",
+						Layout: "2006-01-02",
+					},
+					&cli.TimestampFlag{
+						Name:   "to",
+						Usage:  "Only transactions before date (YYYY-MM-DD)",
+						Layout: "2006-01-02",
+					},
+					&cli.IntFlag{
+						Name:  "page",
+						Usage: "Page number",
+						Value: 1,
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Usage: "Entries per page",
+						Value: 20,
+					},
+				},
+				Action: showAccountingLog,
+			},
+			{
+				Name:  "check",
+				Usage: "Check that the balance covers upcoming renewals (exits non-zero if not)",
+				Flags: []cli.Flag{
+					&cli.Float64Flag{
+						Name:  "min-balance",
+						Usage: "Minimum balance that must remain after all upcoming renewals",
+						Value: 0,
+					},
+					&cli.IntFlag{
+						Name:  "days",
+						Usage: "Include automatic renewals due within this many days",
+						Value: 30,
+					},
+				},
+				Action: checkFunds,
+			},
+			{
+				Name:   "locked",
+				Usage:  "List funds locked for orders in process",
+				Action: showLockedFunds,
+			},
+			{
+				Name:      "credit",
+				Usage:     "Show the details of a deposit payment",
+				ArgsUsage: "<credit-log-id>",
+				Action:    showCreditLog,
+			},
+		},
+	}
+}
+
+func showAccountBalance(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	balance, err := client.Accounting().Balance(ctx)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatAccountBalance(balance)
+		case *output.JSONFormatter:
+			return f.FormatAccountBalance(balance)
+		case *output.YAMLFormatter:
+			return f.FormatAccountBalance(balance)
+		case *output.CSVFormatter:
+			return f.FormatAccountBalance(balance)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func listInvoices(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	invoices, err := client.Accounting().ListInvoices(ctx)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatInvoices(invoices)
+		case *output.JSONFormatter:
+			return f.FormatInvoices(invoices)
+		case *output.YAMLFormatter:
+			return f.FormatInvoices(invoices)
+		case *output.CSVFormatter:
+			return f.FormatInvoices(invoices)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func downloadInvoice(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one invoice ID must be specified")
+	}
+	invoiceID := c.Args().First()
+
+	path := c.String("pdf")
+	if path == "" {
+		path = invoiceID + ".pdf"
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	data, err := client.Accounting().GetInvoice(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	return writeDocumentFile(c, path, data)
+}
+
+func downloadStatement(c *cli.Context) error {
+	from := *c.Timestamp("from")
+	to := *c.Timestamp("to")
+	if !to.After(from) {
+		return fmt.Errorf("--to must be after --from")
+	}
+
+	path := c.String("pdf")
+	if path == "" {
+		path = fmt.Sprintf("statement-%s-%s.pdf", from.Format("20060102"), to.Format("20060102"))
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	data, err := client.Accounting().GetStatement(ctx, from, to, "pdf")
+	if err != nil {
+		return err
+	}
+
+	return writeDocumentFile(c, path, data)
+}
+
+func showAccountingLog(c *cli.Context) error {
+	var from, to time.Time
+	if ts := c.Timestamp("from"); ts != nil {
+		from = *ts
+	}
+	if ts := c.Timestamp("to"); ts != nil {
+		to = *ts
+	}
+
+	page := c.Int("page")
+	limit := c.Int("limit")
+	if page < 1 || limit < 1 {
+		return fmt.Errorf("--page and --limit must be positive")
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	accountingLog, err := client.Accounting().Log(ctx, from, to, page, limit)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatAccountingLog(accountingLog)
+		case *output.JSONFormatter:
+			return f.FormatAccountingLog(accountingLog)
+		case *output.YAMLFormatter:
+			return f.FormatAccountingLog(accountingLog)
+		case *output.CSVFormatter:
+			return f.FormatAccountingLog(accountingLog)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func showLockedFunds(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	funds, err := client.Accounting().LockedFunds(ctx)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatLockedFunds(funds)
+		case *output.JSONFormatter:
+			return f.FormatLockedFunds(funds)
+		case *output.YAMLFormatter:
+			return f.FormatLockedFunds(funds)
+		case *output.CSVFormatter:
+			return f.FormatLockedFunds(funds)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func showCreditLog(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one credit log ID must be specified")
+	}
+	id, err := strconv.Atoi(c.Args().First())
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid credit log ID: %s", c.Args().First())
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	creditLog, err := client.Accounting().CreditLog(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatCreditLog(creditLog)
+		case *output.JSONFormatter:
+			return f.FormatCreditLog(creditLog)
+		case *output.YAMLFormatter:
+			return f.FormatCreditLog(creditLog)
+		case *output.CSVFormatter:
+			return f.FormatCreditLog(creditLog)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func checkFunds(c *cli.Context) error {
+	minBalance := c.Float64("min-balance")
+	days := c.Int("days")
+	if days < 0 {
+		return fmt.Errorf("--days must not be negative")
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	forecast, err := client.Accounting().Forecast(ctx, days)
+	if err != nil {
+		return err
+	}
+	sufficient := forecast.Check(minBalance)
+
+	err = formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatFundsForecast(forecast)
+		case *output.JSONFormatter:
+			return f.FormatFundsForecast(forecast)
+		case *output.YAMLFormatter:
+			return f.FormatFundsForecast(forecast)
+		case *output.CSVFormatter:
+			return f.FormatFundsForecast(forecast)
+		default:
+			return "Unsupported format"
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case forecast.UnknownCosts > 0:
+		return fmt.Errorf("unknown renewal price for %d domain(s), the funds cannot be checked", forecast.UnknownCosts)
+	case !sufficient:
+		return fmt.Errorf("insufficient funds: %.2f remaining after renewals, minimum is %.2f", forecast.Remaining, minBalance)
+	}
+
+	return nil
+}
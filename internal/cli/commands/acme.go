@@ -0,0 +1,303 @@
+package commands
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+const (
+	// acmeChallengeLabel is the label below which ACME DNS-01 challenges are published
+	acmeChallengeLabel = "_acme-challenge."
+	// acmeCheckInterval is the delay between propagation checks
+	acmeCheckInterval = 5 * time.Second
+)
+
+func ACMECommand() *cli.Command {
+	return &cli.Command{
+		Name:  "acme",
+		Usage: "ACME DNS-01 challenge hooks for certbot, lego and acme.sh",
+		Description: `Arguments are accepted in the following forms:
+
+   inwx acme present <fqdn> <value>                 (lego exec, acme.sh)
+   inwx acme present -- <domain> <token> <keyauth>  (lego exec with EXEC_MODE=RAW)
+   inwx acme present                                (certbot, reads CERTBOT_DOMAIN and CERTBOT_VALIDATION)
+
+   The fqdn may be given with or without the _acme-challenge label.`,
+		Subcommands: []*cli.Command{
+			{
+				Name:      "present",
+				Usage:     "Create the challenge TXT record and wait until all authoritative nameservers serve it",
+				ArgsUsage: "[<fqdn> <value>]",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "ttl",
+						Usage: "TTL of the challenge record",
+						Value: 300,
+					},
+					&cli.DurationFlag{
+						Name:  "timeout",
+						Usage: "Maximum time to wait for the record to propagate",
+						Value: 5 * time.Minute,
+					},
+					&cli.BoolFlag{
+						Name:  "no-wait",
+						Usage: "Do not wait for the authoritative nameservers",
+					},
+				},
+				Action: presentACMEChallenge,
+			},
+			{
+				Name:      "cleanup",
+				Usage:     "Remove the challenge TXT record with exactly this value",
+				ArgsUsage: "[<fqdn> <value>]",
+				Action:    cleanupACMEChallenge,
+			},
+		},
+	}
+}
+
+func presentACMEChallenge(c *cli.Context) error {
+	fqdn, value, err := acmeChallengeArgs(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	domain, name, err := utils.InferDomainAndName(client, ctx, fqdn)
+	if err != nil {
+		return err
+	}
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	records, err := acmeChallengeRecords(ctx, dns, domain, name, fqdn)
+	if err != nil {
+		return err
+	}
+
+	if acmeRecordWithValue(records, value) != nil {
+		fmt.Printf("✓ Challenge record %s already exists\n", fqdn)
+	} else {
+		if _, err := dns.CreateRecord(ctx, inwx.DNSRecord{
+			Domain:  domain,
+			Name:    name,
+			Type:    "TXT",
+			Content: value,
+			TTL:     c.Int("ttl"),
+		}); err != nil {
+			return fmt.Errorf("failed to create challenge record %s: %w", fqdn, err)
+		}
+		fmt.Printf("✓ Created challenge record %s\n", fqdn)
+	}
+
+	if c.Bool("no-wait") {
+		return nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, c.Duration("timeout"))
+	defer cancel()
+
+	fmt.Printf("Waiting for authoritative nameservers of %s...\n", domain)
+	return waitForACMEChallenge(waitCtx, dns, domain, name, fqdn)
+}
+
+func cleanupACMEChallenge(c *cli.Context) error {
+	fqdn, value, err := acmeChallengeArgs(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	domain, name, err := utils.InferDomainAndName(client, ctx, fqdn)
+	if err != nil {
+		return err
+	}
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	records, err := acmeChallengeRecords(ctx, dns, domain, name, fqdn)
+	if err != nil {
+		return err
+	}
+
+	// Only remove records with this exact value, so concurrent challenges for the same
+	// name (e.g. a wildcard and its apex) stay intact
+	removed := 0
+	for _, record := range records {
+		if acmeTXTValue(record.Content) != value {
+			continue
+		}
+		if err := dns.DeleteRecord(ctx, record.ID); err != nil {
+			return fmt.Errorf("failed to remove challenge record %s: %w", fqdn, err)
+		}
+		removed++
+	}
+
+	if removed == 0 {
+		fmt.Printf("No challenge record with this value found for %s\n", fqdn)
+		return nil
+	}
+
+	fmt.Printf("✓ Removed challenge record %s\n", fqdn)
+	return nil
+}
+
+// acmeChallengeArgs determines the challenge record name and value from the arguments or
+// from the environment set by certbot
+func acmeChallengeArgs(c *cli.Context) (fqdn, value string, err error) {
+	args := c.Args().Slice()
+
+	switch {
+	case len(args) == 3 || (len(args) > 0 && strings.EqualFold(os.Getenv("EXEC_MODE"), "RAW")):
+		// lego RAW mode passes the domain, token and key authorization
+		if len(args) != 3 {
+			return "", "", fmt.Errorf("expected <domain> <token> <keyauth> in raw mode")
+		}
+		sum := sha256.Sum256([]byte(args[2]))
+		fqdn = args[0]
+		value = base64.RawURLEncoding.EncodeToString(sum[:])
+	case len(args) == 2:
+		fqdn, value = args[0], args[1]
+	case len(args) == 0 && os.Getenv("CERTBOT_DOMAIN") != "":
+		fqdn, value = os.Getenv("CERTBOT_DOMAIN"), os.Getenv("CERTBOT_VALIDATION")
+	default:
+		return "", "", fmt.Errorf("expected <fqdn> <value> or CERTBOT_DOMAIN and CERTBOT_VALIDATION in the environment")
+	}
+
+	fqdn = strings.TrimPrefix(normalizeHostname(fqdn), "*.")
+	if !strings.HasPrefix(fqdn, acmeChallengeLabel) {
+		fqdn = acmeChallengeLabel + fqdn
+	}
+	if err := utils.ValidateDomain(strings.TrimPrefix(fqdn, acmeChallengeLabel)); err != nil {
+		return "", "", fmt.Errorf("invalid domain %s: %w", fqdn, err)
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", "", fmt.Errorf("challenge value must not be empty")
+	}
+
+	return fqdn, value, nil
+}
+
+// acmeChallengeRecords returns the TXT records at exactly the challenge name
+func acmeChallengeRecords(ctx context.Context, dns *inwx.DNSService, domain, name, fqdn string) ([]inwx.DNSRecord, error) {
+	records, err := dns.ListRecords(ctx, inwx.WithDomainFilter(domain), inwx.WithRecordType("TXT"), inwx.WithRecordName(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []inwx.DNSRecord
+	for _, record := range records {
+		if strings.EqualFold(qualifiedRecordName(record), fqdn) {
+			matching = append(matching, record)
+		}
+	}
+	return matching, nil
+}
+
+func acmeRecordWithValue(records []inwx.DNSRecord, value string) *inwx.DNSRecord {
+	for i := range records {
+		if acmeTXTValue(records[i].Content) == value {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+// acmeTXTValue strips the quotes the API may add around TXT content
+func acmeTXTValue(content string) string {
+	return strings.Trim(strings.TrimSpace(content), "\"")
+}
+
+// waitForACMEChallenge waits until all authoritative nameservers serve the challenge
+// records. The expected values are re-read on every attempt, as other challenges for the
+// same name may be added in the meantime.
+func waitForACMEChallenge(ctx context.Context, dns *inwx.DNSService, domain, name, fqdn string) error {
+	for attempt := 1; ; attempt++ {
+		records, err := acmeChallengeRecords(ctx, dns, domain, name, fqdn)
+		if err != nil {
+			return err
+		}
+
+		var expected []string
+		for _, record := range records {
+			expected = append(expected, acmeTXTValue(record.Content))
+		}
+
+		verification := dns.VerifyRecordGroup(ctx, domain, fqdn, "TXT", expected)
+
+		authCount := 0
+		authMatchCount := 0
+		for _, ns := range verification.Nameservers {
+			if ns.Type != "authoritative" {
+				continue
+			}
+			authCount++
+			if ns.Status == "match" {
+				authMatchCount++
+			}
+		}
+
+		if authCount > 0 && authMatchCount == authCount {
+			fmt.Printf("  [Attempt %d] All %d authoritative nameserver(s) updated\n", attempt, authCount)
+			return nil
+		}
+		if authCount == 0 {
+			fmt.Printf("  [Attempt %d] Checking... (no authoritative nameservers found)\n", attempt)
+		} else {
+			fmt.Printf("  [Attempt %d] Propagating... (%d/%d nameserver(s) updated)\n", attempt, authMatchCount, authCount)
+		}
+
+		select {
+		case <-time.After(acmeCheckInterval):
+		case <-ctx.Done():
+			return fmt.Errorf("challenge record %s not served by all authoritative nameservers: %w", fqdn, ctx.Err())
+		}
+	}
+}
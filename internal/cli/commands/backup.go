@@ -199,6 +199,22 @@ func revertBackup(c *cli.Context) error {
 			}
 			fmt.Printf("Successfully deleted created record (backup ID: %s, record ID: %d)\n", entryID, entry.Record.ID)
 
+		case inwx.OperationDomainContacts:
+			// Reassign the previous contact handles, limited to those that were changed
+			previous, err := domainContactsFromBackup(entry, "previous")
+			if err == nil {
+				var changed inwx.DomainContacts
+				changed, err = domainContactsFromBackup(entry, "new")
+				if err == nil {
+					err = client.Domain().SetContacts(ctx, entry.Record.Domain, revertDomainContacts(previous, changed))
+				}
+			}
+			if err != nil {
+				errors = append(errors, fmt.Errorf("failed to revert contact handles for backup %s: %w", entryID, err))
+				continue
+			}
+			fmt.Printf("Successfully reverted contact handles of %s (backup ID: %s)\n", entry.Record.Domain, entryID)
+
 		default:
 			errors = append(errors, fmt.Errorf("backup ID %s: unknown operation type: %s", entryID, entry.Operation))
 			continue
@@ -0,0 +1,875 @@
+package commands
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/cli/output"
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+const (
+	// certValidationTTL is the TTL of the records created for DNS based validation
+	certValidationTTL = 300
+	// certTokenInterval is the delay between checks for the validation token
+	certTokenInterval = 10 * time.Second
+	// certIssueInterval is the delay between checks for the certificate issuance
+	certIssueInterval = 30 * time.Second
+)
+
+func CertCommand() *cli.Command {
+	dryRunFlag := &cli.BoolFlag{
+		Name:    "dry-run",
+		Aliases: []string{"R"},
+		Usage:   "Validate the order in API testing mode without executing it",
+	}
+	waitFlag := &cli.DurationFlag{
+		Name:  "wait",
+		Usage: "How long to wait for issuance when completing DNS validation automatically",
+		Value: 30 * time.Minute,
+	}
+	noValidateFlag := &cli.BoolFlag{
+		Name:  "no-validate",
+		Usage: "Do not create the DNS validation records automatically",
+	}
+	orderFlags := func(csrRequired bool) []cli.Flag {
+		return []cli.Flag{
+			&cli.StringFlag{
+				Name:     "csr",
+				Usage:    "Certificate signing request in PEM format",
+				Required: csrRequired,
+			},
+			&cli.StringFlag{
+				Name:  "validation",
+				Usage: "Domain validation method (dns, email or file)",
+			},
+			&cli.StringFlag{
+				Name:  "email",
+				Usage: "Approver email address for email validation",
+			},
+			&cli.IntFlag{
+				Name:  "owner",
+				Usage: "Contact ID of the certificate owner",
+			},
+			&cli.IntFlag{
+				Name:  "admin",
+				Usage: "Contact ID of the admin contact",
+			},
+			&cli.IntFlag{
+				Name:  "tech",
+				Usage: "Contact ID of the tech contact",
+			},
+			&cli.IntFlag{
+				Name:  "san",
+				Usage: "Maximum number of SANs to purchase (default: included SANs of the product)",
+			},
+			&cli.BoolFlag{
+				Name:  "autorenew",
+				Usage: "Renew the certificate automatically",
+			},
+			waitFlag,
+			noValidateFlag,
+			dryRunFlag,
+		}
+	}
+
+	return &cli.Command{
+		Name:  "cert",
+		Usage: "SSL certificate management",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List certificates",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "all",
+						Aliases: []string{"a"},
+						Usage:   "Include inactive certificates",
+					},
+				},
+				Action: listCertificates,
+			},
+			{
+				Name:      "info",
+				Usage:     "Show certificate details",
+				ArgsUsage: "<certificate-id>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "save",
+						Usage: "Write the certificate and CA chain in PEM format to `FILE`",
+					},
+				},
+				Action: showCertificate,
+			},
+			{
+				Name:   "products",
+				Usage:  "List certificate products with prices",
+				Action: listCertificateProducts,
+			},
+			{
+				Name:  "order",
+				Usage: "Order a certificate for a CSR",
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:     "product",
+						Aliases:  []string{"p"},
+						Usage:    "Product ID or name (see cert products)",
+						Required: true,
+					},
+					&cli.IntFlag{
+						Name:  "period",
+						Usage: "Term in years (1 or 2)",
+						Value: 1,
+					},
+				}, orderFlags(true)...),
+				Action: orderCertificate,
+			},
+			{
+				Name:      "renew",
+				Usage:     "Renew a certificate",
+				ArgsUsage: "<certificate-id>",
+				Flags:     orderFlags(false),
+				Action:    renewCertificate,
+			},
+			{
+				Name:      "reissue",
+				Usage:     "Reissue a certificate for a new CSR",
+				ArgsUsage: "<certificate-id>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "csr",
+						Usage:    "New certificate signing request in PEM format",
+						Required: true,
+					},
+					waitFlag,
+					noValidateFlag,
+				},
+				Action: reissueCertificate,
+			},
+			{
+				Name:      "cancel",
+				Usage:     "Cancel pending certificate requests",
+				ArgsUsage: "<certificate-id>...",
+				Action:    cancelCertificates,
+			},
+			{
+				Name:      "autorenew",
+				Usage:     "Enable or disable the automatic renewal of a certificate",
+				ArgsUsage: "<certificate-id> <on|off>",
+				Action:    setCertificateAutoRenew,
+			},
+		},
+	}
+}
+
+func listCertificates(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	certs, err := client.Certificate().List(ctx, c.Bool("all"))
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatCertificates(certs)
+		case *output.JSONFormatter:
+			return f.FormatCertificates(certs)
+		case *output.YAMLFormatter:
+			return f.FormatCertificates(certs)
+		case *output.CSVFormatter:
+			return f.FormatCertificates(certs)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func showCertificate(c *cli.Context) error {
+	id, err := certificateIDArg(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	cert, err := client.Certificate().Info(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if path := c.String("save"); path != "" {
+		if strings.TrimSpace(cert.Certificate) == "" {
+			return fmt.Errorf("certificate %d has not been issued yet", id)
+		}
+		data := strings.TrimSpace(cert.Certificate) + "\n"
+		if chain := strings.TrimSpace(cert.CAChain); chain != "" {
+			data += chain + "\n"
+		}
+		return writeDocumentFile(c, path, []byte(data))
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatCertificate(cert)
+		case *output.JSONFormatter:
+			return f.FormatCertificate(cert)
+		case *output.YAMLFormatter:
+			return f.FormatCertificate(cert)
+		case *output.CSVFormatter:
+			return f.FormatCertificate(cert)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func listCertificateProducts(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	products, err := client.Certificate().Products(ctx)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatCertificateProducts(products)
+		case *output.JSONFormatter:
+			return f.FormatCertificateProducts(products)
+		case *output.YAMLFormatter:
+			return f.FormatCertificateProducts(products)
+		case *output.CSVFormatter:
+			return f.FormatCertificateProducts(products)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func orderCertificate(c *cli.Context) error {
+	csr, request, err := readCSRFile(c.String("csr"))
+	if err != nil {
+		return err
+	}
+
+	validation := c.String("validation")
+	if validation == "" {
+		validation = inwx.CertificateValidationDNS
+	}
+	order, err := certificateOrderFromFlags(c, validation)
+	if err != nil {
+		return err
+	}
+	order.CSR = csr
+	order.CommonName = request.Subject.CommonName
+	order.Period = c.Int("period")
+	if order.Period != 1 && order.Period != 2 {
+		return fmt.Errorf("invalid period %d (must be 1 or 2 years)", order.Period)
+	}
+
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	certs := client.Certificate(inwx.WithCertificateTesting(dryRun))
+
+	product, err := certs.ResolveProduct(ctx, c.String("product"))
+	if err != nil {
+		return err
+	}
+	order.ProductID = product.ID
+
+	fmt.Printf("Ordering %s for %s (%d year(s))\n", product.Name, csrDomainLabel(request), order.Period)
+	if product.Price1Year > 0 {
+		fmt.Printf("Price: %.2f %s per year\n", product.Price1Year, product.Currency)
+	}
+
+	if !dryRun {
+		confirmed, err := utils.AskSimpleConfirmation("Continue?", c.Bool("yes"))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	result, err := certs.Order(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("✓ Certificate for %s can be ordered (validated in API testing mode)\n", order.CommonName)
+		return nil
+	}
+
+	printCertificateOrderResult("ordered", result)
+	if len(result.Missing) > 0 || c.Bool("no-validate") || !strings.EqualFold(order.ValidationMethod, inwx.CertificateValidationDNS) {
+		return nil
+	}
+
+	return completeDNSValidation(c, client, result.CertificateID)
+}
+
+func renewCertificate(c *cli.Context) error {
+	id, err := certificateIDArg(c)
+	if err != nil {
+		return err
+	}
+
+	order, err := certificateOrderFromFlags(c, c.String("validation"))
+	if err != nil {
+		return err
+	}
+	if path := c.String("csr"); path != "" {
+		csr, request, err := readCSRFile(path)
+		if err != nil {
+			return err
+		}
+		order.CSR = csr
+		order.CommonName = request.Subject.CommonName
+	}
+
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	certs := client.Certificate(inwx.WithCertificateTesting(dryRun))
+
+	current, err := certs.Info(ctx, id)
+	if err != nil {
+		return err
+	}
+	if order.ValidationMethod == "" {
+		order.ValidationMethod = current.ValidationMethod
+	}
+
+	fmt.Printf("Renewing certificate %d (%s, %s)\n", id, current.CommonName, current.Name)
+	if !dryRun {
+		confirmed, err := utils.AskSimpleConfirmation("Continue?", c.Bool("yes"))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	result, err := certs.Renew(ctx, id, order)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("✓ Certificate %d can be renewed (validated in API testing mode)\n", id)
+		return nil
+	}
+
+	printCertificateOrderResult("renewed", result)
+	if c.Bool("no-validate") || !strings.EqualFold(order.ValidationMethod, inwx.CertificateValidationDNS) {
+		return nil
+	}
+
+	return completeDNSValidation(c, client, result.CertificateID)
+}
+
+func reissueCertificate(c *cli.Context) error {
+	id, err := certificateIDArg(c)
+	if err != nil {
+		return err
+	}
+
+	csr, request, err := readCSRFile(c.String("csr"))
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	certs := client.Certificate()
+
+	current, err := certs.Info(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Reissuing certificate %d (%s) for %s\n", id, current.Name, csrDomainLabel(request))
+	confirmed, err := utils.AskSimpleConfirmation("Continue?", c.Bool("yes"))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Operation cancelled")
+		return nil
+	}
+
+	result, err := certs.Reissue(ctx, id, csr)
+	if err != nil {
+		return err
+	}
+	if result.CertificateID == 0 {
+		result.CertificateID = id
+	}
+
+	printCertificateOrderResult("reissued", result)
+	if c.Bool("no-validate") || !strings.EqualFold(current.ValidationMethod, inwx.CertificateValidationDNS) {
+		return nil
+	}
+
+	return completeDNSValidation(c, client, result.CertificateID)
+}
+
+func cancelCertificates(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("at least one certificate ID must be specified")
+	}
+
+	var ids []int
+	for _, arg := range c.Args().Slice() {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid certificate ID %q", arg)
+		}
+		ids = append(ids, id)
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	certs := client.Certificate()
+
+	skipPrompt := c.Bool("yes")
+	failed := 0
+	for _, id := range ids {
+		if !skipPrompt {
+			label := strconv.Itoa(id)
+			if cert, err := certs.Info(ctx, id); err == nil {
+				label = fmt.Sprintf("%d (%s, %s)", id, cert.CommonName, cert.Status)
+			}
+
+			result, err := utils.AskConfirmation(fmt.Sprintf("Cancel certificate %s?", label), false)
+			if err != nil {
+				return err
+			}
+			if result == utils.ConfirmationCancel {
+				fmt.Println("Operation cancelled")
+				break
+			}
+			if result == utils.ConfirmationNo {
+				continue
+			}
+			if result == utils.ConfirmationAll {
+				skipPrompt = true
+			}
+		}
+
+		if err := certs.Cancel(ctx, id); err != nil {
+			log.Error().Err(err).Int("certificate", id).Msg("Failed to cancel certificate")
+			failed++
+			continue
+		}
+		fmt.Printf("✓ Certificate %d cancelled\n", id)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to cancel %d certificate(s)", failed)
+	}
+
+	return nil
+}
+
+func setCertificateAutoRenew(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return fmt.Errorf("usage: inwx cert autorenew <certificate-id> <on|off>")
+	}
+	id, err := strconv.Atoi(c.Args().Get(0))
+	if err != nil {
+		return fmt.Errorf("invalid certificate ID %q", c.Args().Get(0))
+	}
+
+	var enable bool
+	switch strings.ToLower(c.Args().Get(1)) {
+	case "on", "true", "yes", "1":
+		enable = true
+	case "off", "false", "no", "0":
+		enable = false
+	default:
+		return fmt.Errorf("invalid value %q (must be on or off)", c.Args().Get(1))
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	if err := client.Certificate().SetAutoRenew(ctx, id, enable); err != nil {
+		return err
+	}
+
+	if enable {
+		fmt.Printf("✓ Automatic renewal enabled for certificate %d\n", id)
+	} else {
+		fmt.Printf("✓ Automatic renewal disabled for certificate %d\n", id)
+	}
+	return nil
+}
+
+// completeDNSValidation creates the DNS validation records of a certificate in the domains
+// of the account, waits for the certificate to be issued and removes the records again.
+// Records are kept when the certificate is not issued in time, so validation can still
+// succeed later.
+func completeDNSValidation(c *cli.Context, client *inwx.Client, id int) error {
+	wait := c.Duration("wait")
+	shutdown := utils.NewGracefulShutdown()
+	shutdown.Start()
+	ctx := shutdown.Context()
+
+	certs := client.Certificate()
+	deadline := time.Now().Add(wait)
+
+	fmt.Printf("Completing DNS validation for certificate %d...\n", id)
+
+	var records []inwx.CertificateValidationRecord
+	for {
+		cert, err := certs.Info(ctx, id)
+		if err == nil {
+			if cert.IsIssued() {
+				fmt.Printf("✓ Certificate %d issued\n", id)
+				return nil
+			}
+			if records, err = cert.DNSValidationRecords(); err == nil {
+				break
+			}
+			if errors.Is(err, inwx.ErrUnknownValidationToken) {
+				fmt.Printf("⚠️  Create the DNS validation records for this token as described by the certificate authority:\n%s\n", cert.ValidationToken)
+				fmt.Printf("Check the status with: inwx cert info %d\n", id)
+				return fmt.Errorf("cannot create the DNS validation records for certificate %d automatically", id)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("no DNS validation data for certificate %d: %w", id, err)
+		}
+		log.Debug().Err(err).Int("certificate", id).Msg("Waiting for validation data")
+
+		select {
+		case <-time.After(certTokenInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	var created []inwx.DNSRecord
+	manual := 0
+	for _, rec := range records {
+		domain, name, err := utils.InferDomainAndName(client, ctx, rec.Name)
+		if err != nil {
+			fmt.Printf("⚠️  %s is not managed in this account, create it manually: %s %s %s\n", rec.Name, rec.Name, rec.Type, rec.Content)
+			manual++
+			continue
+		}
+
+		existing, err := dns.ListRecords(ctx, inwx.WithDomainFilter(domain), inwx.WithRecordType(rec.Type), inwx.WithRecordName(name))
+		if err != nil {
+			return err
+		}
+		if containsRecordContent(existing, rec.Content) {
+			fmt.Printf("✓ Validation record %s %s already exists\n", rec.Name, rec.Type)
+			continue
+		}
+
+		record, err := dns.CreateRecord(ctx, inwx.DNSRecord{
+			Domain:  domain,
+			Name:    name,
+			Type:    rec.Type,
+			Content: rec.Content,
+			TTL:     certValidationTTL,
+		})
+		if err != nil {
+			cleanupValidationRecords(dns, created)
+			return fmt.Errorf("failed to create validation record %s: %w", rec.Name, err)
+		}
+		created = append(created, *record)
+		fmt.Printf("✓ Created validation record %s %s %s\n", rec.Name, rec.Type, rec.Content)
+	}
+
+	if len(created) == 0 && manual > 0 {
+		fmt.Printf("Check the status with: inwx cert info %d\n", id)
+		return nil
+	}
+
+	fmt.Printf("Waiting up to %s for certificate %d to be issued...\n", wait, id)
+	for {
+		select {
+		case <-time.After(certIssueInterval):
+		case <-ctx.Done():
+			printKeptValidationRecords(created)
+			return ctx.Err()
+		}
+
+		cert, err := certs.Info(ctx, id)
+		if err != nil {
+			log.Debug().Err(err).Int("certificate", id).Msg("Failed to check certificate status")
+		} else if cert.IsIssued() {
+			fmt.Printf("✓ Certificate %d issued\n", id)
+			cleanupValidationRecords(dns, created)
+			return nil
+		} else if cert.IsFailed() {
+			cleanupValidationRecords(dns, created)
+			return fmt.Errorf("certificate %d was not issued (status: %s)", id, cert.Status)
+		}
+
+		if time.Now().After(deadline) {
+			printKeptValidationRecords(created)
+			return fmt.Errorf("certificate %d was not issued within %s", id, wait)
+		}
+	}
+}
+
+// cleanupValidationRecords deletes the validation records created for a certificate
+func cleanupValidationRecords(dns *inwx.DNSService, records []inwx.DNSRecord) {
+	// Use a fresh context so that records are also removed after an interrupt
+	ctx := context.Background()
+	for _, record := range records {
+		if err := dns.DeleteRecord(ctx, record.ID); err != nil {
+			log.Error().Err(err).Int("id", record.ID).Msg("Failed to remove validation record")
+			continue
+		}
+		fmt.Printf("✓ Removed validation record %s %s\n", qualifiedRecordName(record), record.Type)
+	}
+}
+
+func printKeptValidationRecords(records []inwx.DNSRecord) {
+	if len(records) == 0 {
+		return
+	}
+	fmt.Println("⚠️  Keeping the validation records until the certificate is issued:")
+	for _, record := range records {
+		fmt.Printf("  %s %s (remove with: inwx dns delete --id %d)\n", qualifiedRecordName(record), record.Type, record.ID)
+	}
+}
+
+func qualifiedRecordName(record inwx.DNSRecord) string {
+	if record.Name == "" || record.Name == "@" {
+		return record.Domain
+	}
+	return record.Name + "." + record.Domain
+}
+
+func containsRecordContent(records []inwx.DNSRecord, content string) bool {
+	for _, record := range records {
+		if strings.EqualFold(strings.TrimSuffix(record.Content, "."), strings.TrimSuffix(content, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// certificateOrderFromFlags builds the order parameters shared by order and renew
+func certificateOrderFromFlags(c *cli.Context, validation string) (inwx.CertificateOrder, error) {
+	order := inwx.CertificateOrder{
+		ValidationMethod: strings.ToUpper(validation),
+		ValidationEmail:  c.String("email"),
+		Owner:            c.Int("owner"),
+		Admin:            c.Int("admin"),
+		Tech:             c.Int("tech"),
+		NumberOfSAN:      c.Int("san"),
+	}
+
+	if order.ValidationMethod != "" && !inwx.IsValidCertificateValidationMethod(order.ValidationMethod) {
+		return order, fmt.Errorf("invalid validation method %q (must be one of %s)", validation, strings.ToLower(strings.Join(inwx.CertificateValidationMethods, ", ")))
+	}
+	if order.ValidationMethod == inwx.CertificateValidationEmail && order.ValidationEmail == "" {
+		return order, fmt.Errorf("--email is required for email validation")
+	}
+	if c.IsSet("autorenew") {
+		autoRenew := c.Bool("autorenew")
+		order.AutoRenew = &autoRenew
+	}
+
+	return order, nil
+}
+
+// readCSRFile reads a PEM encoded certificate signing request and verifies its signature
+func readCSRFile(path string) (string, *x509.CertificateRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read CSR: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || !strings.HasSuffix(block.Type, "CERTIFICATE REQUEST") {
+		return "", nil, fmt.Errorf("%s does not contain a PEM encoded certificate request", path)
+	}
+
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid CSR in %s: %w", path, err)
+	}
+	if err := request.CheckSignature(); err != nil {
+		return "", nil, fmt.Errorf("invalid CSR signature in %s: %w", path, err)
+	}
+	if request.Subject.CommonName == "" && len(request.DNSNames) == 0 {
+		return "", nil, fmt.Errorf("CSR in %s contains no domain names", path)
+	}
+	if request.Subject.CommonName == "" {
+		request.Subject.CommonName = request.DNSNames[0]
+	}
+
+	return string(pem.EncodeToMemory(block)), request, nil
+}
+
+// csrDomainLabel lists the common name and additional SANs of a CSR
+func csrDomainLabel(request *x509.CertificateRequest) string {
+	names := []string{request.Subject.CommonName}
+	for _, name := range request.DNSNames {
+		if !strings.EqualFold(name, request.Subject.CommonName) {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func certificateIDArg(c *cli.Context) (int, error) {
+	if c.NArg() != 1 {
+		return 0, fmt.Errorf("exactly one certificate ID must be specified")
+	}
+	id, err := strconv.Atoi(c.Args().First())
+	if err != nil {
+		return 0, fmt.Errorf("invalid certificate ID %q", c.Args().First())
+	}
+	return id, nil
+}
+
+func printCertificateOrderResult(action string, result *inwx.CertificateOrderResult) {
+	fmt.Printf("✓ Certificate %s (ID: %d, status: %s)\n", action, result.CertificateID, result.Status)
+	if result.Price > 0 {
+		fmt.Printf("  Price: %.2f\n", result.Price)
+	}
+	if len(result.Missing) > 0 {
+		fmt.Printf("⚠️  The order is incomplete, missing: %s\n", strings.Join(result.Missing, ", "))
+	}
+}
@@ -0,0 +1,801 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/cli/output"
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+func ContactCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "contact",
+		Usage: "Contact handle management",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List contact handles",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "search",
+						Aliases: []string{"s"},
+						Usage:   "Search string (name, organisation, email, ...)",
+					},
+				},
+				Action: listContacts,
+			},
+			{
+				Name:      "info",
+				Usage:     "Show contact handle details",
+				ArgsUsage: "<id>",
+				Action:    showContact,
+			},
+			{
+				Name:  "create",
+				Usage: "Create a contact handle",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "file",
+						Aliases: []string{"f"},
+						Usage:   "Read contact data from a YAML or JSON `FILE`",
+					},
+					&cli.BoolFlag{
+						Name:    "interactive",
+						Aliases: []string{"i"},
+						Usage:   "Interactive mode - prompts for all contact details",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the contact in API testing mode without creating it",
+					},
+				},
+				Action: createContact,
+			},
+			{
+				Name:      "update",
+				Usage:     "Update a contact handle",
+				ArgsUsage: "<id>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "file",
+						Aliases: []string{"f"},
+						Usage:   "Read changed fields from a YAML or JSON `FILE`",
+					},
+					&cli.BoolFlag{
+						Name:    "interactive",
+						Aliases: []string{"i"},
+						Usage:   "Interactive mode - prompts for all fields, prefilled with current values",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the update in API testing mode without executing it",
+					},
+				},
+				Action: updateContact,
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete contact handle(s)",
+				ArgsUsage: "<id...>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the deletion in API testing mode without executing it",
+					},
+				},
+				Action: deleteContacts,
+			},
+			{
+				Name:      "log",
+				Usage:     "Show the change log of a contact handle (or all handles)",
+				ArgsUsage: "[id]",
+				Action:    showContactLog,
+			},
+			{
+				Name:      "verify",
+				Usage:     "Send the contact verification email for contact handle(s)",
+				ArgsUsage: "<id...>",
+				Action:    sendContactVerification,
+			},
+			{
+				Name:  "verify-bulk",
+				Usage: "Send verification emails for all matching contact handles",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "search",
+						Aliases: []string{"s"},
+						Usage:   "Only contacts matching the search string",
+					},
+					&cli.StringSliceFlag{
+						Name:  "status",
+						Usage: "Only contacts with verification status(es) (CONFIRMED, AWAIT_CONFIRMATION, NONE, TO_NOTIFY)",
+					},
+				},
+				Action: sendBulkContactVerification,
+			},
+		},
+	}
+}
+
+// parseContactIDs converts positional arguments into contact handle IDs
+func parseContactIDs(args []string) ([]int, error) {
+	var ids []int
+	for _, arg := range parseCommaSeparatedValues(args) {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid contact ID: %s", arg)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("at least one contact ID must be specified")
+	}
+	return ids, nil
+}
+
+// validateContact checks the fields of a contact; required fields are only enforced for new contacts
+func validateContact(contact inwx.Contact, isNew bool) error {
+	if isNew {
+		required := map[string]string{
+			"type":   contact.Type,
+			"name":   contact.Name,
+			"street": contact.Street,
+			"city":   contact.City,
+			"pc":     contact.PostalCode,
+			"cc":     contact.CountryCode,
+			"voice":  contact.Phone,
+			"email":  contact.Email,
+		}
+		var missing []string
+		for _, field := range []string{"type", "name", "street", "city", "pc", "cc", "voice", "email"} {
+			if strings.TrimSpace(required[field]) == "" {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("missing required field(s): %s", strings.Join(missing, ", "))
+		}
+	}
+
+	if contact.Type != "" && !inwx.IsValidContactType(contact.Type) {
+		return fmt.Errorf("invalid contact type %q (must be one of %s)", contact.Type, strings.Join(inwx.ContactTypes, ", "))
+	}
+	if contact.Email != "" {
+		if err := utils.ValidateEmail(contact.Email); err != nil {
+			return fmt.Errorf("invalid email: %w", err)
+		}
+	}
+	if contact.Phone != "" {
+		if err := utils.ValidatePhone(contact.Phone); err != nil {
+			return fmt.Errorf("invalid phone: %w", err)
+		}
+	}
+	if contact.Fax != "" {
+		if err := utils.ValidatePhone(contact.Fax); err != nil {
+			return fmt.Errorf("invalid fax: %w", err)
+		}
+	}
+	if contact.CountryCode != "" {
+		if err := utils.ValidateCountryCode(contact.CountryCode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// clearValue is entered at an optional prompt to clear the current value
+const clearValue = "-"
+
+// askContactFields prompts for all contact fields, using the current values as defaults
+func askContactFields(contact *inwx.Contact, isNew bool) error {
+	if isNew {
+		contactType := contact.Type
+		if contactType == "" {
+			contactType = inwx.ContactTypePerson
+		}
+		err := survey.AskOne(&survey.Select{
+			Message: "Contact type:",
+			Options: inwx.ContactTypes,
+			Default: strings.ToUpper(contactType),
+		}, &contact.Type)
+		if err != nil {
+			return err
+		}
+	}
+
+	validateWith := func(check func(string) error, required bool) survey.AskOpt {
+		return survey.WithValidator(func(val interface{}) error {
+			str, ok := val.(string)
+			if !ok {
+				return fmt.Errorf("invalid input")
+			}
+			if str == "" || (!required && str == clearValue) {
+				if required {
+					return fmt.Errorf("value is required")
+				}
+				return nil
+			}
+			if check != nil {
+				return check(str)
+			}
+			return nil
+		})
+	}
+
+	prompts := []struct {
+		message  string
+		help     string
+		value    *string
+		required bool
+		check    func(string) error
+	}{
+		{"Name (first and last name):", "", &contact.Name, true, nil},
+		{"Organisation:", "Leave empty for private persons", &contact.Org, false, nil},
+		{"Street:", "", &contact.Street, true, nil},
+		{"Postal code:", "", &contact.PostalCode, true, nil},
+		{"City:", "", &contact.City, true, nil},
+		{"State/province:", "Optional", &contact.State, false, nil},
+		{"Country code:", "Two-letter ISO 3166-1 code (e.g., DE)", &contact.CountryCode, true, utils.ValidateCountryCode},
+		{"Phone:", "International format, e.g., +49.30123456", &contact.Phone, true, utils.ValidatePhone},
+		{"Fax:", "Optional, international format", &contact.Fax, false, utils.ValidatePhone},
+		{"Email:", "", &contact.Email, true, utils.ValidateEmail},
+		{"Remarks:", "Optional internal note", &contact.Remarks, false, nil},
+	}
+
+	for _, p := range prompts {
+		help := p.help
+		if !p.required && *p.value != "" {
+			// An empty answer keeps the default, so optional values are cleared with a marker
+			help = strings.TrimPrefix(help+", enter "+clearValue+" to clear", ", ")
+		}
+		err := survey.AskOne(&survey.Input{
+			Message: p.message,
+			Help:    help,
+			Default: *p.value,
+		}, p.value, validateWith(p.check, p.required))
+		if err != nil {
+			return err
+		}
+		if !p.required && *p.value == clearValue {
+			*p.value = ""
+		}
+	}
+
+	contact.CountryCode = strings.ToUpper(contact.CountryCode)
+	return nil
+}
+
+// printContactPreview shows the contact data before it is sent to the API
+func printContactPreview(title string, contact inwx.Contact, cleared ...string) {
+	fmt.Println("\n" + strings.Repeat("─", 50))
+	fmt.Println(title)
+	fmt.Println(strings.Repeat("─", 50))
+	fields := [][3]string{
+		{"type", "Type", contact.Type},
+		{"name", "Name", contact.Name},
+		{"org", "Org", contact.Org},
+		{"street", "Street", contact.Street},
+		{"pc", "Postal code", contact.PostalCode},
+		{"city", "City", contact.City},
+		{"sp", "State", contact.State},
+		{"cc", "Country", contact.CountryCode},
+		{"voice", "Phone", contact.Phone},
+		{"fax", "Fax", contact.Fax},
+		{"email", "Email", contact.Email},
+		{"remarks", "Remarks", contact.Remarks},
+	}
+	for _, field := range fields {
+		switch {
+		case field[2] != "":
+			fmt.Printf("  %-12s %s\n", field[1]+":", field[2])
+		case utils.ContainsString(cleared, field[0]):
+			fmt.Printf("  %-12s (cleared)\n", field[1]+":")
+		}
+	}
+	fmt.Println(strings.Repeat("─", 50))
+}
+
+func listContacts(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	contacts, err := client.Contact().List(ctx, c.String("search"))
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatContacts(contacts)
+		case *output.JSONFormatter:
+			return f.FormatContacts(contacts)
+		case *output.YAMLFormatter:
+			return f.FormatContacts(contacts)
+		case *output.CSVFormatter:
+			return f.FormatContacts(contacts)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func showContact(c *cli.Context) error {
+	ids, err := parseContactIDs(c.Args().Slice())
+	if err != nil {
+		return err
+	}
+	if len(ids) != 1 {
+		return fmt.Errorf("exactly one contact ID must be specified")
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	contact, err := client.Contact().Info(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatContact(contact)
+		case *output.JSONFormatter:
+			return f.FormatContact(contact)
+		case *output.YAMLFormatter:
+			return f.FormatContact(contact)
+		case *output.CSVFormatter:
+			return f.FormatContact(contact)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func createContact(c *cli.Context) error {
+	dryRun := c.Bool("dry-run")
+	file := c.String("file")
+
+	var contact inwx.Contact
+	if file != "" {
+		if err := decodeInputFile(file, &contact); err != nil {
+			return err
+		}
+	}
+
+	// Start interactive mode when no input file is given
+	interactive := c.Bool("interactive") || file == ""
+	if interactive {
+		fmt.Println("\n✨ Interactive Contact Handle Creation")
+		if err := askContactFields(&contact, true); err != nil {
+			return err
+		}
+	}
+
+	contact.Type = strings.ToUpper(contact.Type)
+	if err := validateContact(contact, true); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	if interactive {
+		printContactPreview("📋 Contact Preview:", contact)
+		var confirm bool
+		err := survey.AskOne(&survey.Confirm{
+			Message: "Create this contact handle?",
+			Default: true,
+		}, &confirm)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			fmt.Println("❌ Contact creation cancelled")
+			return nil
+		}
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	id, err := client.Contact(inwx.WithContactTesting(dryRun)).Create(ctx, contact)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Println("✓ Contact data validated in API testing mode - no handle was created")
+		return nil
+	}
+
+	fmt.Printf("✓ Contact handle created (ID: %d)\n", id)
+	return nil
+}
+
+func updateContact(c *cli.Context) error {
+	ids, err := parseContactIDs(c.Args().Slice())
+	if err != nil {
+		return err
+	}
+	if len(ids) != 1 {
+		return fmt.Errorf("exactly one contact ID must be specified")
+	}
+	id := ids[0]
+	dryRun := c.Bool("dry-run")
+	file := c.String("file")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	contacts := client.Contact(inwx.WithContactTesting(dryRun))
+
+	var changes inwx.Contact
+	var cleared []string
+	if file != "" {
+		if err := decodeInputFile(file, &changes); err != nil {
+			return err
+		}
+		if changes.Type != "" {
+			log.Warn().Msg("The contact type cannot be changed and is ignored")
+			changes.Type = ""
+		}
+		if cleared, err = clearedContactFields(file); err != nil {
+			return err
+		}
+	}
+
+	interactive := c.Bool("interactive") || file == ""
+	if interactive {
+		current, err := contacts.Info(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get contact %d: %w", id, err)
+		}
+
+		fmt.Printf("\n✨ Interactive Contact Handle Update (ID: %d)\n", id)
+		edited := *current
+		if err := askContactFields(&edited, false); err != nil {
+			return err
+		}
+		changes, cleared = diffContact(*current, edited)
+	}
+
+	if changes == (inwx.Contact{}) && len(cleared) == 0 {
+		fmt.Println("No changes to apply")
+		return nil
+	}
+
+	if err := validateContact(changes, false); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	if interactive {
+		printContactPreview("📋 Changed Fields:", changes, cleared...)
+		var confirm bool
+		err := survey.AskOne(&survey.Confirm{
+			Message: "Apply these changes?",
+			Default: true,
+		}, &confirm)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			fmt.Println("❌ Changes cancelled")
+			return nil
+		}
+	}
+
+	if err := contacts.Update(ctx, id, changes, cleared...); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Println("✓ Changes validated in API testing mode - contact was not modified")
+		return nil
+	}
+
+	fmt.Printf("✓ Contact handle %d updated\n", id)
+	return nil
+}
+
+// diffContact returns a contact containing only the fields that differ between current and
+// edited, and the API names of the optional fields that were emptied
+func diffContact(current, edited inwx.Contact) (inwx.Contact, []string) {
+	var changes inwx.Contact
+	var cleared []string
+	pick := func(field, old, new string) string {
+		if old == new {
+			return ""
+		}
+		if new == "" && inwx.IsClearableContactField(field) {
+			cleared = append(cleared, field)
+		}
+		return new
+	}
+
+	changes.Name = pick("name", current.Name, edited.Name)
+	changes.Org = pick("org", current.Org, edited.Org)
+	changes.Street = pick("street", current.Street, edited.Street)
+	changes.City = pick("city", current.City, edited.City)
+	changes.PostalCode = pick("pc", current.PostalCode, edited.PostalCode)
+	changes.State = pick("sp", current.State, edited.State)
+	changes.CountryCode = pick("cc", current.CountryCode, edited.CountryCode)
+	changes.Phone = pick("voice", current.Phone, edited.Phone)
+	changes.Fax = pick("fax", current.Fax, edited.Fax)
+	changes.Email = pick("email", current.Email, edited.Email)
+	changes.Remarks = pick("remarks", current.Remarks, edited.Remarks)
+
+	return changes, cleared
+}
+
+// clearedContactFields returns the optional fields set to an empty value in a contact
+// update file, which are cleared by the update
+func clearedContactFields(file string) ([]string, error) {
+	var fields map[string]interface{}
+	if err := decodeInputFile(file, &fields); err != nil {
+		return nil, err
+	}
+
+	var cleared []string
+	for _, field := range inwx.ContactClearableFields {
+		value, ok := fields[field]
+		if !ok {
+			continue
+		}
+		if s, isString := value.(string); value == nil || (isString && strings.TrimSpace(s) == "") {
+			cleared = append(cleared, field)
+		}
+	}
+	return cleared, nil
+}
+
+func deleteContacts(c *cli.Context) error {
+	ids, err := parseContactIDs(c.Args().Slice())
+	if err != nil {
+		return err
+	}
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	contacts := client.Contact(inwx.WithContactTesting(dryRun))
+
+	skipPrompt := c.Bool("yes") || dryRun
+	failed := 0
+	for _, id := range ids {
+		if !skipPrompt {
+			label := strconv.Itoa(id)
+			if contact, err := contacts.Info(ctx, id); err == nil {
+				label = fmt.Sprintf("%d (%s, %s)", id, contact.Name, contact.Email)
+			}
+
+			result, err := utils.AskConfirmation(fmt.Sprintf("Delete contact %s?", label), false)
+			if err != nil {
+				return err
+			}
+			if result == utils.ConfirmationCancel {
+				fmt.Println("Operation cancelled")
+				break
+			}
+			if result == utils.ConfirmationNo {
+				continue
+			}
+			if result == utils.ConfirmationAll {
+				skipPrompt = true
+			}
+		}
+
+		if err := contacts.Delete(ctx, id); err != nil {
+			log.Error().Err(err).Int("id", id).Msg("Failed to delete contact")
+			failed++
+			continue
+		}
+
+		if dryRun {
+			fmt.Printf("✓ Contact %d can be deleted (validated in API testing mode)\n", id)
+		} else {
+			fmt.Printf("✓ Contact %d deleted\n", id)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d contact(s)", failed)
+	}
+
+	return nil
+}
+
+func showContactLog(c *cli.Context) error {
+	var id int
+	if c.NArg() > 0 {
+		ids, err := parseContactIDs(c.Args().Slice())
+		if err != nil {
+			return err
+		}
+		id = ids[0]
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	entries, err := client.Contact().Log(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatContactLog(entries)
+		case *output.JSONFormatter:
+			return f.FormatContactLog(entries)
+		case *output.YAMLFormatter:
+			return f.FormatContactLog(entries)
+		case *output.CSVFormatter:
+			return f.FormatContactLog(entries)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func sendContactVerification(c *cli.Context) error {
+	ids, err := parseContactIDs(c.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	contacts := client.Contact()
+	failed := 0
+	for _, id := range ids {
+		if err := contacts.SendVerification(ctx, id); err != nil {
+			log.Error().Err(err).Int("id", id).Msg("Failed to send contact verification")
+			failed++
+			continue
+		}
+		fmt.Printf("✓ Verification email sent for contact %d\n", id)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to send verification for %d contact(s)", failed)
+	}
+
+	return nil
+}
+
+func sendBulkContactVerification(c *cli.Context) error {
+	search := c.String("search")
+	statuses := parseCommaSeparatedValues(c.StringSlice("status"))
+	for i, status := range statuses {
+		statuses[i] = strings.ToUpper(status)
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	confirmed, err := utils.AskSimpleConfirmation("Send verification emails to all matching contacts?", c.Bool("yes"))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Operation cancelled")
+		return nil
+	}
+
+	if err := client.Contact().SendBulkVerification(ctx, search, statuses); err != nil {
+		return err
+	}
+
+	fmt.Println("✓ Bulk contact verification triggered")
+	return nil
+}
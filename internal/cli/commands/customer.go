@@ -0,0 +1,340 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/cli/output"
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+func CustomerCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "customer",
+		Usage: "Customer data, data exports and documents",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "info",
+				Usage:  "Show customer data",
+				Action: showCustomer,
+			},
+			{
+				Name:  "update",
+				Usage: "Update customer data",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "email", Usage: "Email address"},
+					&cli.StringFlag{Name: "email-billing", Usage: "Email address for invoices"},
+					&cli.StringFlag{Name: "email-automated", Usage: "Email address for automated emails"},
+					&cli.StringFlag{Name: "wdrp-email", Usage: "Email address for WDRP notifications"},
+					&cli.StringFlag{Name: "renewal-report-email", Usage: "Email address for renewal reports"},
+					&cli.StringFlag{Name: "org", Usage: "Organisation"},
+					&cli.StringFlag{Name: "street", Usage: "Street"},
+					&cli.StringFlag{Name: "pc", Usage: "Postal code"},
+					&cli.StringFlag{Name: "city", Usage: "City"},
+					&cli.StringFlag{Name: "cc", Usage: "Country code"},
+					&cli.StringFlag{Name: "voice", Usage: "Phone number (e.g., +49.30123456)"},
+					&cli.StringFlag{Name: "fax", Usage: "Fax number"},
+					&cli.StringFlag{Name: "www", Usage: "Web address"},
+					&cli.StringFlag{Name: "vat-no", Usage: "VAT identification number"},
+					&cli.StringFlag{Name: "language", Usage: "Language (e.g., en, de)"},
+					&cli.StringFlag{Name: "invoice-text", Usage: "Supplementary text printed on invoices"},
+					&cli.BoolFlag{Name: "summary-invoice", Usage: "Receive summary invoices (use --summary-invoice=false to disable)"},
+					&cli.BoolFlag{Name: "invoice-pdf", Usage: "Receive invoices as PDF (use --invoice-pdf=false to disable)"},
+					&cli.BoolFlag{Name: "invoice-xml", Usage: "Receive invoices as XML (use --invoice-xml=false to disable)"},
+					&cli.BoolFlag{Name: "disable-premium", Usage: "Disable premium domains (use --disable-premium=false to enable)"},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the update in API testing mode without executing it",
+					},
+				},
+				Action: updateCustomer,
+			},
+			{
+				Name:   "export-request",
+				Usage:  "Request an export of all customer data (GDPR)",
+				Action: requestCustomerExport,
+			},
+			{
+				Name:  "downloads",
+				Usage: "List prepared downloads such as data exports",
+				Description: "Lists the download tokens with their status and expiry date. The API offers no call to fetch\n" +
+					"a download by its token, so the files are retrieved in the INWX web interface.",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "type",
+						Usage: "Only list downloads of this type (e.g., gdpr)",
+					},
+				},
+				Action: listCustomerDownloads,
+			},
+			{
+				Name:      "download",
+				Aliases:   []string{"document"},
+				Usage:     "Save the registry document of a domain or a GDPR document (" + strings.Join(inwx.CustomerDocuments, ", ") + ") as PDF",
+				ArgsUsage: "<domain|document>",
+				Description: "Download tokens listed by 'inwx customer downloads' are not accepted, as the API offers no\n" +
+					"call to fetch them; those files are retrieved in the INWX web interface.",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "pdf",
+						Usage: "Write the PDF to `FILE` (default: <domain|document>.pdf)",
+					},
+				},
+				Action: saveCustomerDocument,
+			},
+		},
+	}
+}
+
+func showCustomer(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	customer, err := client.Customer().Info(ctx)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatCustomer(customer)
+		case *output.JSONFormatter:
+			return f.FormatCustomer(customer)
+		case *output.YAMLFormatter:
+			return f.FormatCustomer(customer)
+		case *output.CSVFormatter:
+			return f.FormatCustomer(customer)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func updateCustomer(c *cli.Context) error {
+	fields := make(map[string]interface{})
+
+	stringFields := map[string]string{
+		"email":                "email",
+		"email-billing":        "emailBilling",
+		"email-automated":      "emailAutomated",
+		"wdrp-email":           "wdrpEmail",
+		"renewal-report-email": "renewalReportEmail",
+		"org":                  "org",
+		"street":               "street",
+		"pc":                   "pc",
+		"city":                 "city",
+		"cc":                   "cc",
+		"voice":                "voice",
+		"fax":                  "fax",
+		"www":                  "www",
+		"vat-no":               "vatNo",
+		"language":             "language",
+		"invoice-text":         "supplimentinvoicetext",
+	}
+	for flag, key := range stringFields {
+		if c.IsSet(flag) {
+			fields[key] = c.String(flag)
+		}
+	}
+
+	boolFields := map[string]string{
+		"summary-invoice": "summaryInvoice",
+		"invoice-pdf":     "invoicePdf",
+		"invoice-xml":     "invoiceXml",
+		"disable-premium": "disablepremium",
+	}
+	for flag, key := range boolFields {
+		if c.IsSet(flag) {
+			fields[key] = c.Bool(flag)
+		}
+	}
+
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields to update specified")
+	}
+
+	for _, key := range []string{"email", "emailBilling", "emailAutomated", "wdrpEmail", "renewalReportEmail"} {
+		if value, ok := fields[key].(string); ok && value != "" {
+			if err := utils.ValidateEmail(value); err != nil {
+				return fmt.Errorf("invalid %s: %w", key, err)
+			}
+		}
+	}
+	for _, key := range []string{"voice", "fax"} {
+		if value, ok := fields[key].(string); ok && value != "" {
+			if err := utils.ValidatePhone(value); err != nil {
+				return err
+			}
+		}
+	}
+	if value, ok := fields["cc"].(string); ok {
+		if err := utils.ValidateCountryCode(value); err != nil {
+			return err
+		}
+		fields["cc"] = strings.ToUpper(value)
+	}
+
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	if err := client.Customer(inwx.WithCustomerTesting(dryRun)).Update(ctx, fields); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if dryRun {
+		fmt.Printf("✓ Update of %s validated in API testing mode - customer data was not modified\n", strings.Join(keys, ", "))
+		return nil
+	}
+
+	fmt.Printf("✓ Updated %s\n", strings.Join(keys, ", "))
+	return nil
+}
+
+func requestCustomerExport(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	if err := client.Customer().RequestDataExport(ctx); err != nil {
+		return err
+	}
+
+	fmt.Println("✓ Data export requested")
+	fmt.Println("  The export is listed by 'inwx customer downloads --type gdpr' once it has been prepared")
+	fmt.Println("  Download it in the INWX web interface, the API cannot fetch it")
+	return nil
+}
+
+func listCustomerDownloads(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	downloads, err := client.Customer().ListDownloads(ctx, c.String("type"))
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatCustomerDownloads(downloads)
+		case *output.JSONFormatter:
+			return f.FormatCustomerDownloads(downloads)
+		case *output.YAMLFormatter:
+			return f.FormatCustomerDownloads(downloads)
+		case *output.CSVFormatter:
+			return f.FormatCustomerDownloads(downloads)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func saveCustomerDocument(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one domain or document name must be specified")
+	}
+	id := strings.ToLower(strings.TrimSuffix(c.Args().First(), "."))
+
+	isDocument := utils.ContainsString(inwx.CustomerDocuments, id)
+	if !isDocument {
+		if err := utils.ValidateDomain(id); err != nil {
+			return fmt.Errorf("%s is neither a domain nor a document (%s)", id, strings.Join(inwx.CustomerDocuments, ", "))
+		}
+	}
+
+	path := c.String("pdf")
+	if path == "" {
+		path = id + ".pdf"
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	var data []byte
+	if isDocument {
+		data, err = client.Customer().Document(ctx, id)
+	} else {
+		data, err = client.Customer().DomainDocument(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeDocumentFile(c, path, data)
+}
@@ -0,0 +1,260 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+func dnsTLSACommand() *cli.Command {
+	return &cli.Command{
+		Name:  "tlsa",
+		Usage: "Manage TLSA records for DANE",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "generate",
+				Usage: "Publish TLSA records computed from a certificate",
+				Description: "The record is computed locally from the certificate and published at\n" +
+					"   _<port>._<protocol>.<host>. Other TLSA records of that name are removed. Use\n" +
+					"   --rollover with the next certificate or key to publish it alongside the current one\n" +
+					"   before switching, and run the command again without it afterwards.",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "cert",
+						Usage:    "PEM file with the certificate or chain, e.g. fullchain.pem",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "rollover",
+						Usage: "PEM file with the next certificate or key to publish as well",
+					},
+					&cli.IntFlag{
+						Name:  "usage",
+						Usage: "Certificate usage (0 = PKIX-TA, 1 = PKIX-EE, 2 = DANE-TA, 3 = DANE-EE)",
+						Value: 3,
+					},
+					&cli.IntFlag{
+						Name:  "selector",
+						Usage: "Selector (0 = full certificate, 1 = public key)",
+						Value: 1,
+					},
+					&cli.IntFlag{
+						Name:  "match",
+						Usage: "Matching type (0 = full, 1 = SHA-256, 2 = SHA-512)",
+						Value: 1,
+					},
+					&cli.IntFlag{
+						Name:     "port",
+						Usage:    "Port of the service, e.g. 25 or 443",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "protocol",
+						Usage: "Transport protocol of the service",
+						Value: "tcp",
+					},
+					&cli.StringFlag{
+						Name:     "host",
+						Usage:    "Host name of the service, e.g. mail.example.com",
+						Required: true,
+					},
+					&cli.IntFlag{
+						Name:  "ttl",
+						Usage: "TTL of the records",
+						Value: 3600,
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Show the changes without applying them",
+					},
+				},
+				Action: generateTLSA,
+			},
+		},
+	}
+}
+
+func dnsSSHFPCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "sshfp",
+		Usage: "Manage SSHFP records",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "generate",
+				Usage:     "Publish SSHFP records computed from SSH host keys",
+				ArgsUsage: "[key files...]",
+				Description: "The fingerprints are computed locally from OpenSSH public keys, e.g. the\n" +
+					"   ssh_host_*_key.pub files or the output of ssh-keyscan. Other SSHFP records of the\n" +
+					"   host are removed. Glob patterns in --keys are expanded.",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "keys",
+						Usage: "Public key files or glob patterns (can be repeated)",
+					},
+					&cli.StringFlag{
+						Name:     "host",
+						Usage:    "Host name to publish the records for",
+						Required: true,
+					},
+					&cli.IntSliceFlag{
+						Name:  "fingerprint-type",
+						Usage: "Fingerprint types to publish (1 = SHA-1, 2 = SHA-256)",
+						Value: cli.NewIntSlice(2),
+					},
+					&cli.IntFlag{
+						Name:  "ttl",
+						Usage: "TTL of the records",
+						Value: 3600,
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Show the changes without applying them",
+					},
+				},
+				Action: generateSSHFP,
+			},
+		},
+	}
+}
+
+func generateTLSA(c *cli.Context) error {
+	host := normalizeHostname(c.String("host"))
+	if err := utils.ValidateHostname(host); err != nil {
+		return fmt.Errorf("invalid host %s: %w", host, err)
+	}
+	if err := utils.ValidatePort(c.Int("port")); err != nil {
+		return err
+	}
+
+	files := []string{c.String("cert")}
+	if c.String("rollover") != "" {
+		files = append(files, c.String("rollover"))
+	}
+
+	var records []inwx.RecordData
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		record, err := inwx.TLSAFromPEM(data, c.Int("usage"), c.Int("selector"), c.Int("match"))
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		records = append(records, record)
+	}
+
+	name := inwx.TLSAName(c.Int("port"), c.String("protocol"), host)
+	return publishRecordSet(c, name, "TLSA", records)
+}
+
+func generateSSHFP(c *cli.Context) error {
+	host := normalizeHostname(c.String("host"))
+	if err := utils.ValidateHostname(host); err != nil {
+		return fmt.Errorf("invalid host %s: %w", host, err)
+	}
+
+	// Unquoted globs are expanded by the shell into additional arguments
+	patterns := append(c.StringSlice("keys"), c.Args().Slice()...)
+	if len(patterns) == 0 {
+		return fmt.Errorf("at least one key file must be specified with --keys")
+	}
+
+	var records []inwx.RecordData
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no key files match %s", pattern)
+		}
+
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			keys, err := inwx.SSHFPFromPublicKeys(data, c.IntSlice("fingerprint-type")...)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			for _, key := range keys {
+				records = append(records, key)
+			}
+		}
+	}
+
+	return publishRecordSet(c, host, "SSHFP", records)
+}
+
+// publishRecordSet makes the records the only ones of their type at the host name,
+// using the RRset planner of dns set. New records are created and stale ones updated
+// before others are deleted, so the name never lacks a valid record during a rollover.
+func publishRecordSet(c *cli.Context, hostname, recordType string, data []inwx.RecordData) error {
+	values := make([]string, 0, len(data))
+	for _, d := range data {
+		values = append(values, d.Content())
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	changes, err := dns.PlanRRSet(ctx, hostname, recordType, values, c.Int("ttl"))
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Printf("✓ %s records of %s are up to date\n", recordType, hostname)
+		return nil
+	}
+
+	fmt.Printf("%s records of %s:\n", recordType, hostname)
+	printRRSetChanges(changes)
+
+	if c.Bool("dry-run") {
+		fmt.Println("Dry run - no changes were made")
+		return nil
+	}
+
+	confirmed, err := utils.AskSimpleConfirmation("Apply these changes?", c.Bool("yes"))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("❌ Changes cancelled")
+		return nil
+	}
+
+	if err := dns.ApplyRRSet(ctx, changes); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Published %s records of %s (%d change(s))\n", recordType, hostname, len(changes))
+	return nil
+}
@@ -115,6 +115,7 @@ func DNSCommand() *cli.Command {
 						Usage: "Maximum number of records to display (0 = no limit)",
 						Value: 0,
 					},
+					tagFlag(),
 				},
 				Action: listDNSRecords,
 			},
@@ -122,7 +123,10 @@ func DNSCommand() *cli.Command {
 				Name:      "create",
 				Usage:     "Create DNS record",
 				ArgsUsage: "[hostname]",
-				Flags: []cli.Flag{
+				Description: "The content of SRV, CAA, TLSA, SSHFP, HTTPS, SVCB, NAPTR, LOC and URI records can be\n" +
+					"   built from their fields with flags such as --srv-port or --caa-tag instead of --content.\n" +
+					"   URL records take the target URL as content and the --redirect-* flags.",
+				Flags: append([]cli.Flag{
 					&cli.StringFlag{
 						Name:    "domain",
 						Aliases: []string{"d"},
@@ -150,7 +154,7 @@ func DNSCommand() *cli.Command {
 					},
 					&cli.IntFlag{
 						Name:  "prio",
-						Usage: "Priority (for MX records)",
+						Usage: "Priority (for MX and SRV records)",
 					},
 					&cli.BoolFlag{
 						Name:    "dry-run",
@@ -166,13 +170,13 @@ func DNSCommand() *cli.Command {
 						Aliases: []string{"i"},
 						Usage:   "Interactive mode - prompts for all record details",
 					},
-				},
+				}, append(recordDataFlags(), urlRedirectFlags("redirect-")...)...),
 				Action: createDNSRecord,
 			},
 			{
 				Name:  "update",
 				Usage: "Update DNS record",
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					&cli.IntFlag{
 						Name:     "id",
 						Usage:    "Record ID",
@@ -210,9 +214,31 @@ func DNSCommand() *cli.Command {
 						Name:  "wait",
 						Usage: "Wait for DNS propagation to authoritative nameservers (120s timeout)",
 					},
-				},
+				}, urlRedirectFlags("redirect-")...),
 				Action: updateDNSRecord,
 			},
+			{
+				Name:      "set",
+				Usage:     "Set all records of a name and type",
+				ArgsUsage: "<hostname> <type> <value>...",
+				Description: "The values become the only records of the type at the host name. Records that\n" +
+					"   are already present are kept, and only the differences are created, updated or\n" +
+					"   deleted, so running the command again changes nothing. MX and SRV values may start\n" +
+					"   with the priority, e.g. \"10 mail.example.com\".",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "ttl",
+						Usage: "TTL value",
+						Value: 3600,
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Show the changes without applying them",
+					},
+				},
+				Action: setDNSRecords,
+			},
 			{
 				Name:  "delete",
 				Usage: "Delete DNS record(s)",
@@ -284,6 +310,7 @@ func DNSCommand() *cli.Command {
 						Name:  "output-dir",
 						Usage: "Output directory (exports one file per domain)",
 					},
+					tagFlag(),
 				},
 				Action: exportDNSRecords,
 			},
@@ -347,6 +374,7 @@ func DNSCommand() *cli.Command {
 						Usage: "Minimum severity to report (error, warning, info)",
 						Value: "warning",
 					},
+					tagFlag(),
 				},
 				Action: validateDNSRecords,
 			},
@@ -375,9 +403,13 @@ func DNSCommand() *cli.Command {
 						Usage: "Wait for propagation (e.g., 5m, 30s)",
 						Value: 0,
 					},
+					tagFlag(),
 				},
 				Action: verifyDNSRecords,
 			},
+			dnsTemplateCommand(),
+			dnsTLSACommand(),
+			dnsSSHFPCommand(),
 		},
 	}
 }
@@ -497,6 +529,15 @@ func listDNSRecords(c *cli.Context) error {
 		}
 	}()
 
+	// Tagged domains select records like --domain
+	tagged, err := taggedDomains(ctx, client, c)
+	if err != nil {
+		return err
+	}
+	if domains, err = filterTaggedDomains(domains, tagged); err != nil {
+		return err
+	}
+
 	useWildcard := c.Bool("wildcard")
 	maxRecords := c.Int("max")
 
@@ -518,6 +559,11 @@ func listDNSRecords(c *cli.Context) error {
 		if err != nil {
 			return err
 		}
+		if len(targetDomains) > 0 {
+			if targetDomains, err = filterTaggedDomains(targetDomains, tagged); err != nil {
+				return err
+			}
+		}
 
 		log.Debug().
 			Strs("domains", targetDomains).
@@ -669,15 +715,16 @@ func createDNSRecordInteractive(c *cli.Context) error {
 	var recordType string
 	err = survey.AskOne(&survey.Select{
 		Message: "Record type:",
-		Options: []string{"A", "AAAA", "CNAME", "MX", "TXT", "NS", "SRV", "CAA"},
+		Options: []string{"A", "AAAA", "CNAME", "MX", "TXT", "NS", "SRV", "CAA", "TLSA", "SSHFP", "HTTPS", "SVCB", "NAPTR", "LOC", "URI"},
 		Default: "A",
 	}, &recordType)
 	if err != nil {
 		return err
 	}
 
-	// Prompt for content with type-specific help
+	// Prompt for content with type-specific help, typed records field by field
 	var content string
+	var prio int
 	var contentHelp string
 	switch recordType {
 	case "A":
@@ -692,33 +739,38 @@ func createDNSRecordInteractive(c *cli.Context) error {
 		contentHelp = "Text content (e.g., for SPF, DKIM, verification)"
 	case "NS":
 		contentHelp = "Nameserver hostname"
-	case "SRV":
-		contentHelp = "Target hostname (priority, weight, port will be asked separately)"
-	case "CAA":
-		contentHelp = "CAA record value (e.g., 0 issue \"letsencrypt.org\")"
 	default:
 		contentHelp = "Record content"
 	}
 
-	contentPrompt := &survey.Input{
-		Message: fmt.Sprintf("Content (%s):", recordType),
-		Help:    contentHelp,
-	}
-
-	// Add validator for content
-	err = survey.AskOne(contentPrompt, &content, survey.WithValidator(func(val interface{}) error {
-		str, ok := val.(string)
-		if !ok || str == "" {
-			return fmt.Errorf("content is required")
+	if isTypedRecord(recordType) {
+		data, err := promptRecordData(recordType)
+		if err != nil {
+			return err
 		}
-		// Validate content based on type
-		if err := utils.ValidateRecordContent(recordType, str); err != nil {
+		content = data.Content()
+		prio = inwx.RecordPrio(data)
+	} else {
+		contentPrompt := &survey.Input{
+			Message: fmt.Sprintf("Content (%s):", recordType),
+			Help:    contentHelp,
+		}
+
+		// Add validator for content
+		err = survey.AskOne(contentPrompt, &content, survey.WithValidator(func(val interface{}) error {
+			str, ok := val.(string)
+			if !ok || str == "" {
+				return fmt.Errorf("content is required")
+			}
+			// Validate content based on type
+			if err := utils.ValidateRecordContent(recordType, str); err != nil {
+				return err
+			}
+			return nil
+		}))
+		if err != nil {
 			return err
 		}
-		return nil
-	}))
-	if err != nil {
-		return err
 	}
 
 	// Prompt for TTL
@@ -743,14 +795,10 @@ func createDNSRecordInteractive(c *cli.Context) error {
 	}
 	ttl, _ := strconv.Atoi(ttlStr)
 
-	// Prompt for priority (only for MX and SRV)
-	var prio int
-	if recordType == "MX" || recordType == "SRV" {
+	// Prompt for priority (SRV records include it in their fields)
+	if recordType == "MX" {
 		var prioStr string
 		prioHelp := "Lower values have higher priority (e.g., 10)"
-		if recordType == "SRV" {
-			prioHelp = "Priority value for SRV record"
-		}
 
 		err = survey.AskOne(&survey.Input{
 			Message: "Priority:",
@@ -863,7 +911,7 @@ func createDNSRecord(c *cli.Context) error {
 	// Auto-start interactive mode if no arguments or flags provided
 	hasArgs := c.NArg() > 0
 	hasFlags := c.String("domain") != "" || c.String("name") != "" ||
-		c.String("type") != "" || c.String("content") != ""
+		c.String("type") != "" || c.String("content") != "" || hasRecordDataFlags(c)
 
 	if !hasArgs && !hasFlags && !c.Bool("interactive") {
 		// No arguments or flags - start interactive mode automatically
@@ -884,6 +932,20 @@ func createDNSRecord(c *cli.Context) error {
 	prio := c.Int("prio")
 	dryRun := c.Bool("dry-run")
 
+	// Build the content of typed records from their fields
+	data, err := recordDataFromFlags(c, recordType)
+	if err != nil {
+		return fmt.Errorf("invalid record fields: %w", err)
+	}
+	if data != nil {
+		if content != "" {
+			return fmt.Errorf("--content cannot be combined with flags for record fields")
+		}
+		recordType = data.Type()
+		content = data.Content()
+		prio = inwx.RecordPrio(data)
+	}
+
 	// Validate required fields for non-interactive mode
 	if recordType == "" {
 		return fmt.Errorf("--type is required (or use --interactive mode)")
@@ -936,6 +998,18 @@ func createDNSRecord(c *cli.Context) error {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	record := inwx.DNSRecord{
+		Domain:  domain,
+		Type:    recordType,
+		Name:    name,
+		Content: content,
+		TTL:     ttl,
+		Prio:    prio,
+	}
+	if err := applyURLRedirectFlags(c, "redirect-", &record); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
 	// Dry-run mode: show what would be created
 	if dryRun {
 		log.Info().Msg("Dry run mode - would create DNS record:")
@@ -947,6 +1021,9 @@ func createDNSRecord(c *cli.Context) error {
 		if prio > 0 {
 			log.Info().Msgf("  Priority: %d", prio)
 		}
+		if record.HasURLRedirect() {
+			log.Info().Msgf("  Redirect: %s", urlRedirectSummary(record))
+		}
 		return nil
 	}
 
@@ -962,15 +1039,6 @@ func createDNSRecord(c *cli.Context) error {
 		}()
 	}
 
-	record := inwx.DNSRecord{
-		Domain:  domain,
-		Type:    recordType,
-		Name:    name,
-		Content: content,
-		TTL:     ttl,
-		Prio:    prio,
-	}
-
 	dns, err := createDNSService(c, client)
 	if err != nil {
 		return err
@@ -1027,6 +1095,17 @@ func updateDNSRecord(c *cli.Context) error {
 		}
 	}
 
+	updates := inwx.DNSRecord{
+		Type:    recordType,
+		Name:    name,
+		Content: content,
+		TTL:     ttl,
+		Prio:    prio,
+	}
+	if err := applyURLRedirectFlags(c, "redirect-", &updates); err != nil {
+		return err
+	}
+
 	// Dry-run mode: show what would be updated
 	if dryRun {
 		log.Info().Msgf("Dry run mode - would update DNS record ID %d:", recordID)
@@ -1045,6 +1124,9 @@ func updateDNSRecord(c *cli.Context) error {
 		if prio > 0 {
 			log.Info().Msgf("  Priority: %d", prio)
 		}
+		if updates.HasURLRedirect() {
+			log.Info().Msgf("  Redirect: %s", urlRedirectSummary(updates))
+		}
 		return nil
 	}
 
@@ -1063,14 +1145,6 @@ func updateDNSRecord(c *cli.Context) error {
 		}
 	}()
 
-	updates := inwx.DNSRecord{
-		Type:    recordType,
-		Name:    name,
-		Content: content,
-		TTL:     ttl,
-		Prio:    prio,
-	}
-
 	dns, err := createDNSService(c, client)
 	if err != nil {
 		return err
@@ -1082,7 +1156,12 @@ func updateDNSRecord(c *cli.Context) error {
 		return fmt.Errorf("failed to get record: %w", err)
 	}
 
-	updated, err := dns.UpdateRecord(ctx, recordID, updates)
+	var updated *inwx.DNSRecord
+	if urlRedirectFlagsSet(c, "redirect-") {
+		updated, err = updateURLRedirect(ctx, c, "redirect-", dns, *originalRecord, updates)
+	} else {
+		updated, err = dns.UpdateRecord(ctx, recordID, updates)
+	}
 	if err != nil {
 		return err
 	}
@@ -1116,6 +1195,94 @@ func updateDNSRecord(c *cli.Context) error {
 	return nil
 }
 
+func setDNSRecords(c *cli.Context) error {
+	if c.NArg() < 3 {
+		return fmt.Errorf("a host name, a record type and at least one value must be specified")
+	}
+	hostname := c.Args().Get(0)
+	recordType := strings.ToUpper(c.Args().Get(1))
+	values := c.Args().Slice()[2:]
+
+	if err := utils.ValidateRecordType(recordType); err != nil {
+		return err
+	}
+	if err := utils.ValidateTTL(c.Int("ttl")); err != nil {
+		return fmt.Errorf("invalid TTL: %w", err)
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	changes, err := dns.PlanRRSet(ctx, hostname, recordType, values, c.Int("ttl"))
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("✓ %s records of %s are up to date\n", recordType, hostname)
+		return nil
+	}
+
+	deletes := printRRSetChanges(changes)
+
+	if c.Bool("dry-run") {
+		fmt.Println("Dry run - no changes were made")
+		return nil
+	}
+
+	if deletes > 0 {
+		confirmed, err := utils.AskSimpleConfirmation(fmt.Sprintf("Delete %d record(s)?", deletes), c.Bool("yes"))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("❌ Changes cancelled")
+			return nil
+		}
+	}
+
+	if err := dns.ApplyRRSet(ctx, changes); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Set %s records of %s (%d change(s))\n", recordType, hostname, len(changes))
+	return nil
+}
+
+// printRRSetChanges prints the preview of RRset changes and returns the number of
+// records that would be deleted
+func printRRSetChanges(changes []inwx.RRSetChange) int {
+	deletes := 0
+	for _, change := range changes {
+		switch change.Action {
+		case inwx.RRSetCreate:
+			fmt.Printf("+ %s\n", templateRecordLabel(change.Record))
+		case inwx.RRSetUpdate:
+			fmt.Printf("~ %s\n  → %s\n", templateRecordLabel(*change.Current), templateRecordLabel(change.Record))
+		case inwx.RRSetDelete:
+			fmt.Printf("- %s\n", templateRecordLabel(change.Record))
+			deletes++
+		}
+	}
+	return deletes
+}
+
 func deleteDNSRecord(c *cli.Context) error {
 	// Parse flags first
 	ids := parseCommaSeparatedValues(c.StringSlice("id"))
@@ -1271,6 +1438,14 @@ func exportDNSRecords(c *cli.Context) error {
 		domains = append(domains, d)
 	}
 
+	tagged, err := taggedDomains(ctx, client, c)
+	if err != nil {
+		return err
+	}
+	if domains, err = filterTaggedDomains(domains, tagged); err != nil {
+		return err
+	}
+
 	outputDir := c.String("output-dir")
 	outputFile := c.String("output")
 
@@ -2049,6 +2224,11 @@ func recordKey(rec inwx.DNSRecord) string {
 		return fmt.Sprintf("%s|%s|%d|%s|%d", rec.Type, name, rec.Prio, content, rec.TTL)
 	}
 
+	// Redirect parameters are part of URL records
+	if rec.Type == "URL" {
+		return fmt.Sprintf("%s|%s|%s|%d|%s", rec.Type, name, content, rec.TTL, urlRedirectSummary(rec))
+	}
+
 	// Include TTL in the comparison since changing TTL is a meaningful change
 	return fmt.Sprintf("%s|%s|%s|%d", rec.Type, name, content, rec.TTL)
 }
@@ -2119,6 +2299,14 @@ func validateDNSRecords(c *cli.Context) error {
 		}
 	}()
 
+	tagged, err := taggedDomains(ctx, client, c)
+	if err != nil {
+		return err
+	}
+	if domains, err = filterTaggedDomains(domains, tagged); err != nil {
+		return err
+	}
+
 	// If no domains specified, get all domains
 	if len(domains) == 0 {
 		domainService := client.Domain()
@@ -2163,28 +2351,15 @@ func validateDNSRecords(c *cli.Context) error {
 				continue // Skip issues below minimum severity
 			}
 
-			var icon string
 			switch issue.Severity {
 			case "error":
-				icon = "✗"
 				totalErrors++
 			case "warning":
-				icon = "⚠"
 				totalWarnings++
 			case "info":
-				icon = "ℹ"
 				totalInfo++
 			}
-
-			fmt.Printf("\n%s %s: %s", icon, strings.ToUpper(issue.Severity), issue.Message)
-			if issue.RecordID > 0 {
-				fmt.Printf(" (ID: %d)", issue.RecordID)
-			}
-			fmt.Println()
-
-			if issue.Suggestion != "" {
-				fmt.Printf("  → %s\n", issue.Suggestion)
-			}
+			printValidationIssue(issue)
 		}
 
 		// Show summary for this domain
@@ -2226,6 +2401,29 @@ func validateDNSRecords(c *cli.Context) error {
 	return nil
 }
 
+// printValidationIssue prints an issue with its severity and suggestion
+func printValidationIssue(issue inwx.ValidationIssue) {
+	var icon string
+	switch issue.Severity {
+	case "error":
+		icon = "✗"
+	case "warning":
+		icon = "⚠"
+	case "info":
+		icon = "ℹ"
+	}
+
+	fmt.Printf("\n%s %s: %s", icon, strings.ToUpper(issue.Severity), issue.Message)
+	if issue.RecordID > 0 {
+		fmt.Printf(" (ID: %d)", issue.RecordID)
+	}
+	fmt.Println()
+
+	if issue.Suggestion != "" {
+		fmt.Printf("  → %s\n", issue.Suggestion)
+	}
+}
+
 func verifyDNSRecords(c *cli.Context) error {
 	// Parse flags and positional arguments
 	domains := parseCommaSeparatedValues(c.StringSlice("domain"))
@@ -2251,6 +2449,11 @@ func verifyDNSRecords(c *cli.Context) error {
 
 	dns := client.DNS()
 
+	tagged, err := taggedDomains(ctx, client, c)
+	if err != nil {
+		return err
+	}
+
 	// Determine what to verify
 	var targetDomains []string
 	var targetHosts []string
@@ -2261,11 +2464,19 @@ func verifyDNSRecords(c *cli.Context) error {
 		if err != nil {
 			return err
 		}
-	} else if len(domains) > 0 {
-		// Use flag-specified domains
-		targetDomains = domains
+		// Hosts are explicit targets, only the domains are restricted to the tags
+		if len(targetDomains) > 0 {
+			if targetDomains, err = filterTaggedDomains(targetDomains, tagged); err != nil {
+				return err
+			}
+		}
+	} else if len(domains) > 0 || tagged != nil {
+		// Use flag-specified domains carrying the tags, or all tagged domains
+		if targetDomains, err = filterTaggedDomains(domains, tagged); err != nil {
+			return err
+		}
 	} else {
-		return fmt.Errorf("no domains or hosts specified - provide domain(s) as arguments or use --domain flag")
+		return fmt.Errorf("no domains or hosts specified - provide domain(s) as arguments or use --domain or --tag")
 	}
 
 	if waitDuration > 0 {
@@ -2458,6 +2669,9 @@ func displayRecordCreated(record *inwx.DNSRecord) {
 	if record.Prio > 0 {
 		fmt.Printf("  %sPriority:%s   %d\n", cyan, reset, record.Prio)
 	}
+	if record.HasURLRedirect() {
+		fmt.Printf("  %sRedirect:%s   %s\n", cyan, reset, urlRedirectSummary(*record))
+	}
 
 	// Full hostname
 	fullName := record.Name
@@ -2502,6 +2716,9 @@ func displayRecordUpdated(record *inwx.DNSRecord) {
 	if record.Prio > 0 {
 		fmt.Printf("  %sPriority:%s   %d\n", cyan, reset, record.Prio)
 	}
+	if record.HasURLRedirect() {
+		fmt.Printf("  %sRedirect:%s   %s\n", cyan, reset, urlRedirectSummary(*record))
+	}
 
 	// Full hostname
 	fullName := record.Name
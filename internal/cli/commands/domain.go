@@ -2,11 +2,19 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v2"
 
+	"github.com/nmeilick/inwx-cli/internal/backup"
 	"github.com/nmeilick/inwx-cli/internal/cli/output"
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
 )
 
 func DomainCommand() *cli.Command {
@@ -15,15 +23,384 @@ func DomainCommand() *cli.Command {
 		Usage: "Domain management",
 		Subcommands: []*cli.Command{
 			{
-				Name:   "list",
-				Usage:  "List domains",
+				Name:  "list",
+				Usage: "List domains",
+				Flags: append(domainFilterFlags(),
+					&cli.IntFlag{
+						Name:  "page",
+						Usage: "Only show the given page (0 = all domains)",
+						Value: 0,
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Usage: "Domains per page when --page is set",
+						Value: 50,
+					},
+				),
 				Action: listDomains,
 			},
+			{
+				Name:   "stats",
+				Usage:  "Show domain counts per TLD, status and expiry month",
+				Flags:  domainFilterFlags(),
+				Action: showDomainStats,
+			},
+			{
+				Name:      "renew",
+				Usage:     "Manually renew domain(s)",
+				ArgsUsage: "[domain...]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    "pattern",
+						Aliases: []string{"P"},
+						Usage:   "Select owned domains by shell-style wildcard pattern (e.g., \"*.de\")",
+					},
+					&cli.StringFlag{
+						Name:  "period",
+						Usage: "Renewal period (e.g., 1Y, 2Y)",
+						Value: "1Y",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the renewal in API testing mode without executing it",
+					},
+				},
+				Action: renewDomains,
+			},
+			{
+				Name:      "restore",
+				Usage:     "Restore expired or deleted domain(s)",
+				ArgsUsage: "<domain...>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "renewal-mode",
+						Usage: "Renewal mode to set after restore (AUTORENEW, AUTODELETE, AUTOEXPIRE)",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the restore in API testing mode without executing it",
+					},
+				},
+				Action: restoreDomains,
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete domain(s)",
+				ArgsUsage: "<domain...>",
+				Flags: []cli.Flag{
+					&cli.TimestampFlag{
+						Name:   "at",
+						Usage:  "Schedule deletion at the given date (YYYY-MM-DD)",
+						Layout: "2006-01-02",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the deletion in API testing mode without executing it",
+					},
+				},
+				Action: deleteDomains,
+			},
+			{
+				Name:      "hold",
+				Usage:     "Set clientHold status (removes the domain from DNS)",
+				ArgsUsage: "[domain...]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    "pattern",
+						Aliases: []string{"P"},
+						Usage:   "Select owned domains by shell-style wildcard pattern (e.g., \"*.de\")",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the change in API testing mode without executing it",
+					},
+				},
+				Action: holdDomains,
+			},
+			{
+				Name:      "unhold",
+				Usage:     "Remove clientHold status",
+				ArgsUsage: "[domain...]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    "pattern",
+						Aliases: []string{"P"},
+						Usage:   "Select owned domains by shell-style wildcard pattern (e.g., \"*.de\")",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the change in API testing mode without executing it",
+					},
+				},
+				Action: unholdDomains,
+			},
+			{
+				Name:      "set-renewal",
+				Usage:     "Set the renewal mode of domain(s)",
+				ArgsUsage: "[domain...]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "mode",
+						Aliases:  []string{"m"},
+						Usage:    "Renewal mode (AUTORENEW, AUTODELETE, AUTOEXPIRE)",
+						Required: true,
+					},
+					&cli.StringSliceFlag{
+						Name:    "pattern",
+						Aliases: []string{"P"},
+						Usage:   "Select owned domains by shell-style wildcard pattern (e.g., \"*.de\")",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the change in API testing mode without executing it",
+					},
+					&cli.IntFlag{
+						Name:  "max",
+						Usage: "Maximum number of domains to change (0 = no limit)",
+						Value: 0,
+					},
+				},
+				Action: setDomainRenewalMode,
+			},
+			{
+				Name:      "set-contacts",
+				Usage:     "Assign new contact handles to domain(s)",
+				ArgsUsage: "[domain...]",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "registrant",
+						Usage: "New owner contact handle ID (may trigger a chargeable owner change)",
+					},
+					&cli.IntFlag{
+						Name:  "admin",
+						Usage: "New administrative contact handle ID",
+					},
+					&cli.IntFlag{
+						Name:  "tech",
+						Usage: "New technical contact handle ID",
+					},
+					&cli.IntFlag{
+						Name:  "billing",
+						Usage: "New billing contact handle ID",
+					},
+					&cli.StringSliceFlag{
+						Name:    "pattern",
+						Aliases: []string{"P"},
+						Usage:   "Select owned domains by shell-style wildcard pattern (e.g., \"*.de\")",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the change in API testing mode without executing it",
+					},
+					&cli.IntFlag{
+						Name:  "max",
+						Usage: "Maximum number of domains to change (0 = no limit)",
+						Value: 0,
+					},
+				},
+				Action: setDomainContacts,
+			},
+			{
+				Name:      "set-ns",
+				Usage:     "Set the nameservers of domain(s)",
+				ArgsUsage: "[domain...]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "ns",
+						Usage: "Nameserver hostname (can be repeated)",
+					},
+					&cli.StringFlag{
+						Name:  "nsset",
+						Usage: "Use the nameservers of a nameserver set (ID or name)",
+					},
+					&cli.StringSliceFlag{
+						Name:    "pattern",
+						Aliases: []string{"P"},
+						Usage:   "Select owned domains by shell-style wildcard pattern (e.g., \"*.de\")",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the change in API testing mode without executing it",
+					},
+					&cli.IntFlag{
+						Name:  "max",
+						Usage: "Maximum number of domains to change (0 = no limit)",
+						Value: 0,
+					},
+				},
+				Action: setDomainNameservers,
+			},
+			{
+				Name:      "register",
+				Usage:     "Register a new domain",
+				ArgsUsage: "<domain>",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:     "registrant",
+						Usage:    "Registrant contact handle ID",
+						Required: true,
+					},
+					&cli.IntFlag{
+						Name:  "admin",
+						Usage: "Admin contact handle ID",
+					},
+					&cli.IntFlag{
+						Name:  "tech",
+						Usage: "Tech contact handle ID",
+					},
+					&cli.IntFlag{
+						Name:  "billing",
+						Usage: "Billing contact handle ID",
+					},
+					&cli.StringSliceFlag{
+						Name:  "ns",
+						Usage: "Nameserver hostname (can be repeated)",
+					},
+					&cli.StringFlag{
+						Name:  "nsset",
+						Usage: "Use the nameservers of a nameserver set (ID or name)",
+					},
+					&cli.StringFlag{
+						Name:  "period",
+						Usage: "Registration period (e.g., 1Y, 2Y)",
+						Value: "1Y",
+					},
+					&cli.StringFlag{
+						Name:  "renewal-mode",
+						Usage: "Renewal mode (AUTORENEW, AUTODELETE, AUTOEXPIRE)",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the registration in API testing mode without executing it",
+					},
+				},
+				Action: registerDomain,
+			},
+			{
+				Name:      "whois",
+				Usage:     "Show the whois information of a domain",
+				ArgsUsage: "<domain>",
+				Action:    showDomainWhois,
+			},
+			{
+				Name:      "log",
+				Usage:     "Show the action log of a domain (or all domains)",
+				ArgsUsage: "[domain]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "since",
+						Usage: "Only entries newer than duration (e.g., 30d, 2w, 12h)",
+					},
+					&cli.TimestampFlag{
+						Name:   "from",
+						Usage:  "Only entries on or after date (YYYY-MM-DD)",
+						Layout: "2006-01-02",
+					},
+					&cli.TimestampFlag{
+						Name:   "to",
+						Usage:  "Only entries before date (YYYY-MM-DD)",
+						Layout: "2006-01-02",
+					},
+					&cli.StringFlag{
+						Name:  "status",
+						Usage: "Only entries with the given domain status",
+					},
+					&cli.IntFlag{
+						Name:  "page",
+						Usage: "Page number",
+						Value: 1,
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Usage: "Entries per page",
+						Value: 20,
+					},
+					&cli.BoolFlag{
+						Name:    "all",
+						Aliases: []string{"a"},
+						Usage:   "Fetch all pages",
+					},
+				},
+				Action: showDomainLog,
+			},
 		},
 	}
 }
 
+// domainFilterFlags returns the flags for server-side domain list filters
+func domainFilterFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "search",
+			Aliases: []string{"s"},
+			Usage:   "Filter by domain name search string (wildcards allowed, e.g., \"shop*\")",
+		},
+		&cli.StringSliceFlag{
+			Name:  "tld",
+			Usage: "Filter by TLD (e.g., de,com); combined with --search, both must match",
+		},
+		&cli.StringSliceFlag{
+			Name:  "status",
+			Usage: "Filter by domain status (e.g., OK)",
+		},
+		&cli.StringFlag{
+			Name:  "renewal-mode",
+			Usage: "Filter by renewal mode (AUTORENEW, AUTODELETE, AUTOEXPIRE)",
+		},
+		tagFlag(),
+	}
+}
+
+// parseDomainFilters converts the domain filter flags into domain list filters;
+// tag names are resolved to tag IDs
+func parseDomainFilters(ctx context.Context, client *inwx.Client, c *cli.Context) ([]inwx.DomainFilter, error) {
+	var filters []inwx.DomainFilter
+
+	if search := parseCommaSeparatedValues(c.StringSlice("search")); len(search) > 0 {
+		filters = append(filters, inwx.WithDomainSearch(search...))
+	}
+	if tlds := parseCommaSeparatedValues(c.StringSlice("tld")); len(tlds) > 0 {
+		filters = append(filters, inwx.WithTLD(tlds...))
+	}
+	if statuses := parseCommaSeparatedValues(c.StringSlice("status")); len(statuses) > 0 {
+		for i, status := range statuses {
+			statuses[i] = strings.ToUpper(status)
+		}
+		filters = append(filters, inwx.WithDomainStatus(statuses...))
+	}
+	if mode := c.String("renewal-mode"); mode != "" {
+		if !inwx.IsValidRenewalMode(mode) {
+			return nil, fmt.Errorf("invalid renewal mode %q (must be one of %s)", mode, strings.Join(inwx.RenewalModes, ", "))
+		}
+		filters = append(filters, inwx.WithRenewalMode(mode))
+	}
+	if tags := parseCommaSeparatedValues(c.StringSlice("tag")); len(tags) > 0 {
+		ids, err := resolveTagIDs(ctx, client, tags)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, inwx.WithTagIDs(ids...))
+	}
+
+	return filters, nil
+}
+
 func listDomains(c *cli.Context) error {
+	page := c.Int("page")
+	limit := c.Int("limit")
+	if page < 0 || limit < 1 {
+		return fmt.Errorf("--page must not be negative and --limit must be positive")
+	}
+
 	client, err := createClient(c)
 	if err != nil {
 		return err
@@ -39,8 +416,22 @@ func listDomains(c *cli.Context) error {
 		}
 	}()
 
+	filters, err := parseDomainFilters(ctx, client, c)
+	if err != nil {
+		return err
+	}
+
 	domain := client.Domain()
-	domains, err := domain.List(ctx)
+	var domains []inwx.Domain
+	if page > 0 {
+		var count int
+		domains, count, err = domain.ListPage(ctx, page, limit, filters...)
+		if err == nil {
+			log.Info().Msgf("Page %d: %d of %d domain(s)", page, len(domains), count)
+		}
+	} else {
+		domains, err = domain.List(ctx, filters...)
+	}
 	if err != nil {
 		return err
 	}
@@ -53,8 +444,1003 @@ func listDomains(c *cli.Context) error {
 			return f.FormatDomains(domains)
 		case *output.YAMLFormatter:
 			return f.FormatDomains(domains)
+		case *output.CSVFormatter:
+			return f.FormatDomains(domains)
 		default:
 			return "Unsupported format"
 		}
 	})
 }
+
+func showDomainStats(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	filters, err := parseDomainFilters(ctx, client, c)
+	if err != nil {
+		return err
+	}
+
+	stats, err := client.Domain().Summary(ctx, filters...)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatDomainStats(stats)
+		case *output.JSONFormatter:
+			return f.FormatDomainStats(stats)
+		case *output.YAMLFormatter:
+			return f.FormatDomainStats(stats)
+		case *output.CSVFormatter:
+			return f.FormatDomainStats(stats)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+// resolveDomainTargets combines explicitly named domains with owned domains matching
+// any of the wildcard patterns. Named domains must be owned by the account.
+func resolveDomainTargets(ctx context.Context, client *inwx.Client, names, patterns []string) ([]string, error) {
+	if len(names) == 0 && len(patterns) == 0 {
+		return nil, fmt.Errorf("at least one domain or --pattern must be specified")
+	}
+
+	owned, err := client.Domain().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list domains: %w", err)
+	}
+
+	ownedMap := make(map[string]string)
+	for _, d := range owned {
+		ownedMap[strings.ToLower(d.Name)] = d.Name
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range names {
+		domain, ok := ownedMap[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("domain '%s' not found in your account", name)
+		}
+		selected[domain] = true
+	}
+
+	for _, pattern := range patterns {
+		matched := false
+		for lower, domain := range ownedMap {
+			if utils.MatchWildcard(strings.ToLower(pattern), lower) {
+				selected[domain] = true
+				matched = true
+			}
+		}
+		if !matched {
+			log.Warn().Str("pattern", pattern).Msg("Pattern did not match any domain")
+		}
+	}
+
+	var domains []string
+	for domain := range selected {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	return domains, nil
+}
+
+// confirmDomains asks for confirmation for each domain using the (Y)es/(N)o/(A)ll/(C)ancel
+// prompt and returns the confirmed domains. A cancelled prompt returns no domains.
+func confirmDomains(action string, domains []string, skipPrompt bool) ([]string, error) {
+	var confirmed []string
+	all := skipPrompt
+
+	for _, domain := range domains {
+		if all {
+			confirmed = append(confirmed, domain)
+			continue
+		}
+
+		result, err := utils.AskConfirmation(fmt.Sprintf("%s %s?", action, domain), false)
+		if err != nil {
+			return nil, err
+		}
+
+		switch result {
+		case utils.ConfirmationYes:
+			confirmed = append(confirmed, domain)
+		case utils.ConfirmationAll:
+			confirmed = append(confirmed, domain)
+			all = true
+		case utils.ConfirmationNo:
+			continue
+		case utils.ConfirmationCancel:
+			return nil, nil
+		}
+	}
+
+	return confirmed, nil
+}
+
+// runDomainAction resolves the target domains, asks for confirmation unless in dry-run mode
+// and runs action for each confirmed domain, reporting per-domain results
+func runDomainAction(c *cli.Context, verb string, confirm bool, resolve bool, action func(ctx context.Context, domains *inwx.DomainService, domain string) (string, error)) error {
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	targets := c.Args().Slice()
+	if resolve {
+		targets, err = resolveDomainTargets(ctx, client, targets, c.StringSlice("pattern"))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(targets) == 0 {
+		return fmt.Errorf("no domains specified")
+	}
+
+	if confirm && !dryRun {
+		targets, err = confirmDomains(verb, targets, c.Bool("yes"))
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	domainService := client.Domain(inwx.WithDomainTesting(dryRun))
+
+	failed := 0
+	for _, domain := range targets {
+		message, err := action(ctx, domainService, domain)
+		if err != nil {
+			log.Error().Err(err).Str("domain", domain).Msgf("Failed to %s domain", strings.ToLower(verb))
+			fmt.Printf("✗ %s: %v\n", domain, err)
+			failed++
+			continue
+		}
+
+		if dryRun {
+			fmt.Printf("✓ %s: %s (dry run, validated in API testing mode)\n", domain, message)
+		} else {
+			fmt.Printf("✓ %s: %s\n", domain, message)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d domain(s) failed", failed, len(targets))
+	}
+
+	return nil
+}
+
+func renewDomains(c *cli.Context) error {
+	period := strings.ToUpper(c.String("period"))
+
+	return runDomainAction(c, "Renew", true, true, func(ctx context.Context, domains *inwx.DomainService, domain string) (string, error) {
+		result, err := domains.Renew(ctx, domain, period)
+		if err != nil {
+			return "", err
+		}
+
+		message := fmt.Sprintf("renewed for %s", period)
+		if !result.ExpiresAt.IsZero() {
+			message += fmt.Sprintf(", expires %s", result.ExpiresAt.Format("2006-01-02"))
+		}
+		if result.Price > 0 {
+			message += fmt.Sprintf(" (%.2f %s)", result.Price, result.Currency)
+		}
+		return message, nil
+	})
+}
+
+func restoreDomains(c *cli.Context) error {
+	renewalMode := c.String("renewal-mode")
+	if renewalMode != "" && !inwx.IsValidRenewalMode(renewalMode) {
+		return fmt.Errorf("invalid renewal mode %q (must be one of %s)", renewalMode, strings.Join(inwx.RenewalModes, ", "))
+	}
+
+	// Restorable domains are not necessarily listed as owned, so targets are not resolved
+	return runDomainAction(c, "Restore", true, false, func(ctx context.Context, domains *inwx.DomainService, domain string) (string, error) {
+		if err := domains.Restore(ctx, domain, renewalMode); err != nil {
+			return "", err
+		}
+		return "restored", nil
+	})
+}
+
+func deleteDomains(c *cli.Context) error {
+	var scheduledAt time.Time
+	if ts := c.Timestamp("at"); ts != nil {
+		scheduledAt = *ts
+	}
+
+	return runDomainAction(c, "Delete", true, true, func(ctx context.Context, domains *inwx.DomainService, domain string) (string, error) {
+		if err := domains.Delete(ctx, domain, scheduledAt); err != nil {
+			return "", err
+		}
+		if !scheduledAt.IsZero() {
+			return fmt.Sprintf("deletion scheduled for %s", scheduledAt.Format("2006-01-02")), nil
+		}
+		return "deleted", nil
+	})
+}
+
+func holdDomains(c *cli.Context) error {
+	return runDomainAction(c, "Set clientHold on", true, true, func(ctx context.Context, domains *inwx.DomainService, domain string) (string, error) {
+		if err := domains.SetClientHold(ctx, domain); err != nil {
+			return "", err
+		}
+		return "clientHold set", nil
+	})
+}
+
+func unholdDomains(c *cli.Context) error {
+	return runDomainAction(c, "Remove clientHold from", false, true, func(ctx context.Context, domains *inwx.DomainService, domain string) (string, error) {
+		if err := domains.RemoveClientHold(ctx, domain); err != nil {
+			return "", err
+		}
+		return "clientHold removed", nil
+	})
+}
+
+func setDomainRenewalMode(c *cli.Context) error {
+	mode := strings.ToUpper(c.String("mode"))
+	if !inwx.IsValidRenewalMode(mode) {
+		return fmt.Errorf("invalid renewal mode %q (must be one of %s)", mode, strings.Join(inwx.RenewalModes, ", "))
+	}
+
+	dryRun := c.Bool("dry-run")
+	maxDomains := c.Int("max")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	targets, err := resolveDomainTargets(ctx, client, c.Args().Slice(), c.StringSlice("pattern"))
+	if err != nil {
+		return err
+	}
+
+	// Look up the current renewal mode to skip unchanged domains and show a preview
+	domainService := client.Domain(inwx.WithDomainTesting(dryRun))
+	type change struct {
+		domain  string
+		current string
+	}
+	var changes []change
+	for _, domain := range targets {
+		info, err := domainService.Info(ctx, domain)
+		if err != nil {
+			log.Warn().Err(err).Str("domain", domain).Msg("Failed to get domain info, skipping")
+			continue
+		}
+		if strings.EqualFold(info.RenewalMode, mode) {
+			log.Debug().Str("domain", domain).Msg("Renewal mode already set, skipping")
+			continue
+		}
+		changes = append(changes, change{domain: domain, current: info.RenewalMode})
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("All matching domains already use renewal mode %s\n", mode)
+		return nil
+	}
+
+	if maxDomains > 0 && len(changes) > maxDomains {
+		return fmt.Errorf("found %d domains to change, which exceeds the safety limit of %d - refine your selection or increase --max", len(changes), maxDomains)
+	}
+
+	fmt.Printf("Changing renewal mode of %d domain(s):\n", len(changes))
+	fmt.Printf("%-40s %-12s %s\n", "Domain", "Current", "New")
+	fmt.Println(strings.Repeat("-", 66))
+	for _, ch := range changes {
+		current := ch.current
+		if current == "" {
+			current = "-"
+		}
+		fmt.Printf("%-40s %-12s %s\n", ch.domain, current, mode)
+	}
+
+	switch {
+	case dryRun:
+	case mode == inwx.RenewalModeAutoDelete:
+		// Domains set to AUTODELETE are deleted at expiry, so each one is confirmed like delete
+		var domains []string
+		for _, ch := range changes {
+			domains = append(domains, ch.domain)
+		}
+		confirmed, err := confirmDomains("Set renewal mode "+mode+" on", domains, c.Bool("yes"))
+		if err != nil {
+			return err
+		}
+		if len(confirmed) == 0 {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+		var kept []change
+		for _, ch := range changes {
+			if utils.ContainsString(confirmed, ch.domain) {
+				kept = append(kept, ch)
+			}
+		}
+		changes = kept
+	default:
+		confirmed, err := utils.AskSimpleConfirmation("Continue?", c.Bool("yes"))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	failed := 0
+	for _, ch := range changes {
+		if err := domainService.SetRenewalMode(ctx, ch.domain, mode); err != nil {
+			log.Error().Err(err).Str("domain", ch.domain).Msg("Failed to set renewal mode")
+			failed++
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("\nDry run mode - %d change(s) validated in API testing mode\n", len(changes)-failed)
+	} else {
+		log.Info().Msgf("Updated renewal mode of %d domain(s)", len(changes)-failed)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to update %d of %d domain(s)", failed, len(changes))
+	}
+
+	return nil
+}
+
+func setDomainContacts(c *cli.Context) error {
+	target := inwx.DomainContacts{
+		Registrant: c.Int("registrant"),
+		Admin:      c.Int("admin"),
+		Tech:       c.Int("tech"),
+		Billing:    c.Int("billing"),
+	}
+	if target == (inwx.DomainContacts{}) {
+		return fmt.Errorf("at least one of --registrant, --admin, --tech or --billing must be specified")
+	}
+
+	dryRun := c.Bool("dry-run")
+	maxDomains := c.Int("max")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	// Make sure all referenced handles exist before touching any domain
+	contactService := client.Contact()
+	for _, id := range []int{target.Registrant, target.Admin, target.Tech, target.Billing} {
+		if id == 0 {
+			continue
+		}
+		if _, err := contactService.Info(ctx, id); err != nil {
+			return fmt.Errorf("contact handle %d not found: %w", id, err)
+		}
+	}
+
+	targets, err := resolveDomainTargets(ctx, client, c.Args().Slice(), c.StringSlice("pattern"))
+	if err != nil {
+		return err
+	}
+
+	// Look up the current handles to skip unchanged domains and show a preview
+	domainService := client.Domain(inwx.WithDomainTesting(dryRun))
+	type change struct {
+		domain  string
+		current inwx.DomainContacts
+		changes inwx.DomainContacts
+	}
+	var changes []change
+	var tradeDomains []string
+	for _, domain := range targets {
+		info, err := domainService.Info(ctx, domain)
+		if err != nil {
+			log.Warn().Err(err).Str("domain", domain).Msg("Failed to get domain info, skipping")
+			continue
+		}
+
+		current := info.Contacts()
+		diff := diffDomainContacts(current, target)
+		if diff == (inwx.DomainContacts{}) {
+			log.Debug().Str("domain", domain).Msg("Contact handles already set, skipping")
+			continue
+		}
+
+		changes = append(changes, change{domain: domain, current: current, changes: diff})
+		if diff.Registrant != 0 && inwx.OwnerChangeRequiresTrade(domain) {
+			tradeDomains = append(tradeDomains, domain)
+		}
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("All matching domains already use the requested contact handles")
+		return nil
+	}
+
+	if maxDomains > 0 && len(changes) > maxDomains {
+		return fmt.Errorf("found %d domains to change, which exceeds the safety limit of %d - refine your selection or increase --max", len(changes), maxDomains)
+	}
+
+	fmt.Printf("Changing contact handles of %d domain(s):\n", len(changes))
+	fmt.Printf("%-40s %-18s %-18s %-18s %s\n", "Domain", "Registrant", "Admin", "Tech", "Billing")
+	fmt.Println(strings.Repeat("-", 112))
+	for _, ch := range changes {
+		fmt.Printf("%-40s %-18s %-18s %-18s %s\n", ch.domain,
+			formatHandleChange(ch.current.Registrant, ch.changes.Registrant),
+			formatHandleChange(ch.current.Admin, ch.changes.Admin),
+			formatHandleChange(ch.current.Tech, ch.changes.Tech),
+			formatHandleChange(ch.current.Billing, ch.changes.Billing))
+	}
+
+	if len(tradeDomains) > 0 {
+		fmt.Printf("\n⚠️  The registry handles an owner change of the following domain(s) as a trade (domain.trade), which may incur fees:\n")
+		for _, domain := range tradeDomains {
+			fmt.Printf("  - %s\n", domain)
+		}
+	}
+
+	var store backup.BackupStore
+	if !dryRun {
+		confirmed, err := utils.AskSimpleConfirmation("Continue?", c.Bool("yes"))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+
+		store, err = backup.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize backup store: %w", err)
+		}
+	}
+
+	failed := 0
+	for _, ch := range changes {
+		ch := ch
+		apply := func() error {
+			return domainService.SetContacts(ctx, ch.domain, ch.changes)
+		}
+
+		if store != nil {
+			// Journal the previous handles so the change can be reverted with "backup revert"
+			record := inwx.DNSRecord{Domain: ch.domain, Content: summarizeHandleChanges(ch.current, ch.changes)}
+			backupContext := map[string]interface{}{
+				"previous": domainContactsMap(ch.current),
+				"new":      domainContactsMap(ch.changes),
+			}
+			if entry, err := store.AtomicChange(inwx.OperationDomainContacts, record, backupContext, apply); err != nil {
+				log.Error().Err(err).Str("domain", ch.domain).Msg("Failed to set contact handles")
+				failed++
+			} else {
+				fmt.Printf("✓ %s: contact handles updated (backup ID: %s)\n", ch.domain, entry.ID)
+			}
+			continue
+		}
+
+		if err := apply(); err != nil {
+			log.Error().Err(err).Str("domain", ch.domain).Msg("Failed to set contact handles")
+			failed++
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("\nDry run mode - %d change(s) validated in API testing mode\n", len(changes)-failed)
+	} else {
+		log.Info().Msgf("Updated contact handles of %d domain(s)", len(changes)-failed)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to update %d of %d domain(s)", failed, len(changes))
+	}
+
+	return nil
+}
+
+// diffDomainContacts returns the handles of target that differ from current
+func diffDomainContacts(current, target inwx.DomainContacts) inwx.DomainContacts {
+	pick := func(old, new int) int {
+		if new == 0 || old == new {
+			return 0
+		}
+		return new
+	}
+
+	return inwx.DomainContacts{
+		Registrant: pick(current.Registrant, target.Registrant),
+		Admin:      pick(current.Admin, target.Admin),
+		Tech:       pick(current.Tech, target.Tech),
+		Billing:    pick(current.Billing, target.Billing),
+	}
+}
+
+// revertDomainContacts returns the previous handles for all handles that were changed
+func revertDomainContacts(previous, changed inwx.DomainContacts) inwx.DomainContacts {
+	pick := func(old, new int) int {
+		if new == 0 {
+			return 0
+		}
+		return old
+	}
+
+	return inwx.DomainContacts{
+		Registrant: pick(previous.Registrant, changed.Registrant),
+		Admin:      pick(previous.Admin, changed.Admin),
+		Tech:       pick(previous.Tech, changed.Tech),
+		Billing:    pick(previous.Billing, changed.Billing),
+	}
+}
+
+// formatHandleChange renders a contact handle for the preview table, showing "old → new" if it changes
+func formatHandleChange(current, new int) string {
+	old := "-"
+	if current > 0 {
+		old = strconv.Itoa(current)
+	}
+	if new == 0 {
+		return old
+	}
+	return fmt.Sprintf("%s → %d", old, new)
+}
+
+// summarizeHandleChanges describes the changed handles, e.g. "admin=111 → 222 tech=111 → 333"
+func summarizeHandleChanges(current, changes inwx.DomainContacts) string {
+	var parts []string
+	for _, h := range []struct {
+		name        string
+		current, to int
+	}{
+		{"registrant", current.Registrant, changes.Registrant},
+		{"admin", current.Admin, changes.Admin},
+		{"tech", current.Tech, changes.Tech},
+		{"billing", current.Billing, changes.Billing},
+	} {
+		if h.to != 0 {
+			parts = append(parts, h.name+"="+formatHandleChange(h.current, h.to))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// domainContactsMap converts contact handles into the representation stored in backup entries
+func domainContactsMap(contacts inwx.DomainContacts) map[string]interface{} {
+	return map[string]interface{}{
+		"registrant": contacts.Registrant,
+		"admin":      contacts.Admin,
+		"tech":       contacts.Tech,
+		"billing":    contacts.Billing,
+	}
+}
+
+// domainContactsFromBackup restores contact handles from the context of a backup entry
+func domainContactsFromBackup(entry *inwx.BackupEntry, key string) (inwx.DomainContacts, error) {
+	data, ok := entry.Context[key].(map[string]interface{})
+	if !ok {
+		return inwx.DomainContacts{}, fmt.Errorf("backup entry has no %s contact handles", key)
+	}
+
+	handle := func(name string) int {
+		if v, ok := data[name].(float64); ok {
+			return int(v)
+		}
+		if v, ok := data[name].(int); ok {
+			return v
+		}
+		return 0
+	}
+
+	return inwx.DomainContacts{
+		Registrant: handle("registrant"),
+		Admin:      handle("admin"),
+		Tech:       handle("tech"),
+		Billing:    handle("billing"),
+	}, nil
+}
+
+func showDomainWhois(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one domain must be specified")
+	}
+	domain := strings.ToLower(c.Args().First())
+	if err := utils.ValidateDomain(domain); err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	whois, err := client.Domain().Whois(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatDomainWhois(whois)
+		case *output.JSONFormatter:
+			return f.FormatDomainWhois(whois)
+		case *output.YAMLFormatter:
+			return f.FormatDomainWhois(whois)
+		case *output.CSVFormatter:
+			return f.FormatDomainWhois(whois)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func showDomainLog(c *cli.Context) error {
+	if c.NArg() > 1 {
+		return fmt.Errorf("at most one domain can be specified")
+	}
+	domain := strings.ToLower(c.Args().First())
+
+	var from, to time.Time
+	if since := c.String("since"); since != "" {
+		duration, err := utils.ParseDuration(since)
+		if err != nil {
+			return err
+		}
+		from = time.Now().Add(-duration)
+	}
+	if ts := c.Timestamp("from"); ts != nil {
+		if !from.IsZero() {
+			return fmt.Errorf("--since and --from cannot be combined")
+		}
+		from = *ts
+	}
+	if ts := c.Timestamp("to"); ts != nil {
+		to = *ts
+	}
+
+	page := c.Int("page")
+	limit := c.Int("limit")
+	if page < 1 || limit < 1 {
+		return fmt.Errorf("--page and --limit must be positive")
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	filters := []inwx.DomainLogFilter{
+		inwx.WithLogDateRange(from, to),
+		inwx.WithLogStatus(c.String("status")),
+	}
+
+	domainService := client.Domain()
+	domainLog, err := domainService.Log(ctx, domain, append(filters, inwx.WithLogPage(page, limit))...)
+	if err != nil {
+		return err
+	}
+
+	// Collect the remaining pages into a single result
+	if c.Bool("all") {
+		remaining := domainLog.Count - (page-1)*limit
+		for next := page + 1; len(domainLog.Entries) < remaining; next++ {
+			nextLog, err := domainService.Log(ctx, domain, append(filters, inwx.WithLogPage(next, limit))...)
+			if err != nil {
+				return err
+			}
+			if len(nextLog.Entries) == 0 {
+				break
+			}
+			domainLog.Entries = append(domainLog.Entries, nextLog.Entries...)
+		}
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatDomainLog(domainLog)
+		case *output.JSONFormatter:
+			return f.FormatDomainLog(domainLog)
+		case *output.YAMLFormatter:
+			return f.FormatDomainLog(domainLog)
+		case *output.CSVFormatter:
+			return f.FormatDomainLog(domainLog)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func setDomainNameservers(c *cli.Context) error {
+	if len(c.StringSlice("ns")) == 0 && c.String("nsset") == "" {
+		return fmt.Errorf("either --ns or --nsset must be specified")
+	}
+
+	dryRun := c.Bool("dry-run")
+	maxDomains := c.Int("max")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	nameservers, err := resolveNameserverFlags(ctx, client, c)
+	if err != nil {
+		return err
+	}
+
+	targets, err := resolveDomainTargets(ctx, client, c.Args().Slice(), c.StringSlice("pattern"))
+	if err != nil {
+		return err
+	}
+
+	// Look up the current nameservers to skip unchanged domains and show a preview
+	domainService := client.Domain(inwx.WithDomainTesting(dryRun))
+	type change struct {
+		domain  string
+		current []string
+	}
+	var changes []change
+	for _, domain := range targets {
+		info, err := domainService.Info(ctx, domain)
+		if err != nil {
+			log.Warn().Err(err).Str("domain", domain).Msg("Failed to get domain info, skipping")
+			continue
+		}
+		if sameNameservers(info.Nameservers, nameservers) {
+			log.Debug().Str("domain", domain).Msg("Nameservers already set, skipping")
+			continue
+		}
+		changes = append(changes, change{domain: domain, current: info.Nameservers})
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("All matching domains already use %s\n", strings.Join(nameservers, ", "))
+		return nil
+	}
+
+	if maxDomains > 0 && len(changes) > maxDomains {
+		return fmt.Errorf("found %d domains to change, which exceeds the safety limit of %d - refine your selection or increase --max", len(changes), maxDomains)
+	}
+
+	fmt.Printf("Changing nameservers of %d domain(s) to %s:\n", len(changes), strings.Join(nameservers, ", "))
+	fmt.Printf("%-40s %s\n", "Domain", "Current")
+	fmt.Println(strings.Repeat("-", 66))
+	for _, ch := range changes {
+		current := strings.Join(ch.current, ", ")
+		if current == "" {
+			current = "-"
+		}
+		fmt.Printf("%-40s %s\n", ch.domain, current)
+	}
+
+	if !dryRun {
+		confirmed, err := utils.AskSimpleConfirmation("Continue?", c.Bool("yes"))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	failed := 0
+	for _, ch := range changes {
+		if err := domainService.SetNameservers(ctx, ch.domain, nameservers); err != nil {
+			log.Error().Err(err).Str("domain", ch.domain).Msg("Failed to set nameservers")
+			failed++
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("\nDry run mode - %d change(s) validated in API testing mode\n", len(changes)-failed)
+	} else {
+		log.Info().Msgf("Updated nameservers of %d domain(s)", len(changes)-failed)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to update %d of %d domain(s)", failed, len(changes))
+	}
+
+	return nil
+}
+
+// sameNameservers reports whether two nameserver lists contain the same hosts in any order
+func sameNameservers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	seen := make(map[string]bool, len(a))
+	for _, ns := range a {
+		seen[normalizeHostname(ns)] = true
+	}
+	for _, ns := range b {
+		if !seen[normalizeHostname(ns)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func registerDomain(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one domain must be specified")
+	}
+	domain := strings.ToLower(strings.TrimSuffix(c.Args().First(), "."))
+	if err := utils.ValidateDomain(domain); err != nil {
+		return fmt.Errorf("invalid domain %s: %w", domain, err)
+	}
+
+	renewalMode := strings.ToUpper(c.String("renewal-mode"))
+	if renewalMode != "" && !inwx.IsValidRenewalMode(renewalMode) {
+		return fmt.Errorf("invalid renewal mode %q (must be one of %s)", renewalMode, strings.Join(inwx.RenewalModes, ", "))
+	}
+
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	nameservers, err := resolveNameserverFlags(ctx, client, c)
+	if err != nil {
+		return err
+	}
+
+	registration := inwx.DomainRegistration{
+		Domain: domain,
+		Period: strings.ToUpper(c.String("period")),
+		Contacts: inwx.DomainContacts{
+			Registrant: c.Int("registrant"),
+			Admin:      c.Int("admin"),
+			Tech:       c.Int("tech"),
+			Billing:    c.Int("billing"),
+		},
+		Nameservers: nameservers,
+		RenewalMode: renewalMode,
+	}
+
+	domainService := client.Domain(inwx.WithDomainTesting(dryRun))
+
+	fmt.Printf("Registering %s for %s\n", domain, registration.Period)
+	if len(nameservers) > 0 {
+		fmt.Printf("Nameservers: %s\n", strings.Join(nameservers, ", "))
+	}
+	if prices, err := domainService.Prices(ctx, "reg", []string{domain}); err != nil {
+		log.Debug().Err(err).Msg("Failed to get registration price")
+	} else if price, ok := prices[domain]; ok {
+		fmt.Printf("Price: %.2f %s\n", price.Price, price.Currency)
+	}
+
+	if !dryRun {
+		confirmed, err := utils.AskSimpleConfirmation("Continue?", c.Bool("yes"))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	result, err := domainService.Register(ctx, registration)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("✓ %s can be registered (validated in API testing mode)\n", domain)
+		return nil
+	}
+
+	message := fmt.Sprintf("✓ %s registered", domain)
+	if result.Price > 0 {
+		message += fmt.Sprintf(" (%.2f %s)", result.Price, result.Currency)
+	}
+	fmt.Println(message)
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,8 +11,10 @@ import (
 	"github.com/BurntSushi/toml"
 	"github.com/adrg/xdg"
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
 
 	"github.com/nmeilick/inwx-cli/internal/cli/output"
+	"github.com/nmeilick/inwx-cli/internal/utils"
 	"github.com/nmeilick/inwx-cli/pkg/inwx"
 )
 
@@ -31,6 +34,9 @@ type Config struct {
 		Level  string `toml:"level"`
 		Colors bool   `toml:"colors"`
 	} `toml:"logging"`
+	Messages struct {
+		Hooks map[string]string `toml:"hooks"`
+	} `toml:"messages"`
 }
 
 // locateConfigFile determines the configuration file path using XDG spec.
@@ -222,3 +228,160 @@ func formatOutput(c *cli.Context, formatFunc func(interface{}) string) error {
 
 	return nil
 }
+
+// decodeInputFile reads a JSON or YAML input file into v. Files ending in .json are
+// decoded as JSON, everything else as YAML.
+func decodeInputFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("failed to parse JSON file %s: %w", path, err)
+		}
+		return nil
+	}
+
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse YAML file %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeDocumentFile writes a downloaded document to path, asking before an existing file is overwritten
+func writeDocumentFile(c *cli.Context, path string, data []byte) error {
+	if _, err := os.Stat(path); err == nil {
+		confirmed, err := utils.AskSimpleConfirmation(fmt.Sprintf("File %s exists. Overwrite?", path), c.Bool("yes"))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("not overwriting existing file %s", path)
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	fmt.Printf("✓ Saved %s (%d bytes)\n", path, len(data))
+	return nil
+}
+
+// updateConfigPassword replaces the password key of the [api] section in the TOML file at
+// path, keeping all other content. The file is replaced atomically via a temporary file.
+func updateConfigPassword(path, password string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to access config file: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	newline := "\n"
+	if strings.Contains(string(data), "\r\n") {
+		newline = "\r\n"
+	}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	entry := "password = " + tomlQuote(password)
+
+	section := ""
+	apiHeader := -1
+	replaced := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			if end := strings.Index(trimmed, "]"); end > 0 {
+				section = strings.TrimSpace(strings.Trim(trimmed[:end+1], "[]"))
+			}
+			if section == "api" {
+				apiHeader = i
+			}
+			continue
+		}
+		if section != "api" {
+			continue
+		}
+		key, _, found := strings.Cut(trimmed, "=")
+		if found && strings.TrimSpace(key) == "password" {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			lines[i] = indent + entry
+			replaced = true
+			break
+		}
+	}
+
+	if !replaced {
+		if apiHeader >= 0 {
+			lines = append(lines[:apiHeader+1], append([]string{entry}, lines[apiHeader+1:]...)...)
+		} else {
+			if len(lines) > 0 && lines[len(lines)-1] == "" {
+				lines = lines[:len(lines)-1]
+			}
+			lines = append(lines, "", "[api]", entry, "")
+		}
+	}
+
+	// Make sure the result is still a valid configuration before replacing the file
+	content := strings.Join(lines, "\n")
+	var check Config
+	if _, err := toml.Decode(content, &check); err != nil {
+		return fmt.Errorf("refusing to write invalid config file: %w", err)
+	}
+	if check.API.Password != password {
+		return fmt.Errorf("failed to update the password in %s", path)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary config file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.WriteString(strings.ReplaceAll(content, "\n", newline)); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary config file: %w", err)
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set config file permissions: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync temporary config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary config file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace config file: %w", err)
+	}
+
+	return nil
+}
+
+// tomlQuote encodes s as a TOML basic string
+func tomlQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, "\\u%04X", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,318 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/cli/output"
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+func HostCommand() *cli.Command {
+	ipFlag := &cli.StringSliceFlag{
+		Name:     "ip",
+		Usage:    "IPv4 or IPv6 address of the host (can be repeated)",
+		Required: true,
+	}
+	dryRunFlag := &cli.BoolFlag{
+		Name:    "dry-run",
+		Aliases: []string{"R"},
+		Usage:   "Validate the change in API testing mode without executing it",
+	}
+
+	return &cli.Command{
+		Name:  "host",
+		Usage: "Host (glue record) management for nameservers inside your domains",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List hosts",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "search",
+						Aliases: []string{"s"},
+						Usage:   "Hostname search string with wildcards (e.g., *.example.com)",
+					},
+				},
+				Action: listHosts,
+			},
+			{
+				Name:      "info",
+				Usage:     "Show host details",
+				ArgsUsage: "<hostname>",
+				Action:    showHost,
+			},
+			{
+				Name:      "create",
+				Usage:     "Register a host with its IP addresses",
+				ArgsUsage: "<hostname>",
+				Flags:     []cli.Flag{ipFlag, dryRunFlag},
+				Action:    createHost,
+			},
+			{
+				Name:      "update",
+				Usage:     "Replace the IP addresses of a host",
+				ArgsUsage: "<hostname>",
+				Flags:     []cli.Flag{ipFlag, dryRunFlag},
+				Action:    updateHost,
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete hosts",
+				ArgsUsage: "<hostname>...",
+				Flags:     []cli.Flag{dryRunFlag},
+				Action:    deleteHosts,
+			},
+		},
+	}
+}
+
+func listHosts(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	hosts, err := client.Host().List(ctx, c.String("search"))
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatHosts(hosts)
+		case *output.JSONFormatter:
+			return f.FormatHosts(hosts)
+		case *output.YAMLFormatter:
+			return f.FormatHosts(hosts)
+		case *output.CSVFormatter:
+			return f.FormatHosts(hosts)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func showHost(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one hostname must be specified")
+	}
+	hostname := normalizeHostname(c.Args().First())
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	host, err := client.Host().Info(ctx, hostname)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatHost(host)
+		case *output.JSONFormatter:
+			return f.FormatHost(host)
+		case *output.YAMLFormatter:
+			return f.FormatHost(host)
+		case *output.CSVFormatter:
+			return f.FormatHost(host)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func createHost(c *cli.Context) error {
+	return saveHost(c, true)
+}
+
+func updateHost(c *cli.Context) error {
+	return saveHost(c, false)
+}
+
+// saveHost creates or updates a host after validating the hostname and addresses
+func saveHost(c *cli.Context, create bool) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one hostname must be specified")
+	}
+	hostname := normalizeHostname(c.Args().First())
+	if err := utils.ValidateDomain(hostname); err != nil {
+		return fmt.Errorf("invalid hostname %s: %w", hostname, err)
+	}
+
+	ips, err := parseHostIPs(c.StringSlice("ip"))
+	if err != nil {
+		return err
+	}
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	hosts := client.Host(inwx.WithHostTesting(dryRun))
+
+	if create {
+		roID, err := hosts.Create(ctx, hostname, ips)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			fmt.Printf("✓ Host %s can be created (validated in API testing mode)\n", hostname)
+		} else {
+			fmt.Printf("✓ Host %s created with %s (ID: %d)\n", hostname, strings.Join(ips, ", "), roID)
+		}
+		return nil
+	}
+
+	if err := hosts.Update(ctx, hostname, ips); err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Printf("✓ Host %s can be updated (validated in API testing mode)\n", hostname)
+	} else {
+		fmt.Printf("✓ Host %s updated to %s\n", hostname, strings.Join(ips, ", "))
+	}
+
+	return nil
+}
+
+func deleteHosts(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("at least one hostname must be specified")
+	}
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	hosts := client.Host(inwx.WithHostTesting(dryRun))
+
+	skipPrompt := c.Bool("yes") || dryRun
+	failed := 0
+	for _, arg := range c.Args().Slice() {
+		hostname := normalizeHostname(arg)
+
+		if !skipPrompt {
+			label := hostname
+			if host, err := hosts.Info(ctx, hostname); err == nil && len(host.IPs) > 0 {
+				label = fmt.Sprintf("%s (%s)", hostname, strings.Join(host.IPs, ", "))
+			}
+
+			result, err := utils.AskConfirmation(fmt.Sprintf("Delete host %s?", label), false)
+			if err != nil {
+				return err
+			}
+			if result == utils.ConfirmationCancel {
+				fmt.Println("Operation cancelled")
+				break
+			}
+			if result == utils.ConfirmationNo {
+				continue
+			}
+			if result == utils.ConfirmationAll {
+				skipPrompt = true
+			}
+		}
+
+		if err := hosts.Delete(ctx, hostname); err != nil {
+			log.Error().Err(err).Str("host", hostname).Msg("Failed to delete host")
+			failed++
+			continue
+		}
+
+		if dryRun {
+			fmt.Printf("✓ Host %s can be deleted (validated in API testing mode)\n", hostname)
+		} else {
+			fmt.Printf("✓ Host %s deleted\n", hostname)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d host(s)", failed)
+	}
+
+	return nil
+}
+
+// normalizeHostname lowercases a hostname and strips the trailing dot
+func normalizeHostname(hostname string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(hostname), "."))
+}
+
+// parseHostIPs validates the --ip values, accepting comma separated lists
+func parseHostIPs(values []string) ([]string, error) {
+	var ips []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, ip := range strings.Split(value, ",") {
+			ip = strings.TrimSpace(ip)
+			if ip == "" || seen[ip] {
+				continue
+			}
+			if err := utils.ValidateIP(ip); err != nil {
+				return nil, err
+			}
+			seen[ip] = true
+			ips = append(ips, ip)
+		}
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("at least one IP address must be specified with --ip")
+	}
+
+	return ips, nil
+}
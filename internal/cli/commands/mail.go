@@ -0,0 +1,668 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+func MailCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "mail",
+		Usage: "Audit and manage SPF, DKIM and DMARC records",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "audit",
+				Usage: "Check the SPF, DKIM and DMARC records of a domain",
+				Description: "Includes of the SPF record are resolved recursively to count the DNS lookups\n" +
+					"   against the limit of 10. DKIM keys are detected below _domainkey.",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "domain",
+						Aliases:  []string{"d"},
+						Usage:    "Domain to audit",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "severity",
+						Usage: "Minimum severity to report (error, warning, info)",
+						Value: "info",
+					},
+				},
+				Action: auditMail,
+			},
+			{
+				Name:  "spf",
+				Usage: "Manage the SPF record",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "flatten",
+						Usage: "Resolve the includes of an SPF record to networks",
+						Description: "The includes of the source record are resolved to ip4 and ip6 mechanisms to stay\n" +
+							"   below the limit of 10 DNS lookups. Networks that do not fit into the record are\n" +
+							"   moved to chained records _spf1, _spf2, ... Only records that changed are updated.\n" +
+							"   Run it regularly with --check to detect changes of the included records.",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "domain",
+								Aliases:  []string{"d"},
+								Usage:    "Domain to publish the SPF record for",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "source",
+								Usage:    "SPF record with includes to flatten",
+								Required: true,
+							},
+							&cli.IntFlag{
+								Name:  "max-length",
+								Usage: "Maximum length of a single record",
+								Value: 450,
+							},
+							&cli.IntFlag{
+								Name:  "ttl",
+								Usage: "TTL value",
+								Value: 3600,
+							},
+							&cli.BoolFlag{
+								Name:  "check",
+								Usage: "Only check whether the published records are up to date, exit non-zero if not",
+							},
+							&cli.BoolFlag{
+								Name:    "dry-run",
+								Aliases: []string{"R"},
+								Usage:   "Only show the changes",
+							},
+						},
+						Action: flattenSPF,
+					},
+				},
+			},
+			{
+				Name:  "dmarc",
+				Usage: "Manage the DMARC policy",
+				Subcommands: []*cli.Command{
+					{
+						Name:        "set",
+						Usage:       "Create or update the DMARC policy of a domain",
+						Description: "Tags that are not given keep the value of the current policy.",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "domain",
+								Aliases:  []string{"d"},
+								Usage:    "Domain to set the policy for",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "policy",
+								Usage: "Policy for failing mail (none, quarantine, reject)",
+							},
+							&cli.StringFlag{
+								Name:  "sp",
+								Usage: "Policy for subdomains (none, quarantine, reject)",
+							},
+							&cli.IntFlag{
+								Name:  "pct",
+								Usage: "Percentage of failing mail the policy applies to",
+								Value: 100,
+							},
+							&cli.StringSliceFlag{
+								Name:  "rua",
+								Usage: "Address for aggregate reports (can be repeated)",
+							},
+							&cli.StringSliceFlag{
+								Name:  "ruf",
+								Usage: "Address for failure reports (can be repeated)",
+							},
+							&cli.StringFlag{
+								Name:  "adkim",
+								Usage: "DKIM alignment mode (r = relaxed, s = strict)",
+							},
+							&cli.StringFlag{
+								Name:  "aspf",
+								Usage: "SPF alignment mode (r = relaxed, s = strict)",
+							},
+							&cli.IntFlag{
+								Name:  "ttl",
+								Usage: "TTL value",
+								Value: 3600,
+							},
+							&cli.BoolFlag{
+								Name:    "dry-run",
+								Aliases: []string{"R"},
+								Usage:   "Only show the record that would be set",
+							},
+						},
+						Action: setDMARC,
+					},
+				},
+			},
+			{
+				Name:  "dkim",
+				Usage: "Manage DKIM keys",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "add",
+						Usage: "Publish a DKIM public key",
+						Description: "The key is read from a PEM file with an RSA or Ed25519 public or private key.\n" +
+							"   Only the public key is published, long keys are split into several strings.",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "domain",
+								Aliases:  []string{"d"},
+								Usage:    "Domain to publish the key for",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "selector",
+								Usage:    "DKIM selector",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "pubkey",
+								Usage:    "PEM file with the key",
+								Required: true,
+							},
+							&cli.IntFlag{
+								Name:  "ttl",
+								Usage: "TTL value",
+								Value: 3600,
+							},
+							&cli.BoolFlag{
+								Name:    "dry-run",
+								Aliases: []string{"R"},
+								Usage:   "Only show the record that would be set",
+							},
+						},
+						Action: addDKIM,
+					},
+				},
+			},
+		},
+	}
+}
+
+func auditMail(c *cli.Context) error {
+	domain := normalizeHostname(c.String("domain"))
+	if err := utils.ValidateDomain(domain); err != nil {
+		return err
+	}
+
+	validSeverities := map[string]int{
+		"error":   3,
+		"warning": 2,
+		"info":    1,
+	}
+	minLevel, ok := validSeverities[strings.ToLower(c.String("severity"))]
+	if !ok {
+		return fmt.Errorf("invalid severity level: %s (must be error, warning, or info)", c.String("severity"))
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	audit, err := client.DNS().AuditMail(ctx, domain, net.DefaultResolver)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Mail audit for %s\n", domain)
+
+	fmt.Println("\nSPF:")
+	if audit.SPF == nil {
+		fmt.Println("  (none)")
+	} else {
+		fmt.Printf("  %s\n", audit.SPF.Record)
+		fmt.Printf("  DNS lookups: %d/%d\n", audit.SPF.Lookups, inwx.SPFLookupLimit)
+		printSPFIncludes(audit.SPF.Includes, "    ")
+	}
+
+	fmt.Println("\nDKIM:")
+	if len(audit.DKIM) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, key := range audit.DKIM {
+		var details []string
+		switch {
+		case key.Revoked:
+			details = append(details, "revoked")
+		case key.KeyType != "":
+			details = append(details, fmt.Sprintf("%s %d bits", key.KeyType, key.Bits))
+		}
+		if key.Target != "" {
+			details = append(details, "via "+key.Target)
+		}
+		fmt.Printf("  %s: %s\n", key.Selector, strings.Join(details, ", "))
+	}
+
+	fmt.Println("\nDMARC:")
+	if audit.DMARC == nil {
+		fmt.Println("  (none)")
+	} else {
+		fmt.Printf("  %s\n", audit.DMARC)
+	}
+
+	errorCount := 0
+	shown := 0
+	for _, issue := range audit.Issues {
+		if issue.Severity == "error" {
+			errorCount++
+		}
+		if validSeverities[issue.Severity] < minLevel {
+			continue
+		}
+		printValidationIssue(issue)
+		shown++
+	}
+
+	if shown == 0 {
+		fmt.Println("\n✓ No issues found")
+	} else {
+		fmt.Printf("\nSummary for %s:", domain)
+		if audit.Summary.Errors > 0 {
+			fmt.Printf(" %d errors", audit.Summary.Errors)
+		}
+		if audit.Summary.Warnings > 0 {
+			fmt.Printf(" %d warnings", audit.Summary.Warnings)
+		}
+		if audit.Summary.Info > 0 {
+			fmt.Printf(" %d info", audit.Summary.Info)
+		}
+		fmt.Println()
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("mail audit found %d error(s)", errorCount)
+	}
+	return nil
+}
+
+// printSPFIncludes prints the include tree of an SPF record
+func printSPFIncludes(nodes []*inwx.SPFNode, indent string) {
+	for _, node := range nodes {
+		switch {
+		case node.Error != "":
+			fmt.Printf("%s%s: %s\n", indent, node.Domain, node.Error)
+		case node.Lookups > 0:
+			fmt.Printf("%s%s (%d lookups)\n", indent, node.Domain, node.Lookups)
+		default:
+			fmt.Printf("%s%s\n", indent, node.Domain)
+		}
+		printSPFIncludes(node.Includes, indent+"  ")
+	}
+}
+
+// spfChange is a pending change of a flattened SPF record
+type spfChange struct {
+	action  string // "create", "update", "delete" or "unchanged"
+	name    string
+	value   string
+	current []inwx.DNSRecord
+}
+
+func flattenSPF(c *cli.Context) error {
+	domain := normalizeHostname(c.String("domain"))
+	if err := utils.ValidateDomain(domain); err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	flat, err := inwx.FlattenSPF(ctx, net.DefaultResolver, domain, c.String("source"), c.Int("max-length"))
+	if err != nil {
+		return fmt.Errorf("failed to flatten SPF record: %w", err)
+	}
+
+	records, err := dns.ListRecords(ctx, inwx.WithDomainFilter(domain))
+	if err != nil {
+		return fmt.Errorf("failed to list records of %s: %w", domain, err)
+	}
+
+	ttl := c.Int("ttl")
+	var changes []spfChange
+	for _, record := range flat.Records {
+		change := spfChange{name: record.Name, value: record.Value, action: "unchanged"}
+		if record.Name == "@" {
+			change.current = inwx.FindTXT(records, "@", inwx.IsSPF)
+		} else {
+			change.current = inwx.FindTXT(records, record.Name, nil)
+		}
+
+		switch {
+		case len(change.current) == 0:
+			change.action = "create"
+		case len(change.current) > 1 || inwx.TXTValue(change.current[0].Content) != record.Value || change.current[0].TTL != ttl:
+			change.action = "update"
+		}
+		changes = append(changes, change)
+	}
+
+	// Chain records beyond the new end of the chain are no longer referenced
+	for _, r := range records {
+		if r.Type != "TXT" || !inwx.IsSPFChainName(r.Name) || !inwx.IsSPF(inwx.TXTValue(r.Content)) {
+			continue
+		}
+		used := false
+		for _, record := range flat.Records {
+			if strings.EqualFold(record.Name, r.Name) {
+				used = true
+			}
+		}
+		if !used {
+			changes = append(changes, spfChange{action: "delete", name: r.Name, current: []inwx.DNSRecord{r}})
+		}
+	}
+
+	fmt.Printf("SPF record of %s: %d networks, %d DNS lookups\n", domain, flat.Networks, flat.Lookups)
+	for _, warning := range flat.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+
+	pending := 0
+	for _, change := range changes {
+		label := spfRecordLabel(domain, change.name)
+		switch change.action {
+		case "create":
+			fmt.Printf("+ %s TXT %s\n", label, change.value)
+		case "update":
+			fmt.Printf("~ %s TXT %s\n", label, inwx.TXTValue(change.current[0].Content))
+			fmt.Printf("  → %s\n", change.value)
+		case "delete":
+			fmt.Printf("- %s TXT %s\n", label, inwx.TXTValue(change.current[0].Content))
+		default:
+			fmt.Printf("= %s TXT %s\n", label, change.value)
+		}
+		if change.action != "unchanged" {
+			pending++
+		}
+	}
+
+	if pending == 0 {
+		fmt.Printf("✓ SPF records of %s are up to date\n", domain)
+		return nil
+	}
+	if c.Bool("check") {
+		return fmt.Errorf("SPF records of %s are out of date (%d changes)", domain, pending)
+	}
+	if c.Bool("dry-run") {
+		fmt.Println("Dry run - no changes were made")
+		return nil
+	}
+
+	confirmed, err := utils.AskSimpleConfirmation("Apply these changes?", c.Bool("yes"))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("❌ Changes cancelled")
+		return nil
+	}
+
+	// Complete the chain from its end before the root record references it, and remove
+	// stale records last
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if change.action != "create" && change.action != "update" {
+			continue
+		}
+		match := func(string) bool { return true }
+		if change.name == "@" {
+			match = inwx.IsSPF
+		}
+		if _, err := dns.ReplaceTXT(ctx, domain, change.name, change.value, ttl, match); err != nil {
+			return fmt.Errorf("failed to set %s: %w", spfRecordLabel(domain, change.name), err)
+		}
+		fmt.Printf("✓ Set %s\n", spfRecordLabel(domain, change.name))
+	}
+	for _, change := range changes {
+		if change.action != "delete" {
+			continue
+		}
+		if err := dns.DeleteRecord(ctx, change.current[0].ID); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", spfRecordLabel(domain, change.name), err)
+		}
+		fmt.Printf("✓ Deleted %s\n", spfRecordLabel(domain, change.name))
+	}
+
+	return nil
+}
+
+// spfRecordLabel returns the full name of a record of a flattened SPF chain
+func spfRecordLabel(domain, name string) string {
+	if name == "@" {
+		return domain
+	}
+	return name + "." + domain
+}
+
+func setDMARC(c *cli.Context) error {
+	domain := normalizeHostname(c.String("domain"))
+	if err := utils.ValidateDomain(domain); err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	records, err := dns.ListRecords(ctx, inwx.WithDomainFilter(domain))
+	if err != nil {
+		return fmt.Errorf("failed to list records of %s: %w", domain, err)
+	}
+	current := inwx.FindTXT(records, inwx.DMARCName, inwx.IsDMARC)
+
+	// Start from the current policy so that only the given tags change
+	policy := &inwx.DMARCPolicy{Percent: 100}
+	if len(current) > 0 {
+		if parsed, err := inwx.ParseDMARC(inwx.TXTValue(current[0].Content)); err == nil {
+			policy = parsed
+		} else {
+			fmt.Printf("⚠️  Replacing invalid DMARC record: %v\n", err)
+		}
+	}
+
+	if c.IsSet("policy") {
+		policy.Policy = strings.ToLower(c.String("policy"))
+	}
+	if c.IsSet("sp") {
+		policy.SubdomainPolicy = strings.ToLower(c.String("sp"))
+	}
+	if c.IsSet("pct") {
+		policy.Percent = c.Int("pct")
+	}
+	if c.IsSet("rua") {
+		policy.RUA = mailtoURIs(c.StringSlice("rua"))
+	}
+	if c.IsSet("ruf") {
+		policy.RUF = mailtoURIs(c.StringSlice("ruf"))
+	}
+	if c.IsSet("adkim") {
+		policy.ADKIM = strings.ToLower(c.String("adkim"))
+	}
+	if c.IsSet("aspf") {
+		policy.ASPF = strings.ToLower(c.String("aspf"))
+	}
+	if policy.Policy == "" {
+		return fmt.Errorf("%s has no DMARC policy yet, --policy is required", domain)
+	}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	return setTXTRecord(c, ctx, dns, domain, inwx.DMARCName, policy.String(), current)
+}
+
+// mailtoURIs converts report addresses to mailto: URIs
+func mailtoURIs(values []string) []string {
+	var uris []string
+	for _, value := range parseCommaSeparatedValues(values) {
+		if !strings.HasPrefix(strings.ToLower(value), "mailto:") {
+			value = "mailto:" + value
+		}
+		uris = append(uris, value)
+	}
+	return uris
+}
+
+func addDKIM(c *cli.Context) error {
+	domain := normalizeHostname(c.String("domain"))
+	if err := utils.ValidateDomain(domain); err != nil {
+		return err
+	}
+
+	selector := strings.Trim(strings.ToLower(c.String("selector")), ".")
+	if selector == "" || strings.Contains(selector, "_domainkey") {
+		return fmt.Errorf("invalid selector %q", c.String("selector"))
+	}
+
+	data, err := os.ReadFile(c.String("pubkey"))
+	if err != nil {
+		return fmt.Errorf("failed to read key: %w", err)
+	}
+	value, err := inwx.DKIMRecordFromPEM(data)
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	records, err := dns.ListRecords(ctx, inwx.WithDomainFilter(domain))
+	if err != nil {
+		return fmt.Errorf("failed to list records of %s: %w", domain, err)
+	}
+
+	name := selector + "." + inwx.DKIMSuffix
+	for _, r := range records {
+		if r.Type == "CNAME" && strings.EqualFold(r.Name, name) {
+			return fmt.Errorf("selector %s is delegated to %s with a CNAME record (ID: %d), remove it first", selector, r.Content, r.ID)
+		}
+	}
+
+	return setTXTRecord(c, ctx, dns, domain, name, value, inwx.FindTXT(records, name, nil))
+}
+
+// setTXTRecord previews and applies the value of a TXT record that replaces the
+// current records
+func setTXTRecord(c *cli.Context, ctx context.Context, dns *inwx.DNSService, domain, name, value string, current []inwx.DNSRecord) error {
+	ttl := c.Int("ttl")
+	label := name + "." + domain
+
+	switch {
+	case len(current) == 0:
+		fmt.Printf("+ %s TXT %s\n", label, value)
+	case inwx.TXTValue(current[0].Content) == value && current[0].TTL == ttl && len(current) == 1:
+		fmt.Printf("✓ %s is up to date\n", label)
+		return nil
+	default:
+		fmt.Printf("~ %s TXT %s\n", label, inwx.TXTValue(current[0].Content))
+		fmt.Printf("  → %s\n", value)
+		for _, r := range current[1:] {
+			fmt.Printf("- %s TXT %s\n", label, inwx.TXTValue(r.Content))
+		}
+	}
+	if len(value) > inwx.MaxTXTStringLength {
+		fmt.Printf("  (split into %d strings of at most %d characters)\n",
+			(len(value)+inwx.MaxTXTStringLength-1)/inwx.MaxTXTStringLength, inwx.MaxTXTStringLength)
+	}
+
+	if c.Bool("dry-run") {
+		fmt.Println("Dry run - no changes were made")
+		return nil
+	}
+
+	confirmed, err := utils.AskSimpleConfirmation("Apply these changes?", c.Bool("yes"))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("❌ Changes cancelled")
+		return nil
+	}
+
+	match := func(v string) bool {
+		for _, r := range current {
+			if inwx.TXTValue(r.Content) == v {
+				return true
+			}
+		}
+		return false
+	}
+	if _, err := dns.ReplaceTXT(ctx, domain, name, value, ttl, match); err != nil {
+		return fmt.Errorf("failed to set %s: %w", label, err)
+	}
+
+	fmt.Printf("✓ Set %s\n", label)
+	return nil
+}
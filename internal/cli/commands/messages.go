@@ -0,0 +1,359 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/cli/output"
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+// defaultHookKey selects the hook that runs for message types without a dedicated hook
+const defaultHookKey = "*"
+
+func MessagesCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "messages",
+		Usage: "Registry notifications from the message queue",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "poll",
+				Usage: "Show queued messages (transfers, expiry warnings, registry events)",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "ack",
+						Usage: "Acknowledge messages after processing, draining the queue",
+					},
+					&cli.BoolFlag{
+						Name:    "follow",
+						Aliases: []string{"f"},
+						Usage:   "Keep polling for new messages and run hooks (requires --ack)",
+					},
+					&cli.DurationFlag{
+						Name:  "interval",
+						Usage: "Polling interval in follow mode",
+						Value: time.Minute,
+					},
+					&cli.StringSliceFlag{
+						Name:  "hook",
+						Usage: "Command to run per message in follow mode as TYPE=COMMAND (TYPE is DOMAIN, CERTIFICATE, CONTACT, HOST or * for all others)",
+					},
+					&cli.DurationFlag{
+						Name:  "hook-timeout",
+						Usage: "Maximum runtime of a hook command",
+						Value: 30 * time.Second,
+					},
+				},
+				Action: pollMessages,
+			},
+			{
+				Name:      "ack",
+				Usage:     "Acknowledge and remove messages from the queue",
+				ArgsUsage: "<message-id>...",
+				Action:    ackMessages,
+			},
+		},
+	}
+}
+
+func pollMessages(c *cli.Context) error {
+	if c.Bool("follow") {
+		return followMessages(c)
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	service := client.Message()
+	queue := &inwx.MessageQueue{}
+
+	msg, count, err := service.Poll(ctx)
+	if err != nil {
+		return err
+	}
+	queue.Count = count
+
+	if c.Bool("ack") {
+		// The API only returns the first queued message, so draining means
+		// acknowledging it before the next one becomes visible
+		seen := make(map[int]bool)
+		for msg != nil {
+			if seen[msg.ID] {
+				return fmt.Errorf("message %d is still queued after acknowledging it", msg.ID)
+			}
+			seen[msg.ID] = true
+
+			if err := service.Ack(ctx, msg.ID); err != nil {
+				return fmt.Errorf("failed to acknowledge message %d: %w", msg.ID, err)
+			}
+			queue.Messages = append(queue.Messages, *msg)
+
+			if msg, count, err = service.Poll(ctx); err != nil {
+				return err
+			}
+		}
+		queue.Count = count
+	} else if msg != nil {
+		queue.Messages = append(queue.Messages, *msg)
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatMessageQueue(queue)
+		case *output.JSONFormatter:
+			return f.FormatMessageQueue(queue)
+		case *output.YAMLFormatter:
+			return f.FormatMessageQueue(queue)
+		case *output.CSVFormatter:
+			return f.FormatMessageQueue(queue)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+// followMessages polls the queue until interrupted. Each new message is printed and
+// handed to the hook configured for its type, and acknowledged only after the hook
+// succeeded, so failed hooks are retried on the next poll. message.poll returns the
+// oldest message until it is acknowledged, so following requires --ack.
+func followMessages(c *cli.Context) error {
+	if !c.Bool("ack") {
+		return fmt.Errorf("--follow requires --ack: the queue only returns the next message once the current one is acknowledged")
+	}
+
+	interval := c.Duration("interval")
+	if interval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+
+	config, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+	hooks, err := parseMessageHooks(config.Messages.Hooks, c.StringSlice("hook"))
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	shutdown := utils.NewGracefulShutdown()
+	shutdown.Start()
+	pollCtx := shutdown.Context()
+
+	service := client.Message()
+	shown := 0
+	handled := 0
+
+	for {
+		// An expired session is renewed by the client, so the loop can run for days
+		msg, _, err := service.Poll(pollCtx)
+		switch {
+		case pollCtx.Err() != nil:
+			return nil
+		case err != nil:
+			log.Error().Err(err).Msg("Failed to poll messages")
+		case msg != nil:
+			if msg.ID != shown {
+				if err := printMessage(c, msg); err != nil {
+					return err
+				}
+				shown = msg.ID
+			}
+
+			// The hook of a message whose acknowledgement failed does not run again
+			if msg.ID != handled {
+				if err := runMessageHook(pollCtx, hooks, msg, c.Duration("hook-timeout")); err != nil {
+					log.Error().Err(err).Int("id", msg.ID).Msg("Message hook failed")
+					break
+				}
+				handled = msg.ID
+			}
+
+			if err := service.Ack(pollCtx, msg.ID); err != nil {
+				log.Error().Err(err).Int("id", msg.ID).Msg("Failed to acknowledge message")
+				break
+			}
+			// Fetch the next message right away to drain the queue
+			continue
+		}
+
+		select {
+		case <-pollCtx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func printMessage(c *cli.Context, msg *inwx.Message) error {
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatMessage(msg)
+		case *output.JSONFormatter:
+			return f.FormatMessage(msg)
+		case *output.YAMLFormatter:
+			return f.FormatMessage(msg)
+		case *output.CSVFormatter:
+			return f.FormatMessage(msg)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+// parseMessageHooks merges the hooks from the config file with TYPE=COMMAND flags,
+// the latter taking precedence
+func parseMessageHooks(configured map[string]string, flags []string) (map[string]string, error) {
+	hooks := make(map[string]string)
+
+	add := func(msgType, command string) error {
+		msgType = strings.ToUpper(strings.TrimSpace(msgType))
+		if msgType != defaultHookKey && !utils.ContainsString(inwx.MessageTypes, msgType) {
+			return fmt.Errorf("invalid message type %q for hook (must be one of %s or %s)", msgType, strings.Join(inwx.MessageTypes, ", "), defaultHookKey)
+		}
+		hooks[msgType] = command
+		return nil
+	}
+
+	for msgType, command := range configured {
+		if err := add(msgType, command); err != nil {
+			return nil, err
+		}
+	}
+	for _, flag := range flags {
+		msgType, command, ok := strings.Cut(flag, "=")
+		if !ok || strings.TrimSpace(command) == "" {
+			return nil, fmt.Errorf("invalid hook %q (expected TYPE=COMMAND)", flag)
+		}
+		if err := add(msgType, command); err != nil {
+			return nil, err
+		}
+	}
+
+	return hooks, nil
+}
+
+// runMessageHook runs the hook for the message type through the shell. The message is
+// passed as JSON on stdin and as INWX_MESSAGE_* environment variables.
+func runMessageHook(ctx context.Context, hooks map[string]string, msg *inwx.Message, timeout time.Duration) error {
+	command, ok := hooks[msg.Type]
+	if !ok {
+		command, ok = hooks[defaultHookKey]
+	}
+	if !ok || command == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(hookCtx, "sh", "-c", command)
+	cmd.Stdin = strings.NewReader(string(payload))
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"INWX_MESSAGE_ID="+strconv.Itoa(msg.ID),
+		"INWX_MESSAGE_TYPE="+msg.Type,
+		"INWX_MESSAGE_DATE="+msg.Date.Format(time.RFC3339),
+		"INWX_MESSAGE_OBJECT="+msg.Object,
+		"INWX_MESSAGE_STATUS="+msg.Status,
+		"INWX_MESSAGE_STATUS_DETAILS="+msg.StatusDetails,
+	)
+
+	log.Debug().Int("id", msg.ID).Str("type", msg.Type).Str("hook", command).Msg("Running message hook")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook %q: %w", command, err)
+	}
+
+	return nil
+}
+
+func ackMessages(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("at least one message ID must be specified")
+	}
+
+	var ids []int
+	for _, arg := range c.Args().Slice() {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("invalid message ID: %s", arg)
+		}
+		ids = append(ids, id)
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	service := client.Message()
+	failed := 0
+	for _, id := range ids {
+		if err := service.Ack(ctx, id); err != nil {
+			fmt.Printf("✗ Failed to acknowledge message %d: %v\n", id, err)
+			failed++
+			continue
+		}
+		fmt.Printf("✓ Acknowledged message %d\n", id)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d messages could not be acknowledged", failed, len(ids))
+	}
+
+	return nil
+}
@@ -0,0 +1,445 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/cli/output"
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+func NameserverSetCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "nsset",
+		Usage: "Nameserver set management",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List nameserver sets",
+				Action: listNameserverSets,
+			},
+			{
+				Name:      "info",
+				Usage:     "Show nameserver set details",
+				ArgsUsage: "<id|name>",
+				Action:    showNameserverSet,
+			},
+			{
+				Name:      "create",
+				Usage:     "Create a nameserver set",
+				ArgsUsage: "<name>",
+				Flags: append(nameserverSetFlags(true),
+					&cli.BoolFlag{
+						Name:  "hidden",
+						Usage: "Hide the set from the nameserver selection",
+					},
+				),
+				Action: createNameserverSet,
+			},
+			{
+				Name:      "update",
+				Usage:     "Update a nameserver set",
+				ArgsUsage: "<id|name>",
+				Flags: append(nameserverSetFlags(false),
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "Rename the set",
+					},
+				),
+				Action: updateNameserverSet,
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete nameserver sets",
+				ArgsUsage: "<id|name>...",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Validate the deletion in API testing mode without executing it",
+					},
+				},
+				Action: deleteNameserverSets,
+			},
+		},
+	}
+}
+
+// nameserverSetFlags returns the flags shared by nsset create and update
+func nameserverSetFlags(create bool) []cli.Flag {
+	defaultType := ""
+	if create {
+		defaultType = inwx.NameserverSetTypeExternal
+	}
+
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     "ns",
+			Usage:    "Nameserver hostname (can be repeated)",
+			Required: create,
+		},
+		&cli.StringFlag{
+			Name:  "type",
+			Usage: "Set type (PRIMARY, SECONDARY, EXTERNAL)",
+			Value: defaultType,
+		},
+		&cli.StringFlag{
+			Name:  "hostmaster",
+			Usage: "Email address of the hostmaster",
+		},
+		&cli.StringFlag{
+			Name:  "master-ip",
+			Usage: "IP address of the master server (SECONDARY sets)",
+		},
+		&cli.StringFlag{
+			Name:  "web",
+			Usage: "IP address or URL for the web nameserver entry",
+		},
+		&cli.StringFlag{
+			Name:  "mail",
+			Usage: "Mail nameserver entry",
+		},
+		&cli.BoolFlag{
+			Name:    "dry-run",
+			Aliases: []string{"R"},
+			Usage:   "Validate the change in API testing mode without executing it",
+		},
+	}
+}
+
+func listNameserverSets(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	sets, err := client.NameserverSet().List(ctx)
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatNameserverSets(sets)
+		case *output.JSONFormatter:
+			return f.FormatNameserverSets(sets)
+		case *output.YAMLFormatter:
+			return f.FormatNameserverSets(sets)
+		case *output.CSVFormatter:
+			return f.FormatNameserverSets(sets)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func showNameserverSet(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one nameserver set ID or name must be specified")
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	set, err := client.NameserverSet().Resolve(ctx, c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatNameserverSet(set)
+		case *output.JSONFormatter:
+			return f.FormatNameserverSet(set)
+		case *output.YAMLFormatter:
+			return f.FormatNameserverSet(set)
+		case *output.CSVFormatter:
+			return f.FormatNameserverSet(set)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+// nameserverSetFromFlags builds a nameserver set from the given flags and validates it
+func nameserverSetFromFlags(c *cli.Context) (inwx.NameserverSet, error) {
+	set := inwx.NameserverSet{
+		Type:       strings.ToUpper(c.String("type")),
+		Hostmaster: c.String("hostmaster"),
+		MasterIP:   c.String("master-ip"),
+		Web:        c.String("web"),
+		Mail:       c.String("mail"),
+	}
+
+	if set.Type != "" && !inwx.IsValidNameserverSetType(set.Type) {
+		return set, fmt.Errorf("invalid nameserver set type %q (must be one of %s)", set.Type, strings.Join(inwx.NameserverSetTypes, ", "))
+	}
+	if set.Hostmaster != "" {
+		if err := utils.ValidateEmail(set.Hostmaster); err != nil {
+			return set, fmt.Errorf("invalid hostmaster: %w", err)
+		}
+	}
+	if set.MasterIP != "" {
+		if err := utils.ValidateIP(set.MasterIP); err != nil {
+			return set, fmt.Errorf("invalid master IP: %w", err)
+		}
+	}
+
+	nameservers, err := parseNameservers(c.StringSlice("ns"))
+	if err != nil {
+		return set, err
+	}
+	set.Nameservers = nameservers
+
+	return set, nil
+}
+
+func createNameserverSet(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one nameserver set name must be specified")
+	}
+
+	set, err := nameserverSetFromFlags(c)
+	if err != nil {
+		return err
+	}
+	set.Name = c.Args().First()
+	set.Visible = !c.Bool("hidden")
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	id, err := client.NameserverSet(inwx.WithNameserverSetTesting(dryRun)).Create(ctx, set)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("✓ Nameserver set %s can be created (validated in API testing mode)\n", set.Name)
+	} else {
+		fmt.Printf("✓ Nameserver set %s created (ID: %d)\n", set.Name, id)
+	}
+
+	return nil
+}
+
+func updateNameserverSet(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one nameserver set ID or name must be specified")
+	}
+
+	set, err := nameserverSetFromFlags(c)
+	if err != nil {
+		return err
+	}
+	set.Name = c.String("name")
+	if set.Name == "" && set.Type == "" && len(set.Nameservers) == 0 && set.Hostmaster == "" &&
+		set.MasterIP == "" && set.Web == "" && set.Mail == "" {
+		return fmt.Errorf("no fields to update specified")
+	}
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	service := client.NameserverSet(inwx.WithNameserverSetTesting(dryRun))
+	current, err := service.Resolve(ctx, c.Args().First())
+	if err != nil {
+		return err
+	}
+	if current.ReadOnly {
+		return fmt.Errorf("nameserver set %s is read-only", nameserverSetLabel(current))
+	}
+
+	if err := service.Update(ctx, current.ID, set); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("✓ Nameserver set %s can be updated (validated in API testing mode)\n", nameserverSetLabel(current))
+	} else {
+		fmt.Printf("✓ Nameserver set %s updated\n", nameserverSetLabel(current))
+	}
+
+	return nil
+}
+
+func deleteNameserverSets(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("at least one nameserver set ID or name must be specified")
+	}
+	dryRun := c.Bool("dry-run")
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	service := client.NameserverSet(inwx.WithNameserverSetTesting(dryRun))
+
+	skipPrompt := c.Bool("yes") || dryRun
+	failed := 0
+	for _, ref := range c.Args().Slice() {
+		set, err := service.Resolve(ctx, ref)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to resolve nameserver set")
+			failed++
+			continue
+		}
+		label := nameserverSetLabel(set)
+
+		if !skipPrompt {
+			result, err := utils.AskConfirmation(fmt.Sprintf("Delete nameserver set %s (%s)?", label, strings.Join(set.Nameservers, ", ")), false)
+			if err != nil {
+				return err
+			}
+			if result == utils.ConfirmationCancel {
+				fmt.Println("Operation cancelled")
+				break
+			}
+			if result == utils.ConfirmationNo {
+				continue
+			}
+			if result == utils.ConfirmationAll {
+				skipPrompt = true
+			}
+		}
+
+		if err := service.Delete(ctx, set.ID); err != nil {
+			log.Error().Err(err).Str("nsset", label).Msg("Failed to delete nameserver set")
+			failed++
+			continue
+		}
+
+		if dryRun {
+			fmt.Printf("✓ Nameserver set %s can be deleted (validated in API testing mode)\n", label)
+		} else {
+			fmt.Printf("✓ Nameserver set %s deleted\n", label)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d nameserver set(s)", failed)
+	}
+
+	return nil
+}
+
+// nameserverSetLabel returns the name of a set along with its ID
+func nameserverSetLabel(set *inwx.NameserverSet) string {
+	if set.Name == "" {
+		return fmt.Sprintf("%d", set.ID)
+	}
+	return fmt.Sprintf("%s (ID: %d)", set.Name, set.ID)
+}
+
+// parseNameservers validates nameserver hostnames, accepting comma separated lists
+func parseNameservers(values []string) ([]string, error) {
+	var nameservers []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, ns := range strings.Split(value, ",") {
+			ns = normalizeHostname(ns)
+			if ns == "" || seen[ns] {
+				continue
+			}
+			if err := utils.ValidateDomain(ns); err != nil {
+				return nil, fmt.Errorf("invalid nameserver %s: %w", ns, err)
+			}
+			seen[ns] = true
+			nameservers = append(nameservers, ns)
+		}
+	}
+	return nameservers, nil
+}
+
+// resolveNameserverFlags returns the nameservers given with --ns or referenced by
+// --nsset; it returns nil if neither flag is set
+func resolveNameserverFlags(ctx context.Context, client *inwx.Client, c *cli.Context) ([]string, error) {
+	nameservers, err := parseNameservers(c.StringSlice("ns"))
+	if err != nil {
+		return nil, err
+	}
+
+	ref := c.String("nsset")
+	if ref == "" {
+		return nameservers, nil
+	}
+	if len(nameservers) > 0 {
+		return nil, fmt.Errorf("--ns and --nsset cannot be combined")
+	}
+
+	set, err := client.NameserverSet().Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(set.Nameservers) == 0 {
+		return nil, fmt.Errorf("nameserver set %s has no nameservers", nameserverSetLabel(set))
+	}
+
+	return set.Nameservers, nil
+}
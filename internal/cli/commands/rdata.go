@@ -0,0 +1,377 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+// recordDataBuilder builds the content of typed records from command line flags
+type recordDataBuilder struct {
+	types []string
+	flags []cli.Flag
+	build func(c *cli.Context, recordType string) inwx.RecordData
+}
+
+var recordDataBuilders = []recordDataBuilder{
+	{
+		types: []string{"SRV"},
+		flags: []cli.Flag{
+			&cli.IntFlag{Name: "srv-weight", Usage: "SRV weight"},
+			&cli.IntFlag{Name: "srv-port", Usage: "SRV port"},
+			&cli.StringFlag{Name: "srv-target", Usage: "SRV target host (priority is set with --prio)"},
+		},
+		build: func(c *cli.Context, _ string) inwx.RecordData {
+			return &inwx.SRVData{
+				Priority: c.Int("prio"),
+				Weight:   c.Int("srv-weight"),
+				Port:     c.Int("srv-port"),
+				Target:   c.String("srv-target"),
+			}
+		},
+	},
+	{
+		types: []string{"CAA"},
+		flags: []cli.Flag{
+			&cli.IntFlag{Name: "caa-flags", Usage: "CAA flags (128 = critical)"},
+			&cli.StringFlag{Name: "caa-tag", Usage: "CAA tag (issue, issuewild, iodef, ...)"},
+			&cli.StringFlag{Name: "caa-value", Usage: "CAA value, e.g. letsencrypt.org"},
+		},
+		build: func(c *cli.Context, _ string) inwx.RecordData {
+			return &inwx.CAAData{
+				Flags: c.Int("caa-flags"),
+				Tag:   strings.ToLower(c.String("caa-tag")),
+				Value: c.String("caa-value"),
+			}
+		},
+	},
+	{
+		types: []string{"TLSA"},
+		flags: []cli.Flag{
+			&cli.IntFlag{Name: "tlsa-usage", Usage: "TLSA certificate usage (0-3)", Value: 3},
+			&cli.IntFlag{Name: "tlsa-selector", Usage: "TLSA selector (0 = certificate, 1 = public key)", Value: 1},
+			&cli.IntFlag{Name: "tlsa-matching-type", Usage: "TLSA matching type (0 = full, 1 = SHA-256, 2 = SHA-512)", Value: 1},
+			&cli.StringFlag{Name: "tlsa-data", Usage: "TLSA certificate association data (hex)"},
+		},
+		build: func(c *cli.Context, _ string) inwx.RecordData {
+			return &inwx.TLSAData{
+				Usage:        c.Int("tlsa-usage"),
+				Selector:     c.Int("tlsa-selector"),
+				MatchingType: c.Int("tlsa-matching-type"),
+				Data:         strings.ToLower(c.String("tlsa-data")),
+			}
+		},
+	},
+	{
+		types: []string{"SSHFP"},
+		flags: []cli.Flag{
+			&cli.IntFlag{Name: "sshfp-algorithm", Usage: "SSHFP algorithm (1 = RSA, 2 = DSA, 3 = ECDSA, 4 = Ed25519, 6 = Ed448)"},
+			&cli.IntFlag{Name: "sshfp-type", Usage: "SSHFP fingerprint type (1 = SHA-1, 2 = SHA-256)", Value: 2},
+			&cli.StringFlag{Name: "sshfp-fingerprint", Usage: "SSHFP fingerprint (hex)"},
+		},
+		build: func(c *cli.Context, _ string) inwx.RecordData {
+			return &inwx.SSHFPData{
+				Algorithm:       c.Int("sshfp-algorithm"),
+				FingerprintType: c.Int("sshfp-type"),
+				Fingerprint:     strings.ToLower(c.String("sshfp-fingerprint")),
+			}
+		},
+	},
+	{
+		types: []string{"HTTPS", "SVCB"},
+		flags: []cli.Flag{
+			&cli.IntFlag{Name: "svc-priority", Usage: "HTTPS/SVCB priority (0 = alias mode)", Value: 1},
+			&cli.StringFlag{Name: "svc-target", Usage: "HTTPS/SVCB target host (. = the owner name)", Value: "."},
+			&cli.StringSliceFlag{Name: "svc-param", Usage: "HTTPS/SVCB parameter as key=value, e.g. alpn=h2,h3 (can be repeated)"},
+		},
+		build: func(c *cli.Context, recordType string) inwx.RecordData {
+			return &inwx.SVCBData{
+				RRType:   recordType,
+				Priority: c.Int("svc-priority"),
+				Target:   c.String("svc-target"),
+				Params:   inwx.ParseSVCParams(c.StringSlice("svc-param")),
+			}
+		},
+	},
+	{
+		types: []string{"NAPTR"},
+		flags: []cli.Flag{
+			&cli.IntFlag{Name: "naptr-order", Usage: "NAPTR order"},
+			&cli.IntFlag{Name: "naptr-preference", Usage: "NAPTR preference"},
+			&cli.StringFlag{Name: "naptr-flags", Usage: "NAPTR flags, e.g. S, A or U"},
+			&cli.StringFlag{Name: "naptr-service", Usage: "NAPTR service, e.g. SIP+D2U"},
+			&cli.StringFlag{Name: "naptr-regexp", Usage: "NAPTR regular expression"},
+			&cli.StringFlag{Name: "naptr-replacement", Usage: "NAPTR replacement host", Value: "."},
+		},
+		build: func(c *cli.Context, _ string) inwx.RecordData {
+			return &inwx.NAPTRData{
+				Order:       c.Int("naptr-order"),
+				Preference:  c.Int("naptr-preference"),
+				Flags:       c.String("naptr-flags"),
+				Service:     c.String("naptr-service"),
+				Regexp:      c.String("naptr-regexp"),
+				Replacement: c.String("naptr-replacement"),
+			}
+		},
+	},
+	{
+		types: []string{"LOC"},
+		flags: []cli.Flag{
+			&cli.Float64Flag{Name: "loc-latitude", Usage: "LOC latitude in decimal degrees (negative = south)"},
+			&cli.Float64Flag{Name: "loc-longitude", Usage: "LOC longitude in decimal degrees (negative = west)"},
+			&cli.Float64Flag{Name: "loc-altitude", Usage: "LOC altitude in meters"},
+			&cli.Float64Flag{Name: "loc-size", Usage: "LOC size in meters", Value: 1},
+			&cli.Float64Flag{Name: "loc-horizontal-precision", Usage: "LOC horizontal precision in meters", Value: 10000},
+			&cli.Float64Flag{Name: "loc-vertical-precision", Usage: "LOC vertical precision in meters", Value: 10},
+		},
+		build: func(c *cli.Context, _ string) inwx.RecordData {
+			return &inwx.LOCData{
+				Latitude:            c.Float64("loc-latitude"),
+				Longitude:           c.Float64("loc-longitude"),
+				Altitude:            c.Float64("loc-altitude"),
+				Size:                c.Float64("loc-size"),
+				HorizontalPrecision: c.Float64("loc-horizontal-precision"),
+				VerticalPrecision:   c.Float64("loc-vertical-precision"),
+			}
+		},
+	},
+	{
+		types: []string{"URI"},
+		flags: []cli.Flag{
+			&cli.IntFlag{Name: "uri-priority", Usage: "URI priority"},
+			&cli.IntFlag{Name: "uri-weight", Usage: "URI weight"},
+			&cli.StringFlag{Name: "uri-target", Usage: "URI target, e.g. https://example.com/"},
+		},
+		build: func(c *cli.Context, _ string) inwx.RecordData {
+			return &inwx.URIData{
+				Priority: c.Int("uri-priority"),
+				Weight:   c.Int("uri-weight"),
+				Target:   c.String("uri-target"),
+			}
+		},
+	},
+}
+
+// recordDataFlags returns the flags of all typed record builders
+func recordDataFlags() []cli.Flag {
+	var flags []cli.Flag
+	for _, b := range recordDataBuilders {
+		flags = append(flags, b.flags...)
+	}
+	return flags
+}
+
+// hasRecordDataFlags reports whether any typed record flag is set
+func hasRecordDataFlags(c *cli.Context) bool {
+	for _, b := range recordDataBuilders {
+		for _, f := range b.flags {
+			if c.IsSet(f.Names()[0]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recordDataFromFlags builds typed record content from the flags. The record type is
+// taken from the flags if it is empty. It returns nil if no typed flag is set.
+func recordDataFromFlags(c *cli.Context, recordType string) (inwx.RecordData, error) {
+	recordType = strings.ToUpper(recordType)
+
+	var builder *recordDataBuilder
+	for i, b := range recordDataBuilders {
+		for _, f := range b.flags {
+			if !c.IsSet(f.Names()[0]) {
+				continue
+			}
+			if builder != nil && builder != &recordDataBuilders[i] {
+				return nil, fmt.Errorf("flags for %s and %s records cannot be combined", builder.types[0], b.types[0])
+			}
+			builder = &recordDataBuilders[i]
+		}
+	}
+	if builder == nil {
+		return nil, nil
+	}
+
+	if recordType == "" {
+		recordType = builder.types[0]
+	}
+	supported := false
+	for _, t := range builder.types {
+		if t == recordType {
+			supported = true
+		}
+	}
+	if !supported {
+		return nil, fmt.Errorf("flags for %s records cannot be used with type %s", strings.Join(builder.types, "/"), recordType)
+	}
+
+	data := builder.build(c, recordType)
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// isTypedRecord reports whether content of the record type is built field by field
+// in interactive mode
+func isTypedRecord(recordType string) bool {
+	for _, b := range recordDataBuilders {
+		for _, t := range b.types {
+			if t == recordType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// promptRecordData asks for the fields of a typed record
+func promptRecordData(recordType string) (inwx.RecordData, error) {
+	p := &fieldPrompter{}
+
+	var data inwx.RecordData
+	switch recordType {
+	case "SRV":
+		data = &inwx.SRVData{
+			Priority: p.int("Priority:", "Lower values are tried first", "10", 65535),
+			Weight:   p.int("Weight:", "Relative weight for targets with the same priority", "5", 65535),
+			Port:     p.int("Port:", "TCP or UDP port of the service", "", 65535),
+			Target:   p.string("Target host:", "Host providing the service, . if the service is not available", ""),
+		}
+	case "CAA":
+		flags := p.int("Flags:", "0, or 128 if CAs must understand the tag", "0", 255)
+		var tag string
+		if p.err == nil {
+			p.err = survey.AskOne(&survey.Select{
+				Message: "Tag:",
+				Options: []string{"issue", "issuewild", "iodef", "issuemail", "issuevmc", "contactemail", "contactphone"},
+				Default: "issue",
+			}, &tag)
+		}
+		data = &inwx.CAAData{
+			Flags: flags,
+			Tag:   tag,
+			Value: p.string("Value:", "CA domain (e.g. letsencrypt.org), ; to forbid issuance, or a mailto: URL for iodef", ""),
+		}
+	case "TLSA":
+		data = &inwx.TLSAData{
+			Usage:        p.int("Certificate usage:", "0 = CA constraint, 1 = service certificate, 2 = trust anchor, 3 = domain-issued certificate", "3", 3),
+			Selector:     p.int("Selector:", "0 = full certificate, 1 = public key", "1", 1),
+			MatchingType: p.int("Matching type:", "0 = exact, 1 = SHA-256, 2 = SHA-512", "1", 2),
+			Data:         strings.ToLower(p.string("Association data (hex):", "Digest of the certificate or public key, see 'inwx dns tlsa generate'", "")),
+		}
+	case "SSHFP":
+		data = &inwx.SSHFPData{
+			Algorithm:       p.int("Algorithm:", "1 = RSA, 2 = DSA, 3 = ECDSA, 4 = Ed25519, 6 = Ed448", "4", 255),
+			FingerprintType: p.int("Fingerprint type:", "1 = SHA-1, 2 = SHA-256", "2", 255),
+			Fingerprint:     strings.ToLower(p.string("Fingerprint (hex):", "Output of 'ssh-keygen -r <host>'", "")),
+		}
+	case "HTTPS", "SVCB":
+		d := &inwx.SVCBData{
+			RRType:   recordType,
+			Priority: p.int("Priority:", "0 = alias mode, 1 or higher = service mode", "1", 65535),
+			Target:   p.string("Target host:", ". for the owner name itself", "."),
+		}
+		if d.Priority > 0 {
+			params := p.optional("Parameters:", "Space separated key=value pairs, e.g. alpn=h2,h3 ipv4hint=192.0.2.1")
+			d.Params = inwx.ParseSVCParams(strings.Fields(params))
+		}
+		data = d
+	case "NAPTR":
+		data = &inwx.NAPTRData{
+			Order:       p.int("Order:", "Lower values are processed first", "100", 65535),
+			Preference:  p.int("Preference:", "Lower values are preferred among records with the same order", "10", 65535),
+			Flags:       p.optional("Flags:", "e.g. S (SRV lookup), A (address lookup), U (terminal URI)"),
+			Service:     p.optional("Service:", "e.g. SIP+D2U or E2U+sip"),
+			Regexp:      p.optional("Regexp:", "Substitution expression, empty if a replacement is used"),
+			Replacement: p.string("Replacement:", "Next domain to look up, . if a regexp is used", "."),
+		}
+	case "LOC":
+		data = inwx.NewLOCData(
+			p.float("Latitude:", "Decimal degrees, negative for south", ""),
+			p.float("Longitude:", "Decimal degrees, negative for west", ""),
+			p.float("Altitude (m):", "Altitude above sea level in meters", "0"),
+		)
+	case "URI":
+		data = &inwx.URIData{
+			Priority: p.int("Priority:", "Lower values are tried first", "10", 65535),
+			Weight:   p.int("Weight:", "Relative weight for targets with the same priority", "1", 65535),
+			Target:   p.string("Target URI:", "Absolute URI, e.g. https://example.com/", ""),
+		}
+	default:
+		return nil, fmt.Errorf("no typed prompts for %s records", recordType)
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// fieldPrompter asks for record fields and keeps the first error
+type fieldPrompter struct {
+	err error
+}
+
+func (p *fieldPrompter) ask(message, help, def string, required bool, validate func(string) error) string {
+	if p.err != nil {
+		return ""
+	}
+	var value string
+	p.err = survey.AskOne(&survey.Input{
+		Message: message,
+		Help:    help,
+		Default: def,
+	}, &value, survey.WithValidator(func(val interface{}) error {
+		str, _ := val.(string)
+		if str == "" {
+			if required {
+				return fmt.Errorf("value is required")
+			}
+			return nil
+		}
+		return validate(str)
+	}))
+	return value
+}
+
+func (p *fieldPrompter) int(message, help, def string, max int) int {
+	value := p.ask(message, help, def, true, func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n > max {
+			return fmt.Errorf("must be a number between 0 and %d", max)
+		}
+		return nil
+	})
+	n, _ := strconv.Atoi(value)
+	return n
+}
+
+func (p *fieldPrompter) float(message, help, def string) float64 {
+	value := p.ask(message, help, def, true, func(s string) error {
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		return nil
+	})
+	f, _ := strconv.ParseFloat(value, 64)
+	return f
+}
+
+func (p *fieldPrompter) string(message, help, def string) string {
+	return p.ask(message, help, def, true, func(string) error { return nil })
+}
+
+func (p *fieldPrompter) optional(message, help string) string {
+	return p.ask(message, help, "", false, func(string) error { return nil })
+}
@@ -0,0 +1,279 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+func RedirectCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "redirect",
+		Usage: "Manage URL redirects and frame forwarding",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     "Redirect a host name to a URL",
+				ArgsUsage: "<hostname> <target URL>",
+				Description: "Creates a URL record for the host name, or updates the existing one. A, AAAA\n" +
+					"   and CNAME records of the host name conflict with the redirect and must be removed\n" +
+					"   first. Title, description, favicon and keywords are used by frame forwarding.",
+				Flags: append(urlRedirectFlags(""),
+					&cli.IntFlag{
+						Name:  "ttl",
+						Usage: "TTL of the record",
+						Value: 3600,
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Show the change without applying it",
+					},
+				),
+				Action: addRedirect,
+			},
+		},
+	}
+}
+
+// urlRedirectFlags returns the flags for the redirect parameters of URL records. The
+// prefix distinguishes them from other flags of a command, e.g. "redirect-".
+func urlRedirectFlags(prefix string) []cli.Flag {
+	redirectType := &cli.StringFlag{
+		Name:  prefix + "type",
+		Usage: "Redirect type (301, 302 or frame)",
+	}
+	if prefix == "" {
+		redirectType.Value = "301"
+	}
+
+	return []cli.Flag{
+		redirectType,
+		&cli.StringFlag{Name: prefix + "title", Usage: "Page title for frame forwarding"},
+		&cli.StringFlag{Name: prefix + "description", Usage: "Meta description for frame forwarding"},
+		&cli.StringFlag{Name: prefix + "favicon", Usage: "Favicon URL for frame forwarding"},
+		&cli.StringFlag{Name: prefix + "keywords", Usage: "Meta keywords for frame forwarding"},
+		&cli.BoolFlag{Name: prefix + "append", Usage: "Append the requested path to the target URL"},
+	}
+}
+
+// applyURLRedirectFlags sets the redirect parameters of the record from the flags
+func applyURLRedirectFlags(c *cli.Context, prefix string, record *inwx.DNSRecord) error {
+	if value := c.String(prefix + "type"); value != "" {
+		redirectType, err := inwx.ParseURLRedirectType(value)
+		if err != nil {
+			return err
+		}
+		record.URLRedirectType = redirectType
+	}
+	record.URLRedirectTitle = c.String(prefix + "title")
+	record.URLRedirectDescription = c.String(prefix + "description")
+	record.URLRedirectFavIcon = c.String(prefix + "favicon")
+	record.URLRedirectKeywords = c.String(prefix + "keywords")
+	record.URLAppend = c.Bool(prefix + "append")
+	return nil
+}
+
+// urlRedirectFlagsSet reports whether any redirect flag is given
+func urlRedirectFlagsSet(c *cli.Context, prefix string) bool {
+	for _, name := range []string{"type", "title", "description", "favicon", "keywords", "append"} {
+		if c.IsSet(prefix + name) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateURLRedirect applies the redirect flags to a URL record. Given flags replace the
+// current parameters, so empty values clear them and --<prefix>append=false turns
+// appending off; switching away from frame forwarding clears its parameters. The other
+// fields of updates keep the current values if empty.
+func updateURLRedirect(ctx context.Context, c *cli.Context, prefix string, dns *inwx.DNSService, current inwx.DNSRecord, updates inwx.DNSRecord) (*inwx.DNSRecord, error) {
+	if updates.Name != "" || updates.Type != "" {
+		if _, err := dns.UpdateRecord(ctx, current.ID, inwx.DNSRecord{Name: updates.Name, Type: updates.Type}); err != nil {
+			return nil, err
+		}
+	}
+
+	record := current
+	if updates.Type != "" {
+		record.Type = updates.Type
+	}
+	if updates.Content != "" {
+		record.Content = updates.Content
+	}
+	if updates.TTL > 0 {
+		record.TTL = updates.TTL
+	}
+	if updates.Prio > 0 {
+		record.Prio = updates.Prio
+	}
+
+	if c.IsSet(prefix + "type") {
+		record.URLRedirectType = updates.URLRedirectType
+		if record.URLRedirectType != inwx.URLRedirectFrame {
+			record.URLRedirectTitle, record.URLRedirectDescription = "", ""
+			record.URLRedirectFavIcon, record.URLRedirectKeywords = "", ""
+		}
+	}
+	for _, field := range []struct {
+		flag   string
+		value  string
+		target *string
+	}{
+		{"title", updates.URLRedirectTitle, &record.URLRedirectTitle},
+		{"description", updates.URLRedirectDescription, &record.URLRedirectDescription},
+		{"favicon", updates.URLRedirectFavIcon, &record.URLRedirectFavIcon},
+		{"keywords", updates.URLRedirectKeywords, &record.URLRedirectKeywords},
+	} {
+		if c.IsSet(prefix + field.flag) {
+			*field.target = field.value
+		}
+	}
+	if c.IsSet(prefix + "append") {
+		record.URLAppend = updates.URLAppend
+	}
+
+	return dns.ReplaceRecord(ctx, current.ID, record)
+}
+
+// urlRedirectSummary describes the redirect parameters of a record, e.g.
+// "HEADER301, append"
+func urlRedirectSummary(record inwx.DNSRecord) string {
+	var parts []string
+	if record.URLRedirectType != "" {
+		parts = append(parts, record.URLRedirectType)
+	}
+	if record.URLRedirectTitle != "" {
+		parts = append(parts, fmt.Sprintf("title %q", record.URLRedirectTitle))
+	}
+	if record.URLRedirectDescription != "" {
+		parts = append(parts, fmt.Sprintf("description %q", record.URLRedirectDescription))
+	}
+	if record.URLRedirectFavIcon != "" {
+		parts = append(parts, "favicon "+record.URLRedirectFavIcon)
+	}
+	if record.URLRedirectKeywords != "" {
+		parts = append(parts, fmt.Sprintf("keywords %q", record.URLRedirectKeywords))
+	}
+	if record.URLAppend {
+		parts = append(parts, "append")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func addRedirect(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return fmt.Errorf("a host name and a target URL must be specified")
+	}
+	hostname := normalizeHostname(c.Args().Get(0))
+	if err := utils.ValidateHostname(hostname); err != nil {
+		return fmt.Errorf("invalid hostname %s: %w", hostname, err)
+	}
+
+	if err := inwx.ValidateRedirectTarget(c.Args().Get(1)); err != nil {
+		return err
+	}
+
+	record := inwx.DNSRecord{
+		Type:    "URL",
+		Content: c.Args().Get(1),
+		TTL:     c.Int("ttl"),
+	}
+	if err := applyURLRedirectFlags(c, "", &record); err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	record.Domain, record.Name, err = utils.InferDomainAndName(client, ctx, hostname)
+	if err != nil {
+		return fmt.Errorf("invalid hostname %s: %w", hostname, err)
+	}
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	existing, err := dns.ListRecords(ctx, inwx.WithDomainFilter(record.Domain))
+	if err != nil {
+		return fmt.Errorf("failed to list records of %s: %w", record.Domain, err)
+	}
+
+	var current *inwx.DNSRecord
+	for i, r := range existing {
+		if normalizeRecordName(r.Name) != normalizeRecordName(record.Name) {
+			continue
+		}
+		switch strings.ToUpper(r.Type) {
+		case "URL":
+			if current == nil {
+				current = &existing[i]
+			}
+		case "A", "AAAA", "CNAME":
+			return fmt.Errorf("%s has an existing %s record (%s) that conflicts with the redirect, delete it first", hostname, r.Type, r.Content)
+		}
+	}
+
+	summary := urlRedirectSummary(record)
+	switch {
+	case current == nil:
+		fmt.Printf("+ %s → %s (%s)\n", hostname, record.Content, summary)
+	case current.Content == record.Content && urlRedirectSummary(*current) == summary && current.TTL == record.TTL:
+		fmt.Printf("✓ %s already redirects to %s (%s)\n", hostname, record.Content, summary)
+		return nil
+	default:
+		fmt.Printf("~ %s → %s (%s)\n", hostname, current.Content, urlRedirectSummary(*current))
+		fmt.Printf("  → %s (%s)\n", record.Content, summary)
+	}
+
+	if c.Bool("dry-run") {
+		fmt.Println("Dry run - no changes were made")
+		return nil
+	}
+
+	confirmed, err := utils.AskSimpleConfirmation("Apply this change?", c.Bool("yes"))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("❌ Changes cancelled")
+		return nil
+	}
+
+	if current == nil {
+		if _, err := dns.CreateRecord(ctx, record); err != nil {
+			return fmt.Errorf("failed to create redirect: %w", err)
+		}
+		fmt.Printf("✓ Created redirect %s → %s\n", hostname, record.Content)
+		return nil
+	}
+
+	// Parameters that are not given are cleared, e.g. the title when switching from
+	// frame forwarding to a 301 redirect
+	if _, err := dns.ReplaceRecord(ctx, current.ID, record); err != nil {
+		return fmt.Errorf("failed to update redirect: %w", err)
+	}
+	fmt.Printf("✓ Updated redirect %s → %s\n", hostname, record.Content)
+	return nil
+}
@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/externaldns"
+	"github.com/nmeilick/inwx-cli/internal/rfc2136"
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+func ServeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Run gateways that manage INWX DNS records on behalf of other tools",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "external-dns",
+				Usage: "Serve the ExternalDNS webhook provider API",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "listen",
+						Usage: "Address to listen on",
+						Value: "127.0.0.1:8888",
+					},
+					&cli.StringSliceFlag{
+						Name:  "domain-filter",
+						Usage: "Only manage names in these domains (can be repeated)",
+					},
+					&cli.StringSliceFlag{
+						Name:  "exclude-domains",
+						Usage: "Never manage names in these domains (can be repeated)",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Log changes instead of applying them",
+					},
+				},
+				Action: serveExternalDNS,
+			},
+			{
+				Name:  "rfc2136",
+				Usage: "Accept TSIG signed RFC 2136 dynamic updates over UDP and TCP",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "listen",
+						Usage: "Address to listen on",
+						Value: ":5353",
+					},
+					&cli.StringSliceFlag{
+						Name:    "tsig-key",
+						Usage:   "TSIG key as [algorithm:]name:base64-secret, algorithm defaults to hmac-sha256 (can be repeated)",
+						EnvVars: []string{"INWX_TSIG_KEY"},
+					},
+					&cli.StringSliceFlag{
+						Name:  "zone",
+						Usage: "Only accept updates for these zones (can be repeated, default: all account domains)",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Log changes instead of applying them",
+					},
+				},
+				Action: serveRFC2136,
+			},
+		},
+	}
+}
+
+func serveExternalDNS(c *cli.Context) error {
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	server := externaldns.NewServer(dns, accountZones(client),
+		externaldns.WithDomainFilter(externaldns.DomainFilter{
+			Include: parseCommaSeparatedValues(c.StringSlice("domain-filter")),
+			Exclude: parseCommaSeparatedValues(c.StringSlice("exclude-domains")),
+		}),
+		externaldns.WithDryRun(c.Bool("dry-run")),
+	)
+
+	return listenAndServe(c.String("listen"), server.Handler(), "ExternalDNS webhook")
+}
+
+func serveRFC2136(c *cli.Context) error {
+	specs := parseCommaSeparatedValues(c.StringSlice("tsig-key"))
+	if len(specs) == 0 {
+		return fmt.Errorf("at least one --tsig-key is required")
+	}
+
+	var keys []rfc2136.Key
+	for _, spec := range specs {
+		key, err := rfc2136.ParseKey(spec)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	server := rfc2136.NewServer(dns, accountZones(client),
+		rfc2136.WithKeys(keys...),
+		rfc2136.WithZones(parseCommaSeparatedValues(c.StringSlice("zone"))...),
+		rfc2136.WithDryRun(c.Bool("dry-run")),
+	)
+
+	shutdown := utils.NewGracefulShutdown()
+	shutdown.Start()
+
+	addr := c.String("listen")
+	log.Info().Str("listen", addr).Int("keys", len(keys)).Msg("RFC 2136 gateway listening")
+	if err := server.ListenAndServe(shutdown.Context(), addr); err != nil {
+		return fmt.Errorf("RFC 2136 gateway failed: %w", err)
+	}
+	return nil
+}
+
+// accountZones lists the domains of the account as zones
+func accountZones(client *inwx.Client) func(ctx context.Context) ([]string, error) {
+	return func(ctx context.Context) ([]string, error) {
+		domains, err := client.Domain().List(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		zones := make([]string, 0, len(domains))
+		for _, domain := range domains {
+			zones = append(zones, domain.Name)
+		}
+		return zones, nil
+	}
+}
+
+// listenAndServe runs an HTTP server until it fails or the process is interrupted
+func listenAndServe(addr string, handler http.Handler, name string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	shutdown := utils.NewGracefulShutdown()
+	shutdown.AddShutdownFunc(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(ctx)
+	})
+	shutdown.Start()
+
+	log.Info().Str("listen", addr).Msgf("%s listening", name)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+
+	// Wait for the shutdown functions to complete
+	shutdown.Wait()
+	return nil
+}
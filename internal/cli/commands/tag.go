@@ -0,0 +1,483 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/cli/output"
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+func TagCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "tag",
+		Usage: "Tag management for organising domains",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List tags",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    "domain",
+						Aliases: []string{"d"},
+						Usage:   "Only list tags attached to these domain(s)",
+					},
+				},
+				Action: listTags,
+			},
+			{
+				Name:      "domains",
+				Usage:     "List the domains carrying a tag",
+				ArgsUsage: "<tag>",
+				Action:    listTaggedDomains,
+			},
+			{
+				Name:      "create",
+				Usage:     "Create tags",
+				ArgsUsage: "<name>...",
+				Action:    createTags,
+			},
+			{
+				Name:      "rename",
+				Usage:     "Rename a tag",
+				ArgsUsage: "<tag> <new-name>",
+				Action:    renameTag,
+			},
+			{
+				Name:      "delete",
+				Usage:     "Remove tags from all domains and delete them",
+				ArgsUsage: "<tag>...",
+				Action:    deleteTags,
+			},
+			{
+				Name:      "add",
+				Usage:     "Attach a tag to domain(s), creating the tag if needed",
+				ArgsUsage: "<tag> [domain...]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    "pattern",
+						Aliases: []string{"P"},
+						Usage:   "Select owned domains by shell-style wildcard pattern (e.g., \"*.de\")",
+					},
+				},
+				Action: addTagToDomains,
+			},
+			{
+				Name:      "remove",
+				Usage:     "Detach a tag from domain(s)",
+				ArgsUsage: "<tag> [domain...]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    "pattern",
+						Aliases: []string{"P"},
+						Usage:   "Select owned domains by shell-style wildcard pattern (e.g., \"*.de\")",
+					},
+				},
+				Action: removeTagFromDomains,
+			},
+		},
+	}
+}
+
+// tagFlag returns the --tag flag used by commands that select domains by tag
+func tagFlag() cli.Flag {
+	return &cli.StringSliceFlag{
+		Name:  "tag",
+		Usage: "Select domains by tag name or ID (can be repeated); combined with explicit domains, only the tagged ones are used",
+	}
+}
+
+// resolveTagIDs converts tag names or IDs to tag IDs
+func resolveTagIDs(ctx context.Context, client *inwx.Client, refs []string) ([]int, error) {
+	tags := client.Tag()
+
+	var ids []int
+	for _, ref := range refs {
+		tag, err := tags.Resolve(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, tag.ID)
+	}
+
+	return ids, nil
+}
+
+// taggedDomains returns the names of all domains carrying any of the tags given with
+// --tag. It returns nil if the flag is not set and an error if no domain matches, so
+// that callers never fall back to all domains.
+func taggedDomains(ctx context.Context, client *inwx.Client, c *cli.Context) ([]string, error) {
+	refs := parseCommaSeparatedValues(c.StringSlice("tag"))
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	ids, err := resolveTagIDs(ctx, client, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	domains, err := client.Domain().List(ctx, inwx.WithTagIDs(ids...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tagged domains: %w", err)
+	}
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("no domains tagged %s", strings.Join(refs, ", "))
+	}
+
+	names := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		names = append(names, domain.Name)
+	}
+	sort.Strings(names)
+
+	log.Debug().Strs("tags", refs).Int("domains", len(names)).Msg("Resolved tagged domains")
+
+	return names, nil
+}
+
+// filterTaggedDomains applies --tag to the domains given explicitly. Like the filters
+// of domain list, both have to match, so only the given domains carrying a tag are
+// kept. Without explicit domains, the tagged domains are used. tagged is nil if --tag
+// is not set.
+func filterTaggedDomains(domains, tagged []string) ([]string, error) {
+	if tagged == nil {
+		return domains, nil
+	}
+	if len(domains) == 0 {
+		return tagged, nil
+	}
+
+	isTagged := make(map[string]bool, len(tagged))
+	for _, domain := range tagged {
+		isTagged[strings.ToLower(domain)] = true
+	}
+
+	var filtered []string
+	for _, domain := range domains {
+		if isTagged[strings.ToLower(domain)] {
+			filtered = append(filtered, domain)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("none of the given domains carries the selected tags")
+	}
+	return filtered, nil
+}
+
+func listTags(c *cli.Context) error {
+	domains := parseCommaSeparatedValues(c.StringSlice("domain"))
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	var tags []inwx.Tag
+	if len(domains) > 0 {
+		tags, err = client.Tag().ListByDomain(ctx, domains...)
+	} else {
+		tags, err = client.Tag().List(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatTags(tags)
+		case *output.JSONFormatter:
+			return f.FormatTags(tags)
+		case *output.YAMLFormatter:
+			return f.FormatTags(tags)
+		case *output.CSVFormatter:
+			return f.FormatTags(tags)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func listTaggedDomains(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one tag must be specified")
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	tag, err := client.Tag().Resolve(ctx, c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	domains, err := client.Domain().List(ctx, inwx.WithTagIDs(tag.ID))
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatDomains(domains)
+		case *output.JSONFormatter:
+			return f.FormatDomains(domains)
+		case *output.YAMLFormatter:
+			return f.FormatDomains(domains)
+		case *output.CSVFormatter:
+			return f.FormatDomains(domains)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func createTags(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("at least one tag name must be specified")
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	failed := 0
+	for _, name := range c.Args().Slice() {
+		tag, err := client.Tag().Create(ctx, name)
+		if err != nil {
+			log.Error().Err(err).Str("tag", name).Msg("Failed to create tag")
+			failed++
+			continue
+		}
+		if tag.Name != name {
+			fmt.Printf("✓ Tag %s created as %s (ID: %d)\n", name, tag.Name, tag.ID)
+		} else {
+			fmt.Printf("✓ Tag %s created (ID: %d)\n", tag.Name, tag.ID)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to create %d tag(s)", failed)
+	}
+
+	return nil
+}
+
+func renameTag(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return fmt.Errorf("a tag and its new name must be specified")
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	tag, err := client.Tag().Resolve(ctx, c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	newName := c.Args().Get(1)
+	if err := client.Tag().Rename(ctx, tag.ID, newName); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Tag %s renamed to %s\n", tag.Name, newName)
+	return nil
+}
+
+func deleteTags(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("at least one tag must be specified")
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	tags := client.Tag()
+
+	skipPrompt := c.Bool("yes")
+	failed := 0
+	for _, ref := range c.Args().Slice() {
+		tag, err := tags.Resolve(ctx, ref)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to resolve tag")
+			failed++
+			continue
+		}
+
+		if !skipPrompt {
+			result, err := utils.AskConfirmation(fmt.Sprintf("Delete tag %s and remove it from all domains?", tag.Name), false)
+			if err != nil {
+				return err
+			}
+			if result == utils.ConfirmationCancel {
+				fmt.Println("Operation cancelled")
+				break
+			}
+			if result == utils.ConfirmationNo {
+				continue
+			}
+			if result == utils.ConfirmationAll {
+				skipPrompt = true
+			}
+		}
+
+		if err := tags.Delete(ctx, tag.ID); err != nil {
+			log.Error().Err(err).Str("tag", tag.Name).Msg("Failed to delete tag")
+			failed++
+			continue
+		}
+		fmt.Printf("✓ Tag %s deleted\n", tag.Name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d tag(s)", failed)
+	}
+
+	return nil
+}
+
+func addTagToDomains(c *cli.Context) error {
+	return changeDomainTags(c, true)
+}
+
+func removeTagFromDomains(c *cli.Context) error {
+	return changeDomainTags(c, false)
+}
+
+// changeDomainTags attaches or detaches a tag to/from the selected domains
+func changeDomainTags(c *cli.Context, add bool) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("a tag must be specified")
+	}
+	ref := c.Args().First()
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	targets, err := resolveDomainTargets(ctx, client, c.Args().Tail(), c.StringSlice("pattern"))
+	if err != nil {
+		return err
+	}
+
+	owned, err := client.Domain().List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list domains: %w", err)
+	}
+	roIDs := make(map[string]int, len(owned))
+	for _, domain := range owned {
+		roIDs[strings.ToLower(domain.Name)] = domain.RoID
+	}
+
+	var domainIDs []int
+	for _, domain := range targets {
+		id, ok := roIDs[strings.ToLower(domain)]
+		if !ok || id == 0 {
+			return fmt.Errorf("domain %s is not in this account", domain)
+		}
+		domainIDs = append(domainIDs, id)
+	}
+
+	tags := client.Tag()
+
+	var tag *inwx.Tag
+	if add {
+		// tag.create returns the existing tag if the name is already taken
+		if tag, err = tags.Resolve(ctx, ref); err != nil {
+			if tag, err = tags.Create(ctx, ref); err != nil {
+				return err
+			}
+		}
+		if err := tags.AddDomains(ctx, tag.ID, domainIDs); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Tagged %d domain(s) with %s\n", len(domainIDs), tag.Name)
+		return nil
+	}
+
+	if tag, err = tags.Resolve(ctx, ref); err != nil {
+		return err
+	}
+	if err := tags.RemoveDomains(ctx, tag.ID, domainIDs); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Removed tag %s from %d domain(s)\n", tag.Name, len(domainIDs))
+
+	return nil
+}
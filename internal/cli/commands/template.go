@@ -0,0 +1,535 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/nmeilick/inwx-cli/internal/cli/output"
+	"github.com/nmeilick/inwx-cli/internal/templates"
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+func dnsTemplateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "template",
+		Usage: "Apply record templates for common services",
+		Description: "Built-in templates can be overridden and extended with YAML files in " + templates.Dir() + ".\n" +
+			"   Use 'inwx -o yaml dns template show <name>' as a starting point for your own templates.",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List available templates, or the templates applied to a domain",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "domain",
+						Aliases: []string{"d"},
+						Usage:   "Only list templates applied to this domain",
+					},
+				},
+				Action: listTemplates,
+			},
+			{
+				Name:      "show",
+				Usage:     "Show the variables and records of a template",
+				ArgsUsage: "<template>",
+				Action:    showTemplate,
+			},
+			{
+				Name:      "apply",
+				Usage:     "Preview and apply a template to a domain",
+				ArgsUsage: "<template>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "domain",
+						Aliases:  []string{"d"},
+						Usage:    "Domain to apply the template to",
+						Required: true,
+					},
+					&cli.StringSliceFlag{
+						Name:  "var",
+						Usage: "Template variable as name=value (can be repeated)",
+					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Apply even if the changes introduce validation errors",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Only show the preview",
+					},
+				},
+				Action: applyTemplate,
+			},
+			{
+				Name:      "remove",
+				Usage:     "Remove the records a template added to a domain",
+				ArgsUsage: "<template>",
+				Description: "Records the template created are deleted, and records it updated, e.g. an\n" +
+					"   existing SPF record, get their previous content and TTL back.",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "domain",
+						Aliases:  []string{"d"},
+						Usage:    "Domain to remove the template from",
+						Required: true,
+					},
+					&cli.StringSliceFlag{
+						Name:  "var",
+						Usage: "Template variable as name=value, used if the template was not applied with this CLI (can be repeated)",
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"R"},
+						Usage:   "Only show the records that would be removed",
+					},
+				},
+				Action: removeTemplate,
+			},
+		},
+	}
+}
+
+func listTemplates(c *cli.Context) error {
+	list, err := templates.List()
+	if err != nil {
+		return err
+	}
+
+	if domain := normalizeHostname(c.String("domain")); domain != "" {
+		state, err := templates.LoadState()
+		if err != nil {
+			return err
+		}
+		applied := make(map[string]bool)
+		for _, a := range state.ForDomain(domain) {
+			applied[a.Template] = true
+		}
+
+		var filtered []*templates.Template
+		for _, t := range list {
+			if applied[t.Name] {
+				filtered = append(filtered, t)
+			}
+		}
+		list = filtered
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatTemplates(list)
+		case *output.JSONFormatter:
+			return f.FormatTemplates(list)
+		case *output.YAMLFormatter:
+			return f.FormatTemplates(list)
+		case *output.CSVFormatter:
+			return f.FormatTemplates(list)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func showTemplate(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one template name must be specified")
+	}
+
+	t, err := templates.Load(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	return formatOutput(c, func(formatter interface{}) string {
+		switch f := formatter.(type) {
+		case *output.TableFormatter:
+			return f.FormatTemplate(t)
+		case *output.JSONFormatter:
+			return f.FormatTemplate(t)
+		case *output.YAMLFormatter:
+			return f.FormatTemplate(t)
+		case *output.CSVFormatter:
+			return f.FormatTemplate(t)
+		default:
+			return "Unsupported format"
+		}
+	})
+}
+
+func applyTemplate(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one template name must be specified")
+	}
+
+	domain := normalizeHostname(c.String("domain"))
+	if err := utils.ValidateDomain(domain); err != nil {
+		return fmt.Errorf("invalid domain %s: %w", domain, err)
+	}
+
+	t, err := templates.Load(c.Args().First())
+	if err != nil {
+		return err
+	}
+	vars, err := templates.ParseVars(c.StringSlice("var"))
+	if err != nil {
+		return err
+	}
+	records, err := t.Render(domain, vars)
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	existing, err := dns.ListRecords(ctx, inwx.WithDomainFilter(domain))
+	if err != nil {
+		return fmt.Errorf("failed to list records of %s: %w", domain, err)
+	}
+
+	changes := templates.Plan(domain, records, existing)
+
+	fmt.Printf("Template %s for %s:\n", t.Name, domain)
+	pending := 0
+	for _, change := range changes {
+		printTemplateChange(change)
+		if change.Action != templates.ActionUnchanged {
+			pending++
+		}
+	}
+
+	// Report the issues the changes would introduce, e.g. CNAME conflicts
+	before := dns.ValidateRecords(ctx, domain, existing)
+	after := dns.ValidateRecords(ctx, domain, templates.Result(existing, changes))
+	errorCount := 0
+	for _, issue := range templates.NewIssues(before, after) {
+		switch issue.Severity {
+		case "error":
+			errorCount++
+			fmt.Printf("✗ %s\n", issue.Message)
+		case "warning":
+			fmt.Printf("⚠️  %s\n", issue.Message)
+		default:
+			continue
+		}
+		if issue.Suggestion != "" {
+			fmt.Printf("  %s\n", issue.Suggestion)
+		}
+	}
+
+	if pending == 0 {
+		fmt.Println("✓ All records of the template are already present")
+		return nil
+	}
+	if c.Bool("dry-run") {
+		fmt.Println("Dry run - no changes were made")
+		return nil
+	}
+	if errorCount > 0 && !c.Bool("force") {
+		return fmt.Errorf("the template would introduce %d validation error(s), use --force to apply anyway", errorCount)
+	}
+
+	confirmed, err := utils.AskSimpleConfirmation("Apply these changes?", c.Bool("yes"))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("❌ Changes cancelled")
+		return nil
+	}
+
+	state, err := templates.LoadState()
+	if err != nil {
+		return err
+	}
+	applied := templates.Applied{
+		Template:  t.Name,
+		Domain:    domain,
+		Vars:      vars,
+		AppliedAt: time.Now(),
+	}
+
+	// Record what has been applied even if a later change fails
+	defer func() {
+		if len(applied.Records) == 0 && len(applied.Deleted) == 0 {
+			return
+		}
+		state.Set(applied)
+		if err := state.Save(); err != nil {
+			log.Error().Err(err).Msg("Failed to save template state")
+		}
+	}()
+
+	// Records are created and updated before others are deleted, so that e.g. the
+	// domain keeps an MX record while exclusive MX records are replaced
+	for _, action := range []templates.Action{templates.ActionCreate, templates.ActionUpdate, templates.ActionDelete} {
+		for _, change := range changes {
+			if change.Action != action {
+				continue
+			}
+			record := change.Record
+
+			switch action {
+			case templates.ActionCreate:
+				created, err := dns.CreateRecord(ctx, record)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", templateRecordLabel(record), err)
+				}
+				applied.Records = append(applied.Records, *created)
+				fmt.Printf("✓ Created %s\n", templateRecordLabel(record))
+			case templates.ActionUpdate:
+				if _, err := dns.ReplaceRecord(ctx, record.ID, record); err != nil {
+					return fmt.Errorf("failed to update %s: %w", templateRecordLabel(record), err)
+				}
+				applied.Records = append(applied.Records, record)
+				applied.Previous = append(applied.Previous, *change.Current)
+				fmt.Printf("✓ Updated %s\n", templateRecordLabel(record))
+			case templates.ActionDelete:
+				if err := dns.DeleteRecord(ctx, record.ID); err != nil {
+					return fmt.Errorf("failed to delete %s: %w", templateRecordLabel(record), err)
+				}
+				applied.Deleted = append(applied.Deleted, record)
+				fmt.Printf("✓ Deleted %s\n", templateRecordLabel(record))
+			}
+		}
+	}
+
+	fmt.Printf("✓ Applied template %s to %s\n", t.Name, domain)
+	return nil
+}
+
+func removeTemplate(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one template name must be specified")
+	}
+	name := c.Args().First()
+
+	domain := normalizeHostname(c.String("domain"))
+	if err := utils.ValidateDomain(domain); err != nil {
+		return fmt.Errorf("invalid domain %s: %w", domain, err)
+	}
+
+	state, err := templates.LoadState()
+	if err != nil {
+		return err
+	}
+	applied := state.Get(domain, name)
+
+	// Without a record of the application, the records matching the rendered template
+	// are removed
+	var rendered []templates.Record
+	if applied == nil {
+		t, err := templates.Load(name)
+		if err != nil {
+			return err
+		}
+		vars, err := templates.ParseVars(c.StringSlice("var"))
+		if err != nil {
+			return err
+		}
+		if rendered, err = t.Render(domain, vars); err != nil {
+			return err
+		}
+	}
+
+	client, err := createClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Logout(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to logout")
+		}
+	}()
+
+	dns, err := createDNSService(c, client)
+	if err != nil {
+		return err
+	}
+
+	existing, err := dns.ListRecords(ctx, inwx.WithDomainFilter(domain))
+	if err != nil {
+		return fmt.Errorf("failed to list records of %s: %w", domain, err)
+	}
+
+	// Records the template updated are restored, the ones it created are deleted
+	var targets []templates.Change
+	if applied != nil {
+		byID := make(map[int]inwx.DNSRecord)
+		for _, record := range existing {
+			byID[record.ID] = record
+		}
+		for _, record := range applied.Records {
+			current, ok := byID[record.ID]
+			if !ok {
+				continue
+			}
+			if !strings.EqualFold(strings.TrimSpace(current.Content), strings.TrimSpace(record.Content)) {
+				fmt.Printf("⚠️  Keeping %s, it was changed after the template was applied\n", templateRecordLabel(current))
+				continue
+			}
+			if previous := applied.PreviousRecord(current.ID); previous != nil {
+				targets = append(targets, templates.Change{Action: templates.ActionUpdate, Record: *previous, Current: &current})
+				continue
+			}
+			targets = append(targets, templates.Change{Action: templates.ActionDelete, Record: current})
+		}
+
+		// Records replaced by an exclusive record set are recreated, unless they exist again
+		for _, record := range applied.Deleted {
+			exists := false
+			for _, current := range existing {
+				if sameTemplateRecord(current, record) {
+					exists = true
+					break
+				}
+			}
+			if !exists {
+				targets = append(targets, templates.Change{Action: templates.ActionCreate, Record: record})
+			}
+		}
+	} else {
+		fmt.Printf("No record of applying %s to %s, removing the records matching the template\n", name, domain)
+		for _, record := range existing {
+			for _, r := range rendered {
+				if templates.Matches(record, r, domain) {
+					targets = append(targets, templates.Change{Action: templates.ActionDelete, Record: record})
+					break
+				}
+			}
+		}
+	}
+
+	if len(targets) == 0 {
+		fmt.Printf("No records of template %s found in %s\n", name, domain)
+		if applied != nil && !c.Bool("dry-run") {
+			state.Remove(domain, name)
+			return state.Save()
+		}
+		return nil
+	}
+
+	fmt.Printf("Template %s in %s:\n", name, domain)
+	for _, change := range targets {
+		printTemplateChange(change)
+	}
+
+	if c.Bool("dry-run") {
+		fmt.Println("Dry run - no changes were made")
+		return nil
+	}
+
+	confirmed, err := utils.AskSimpleConfirmation("Remove the template?", c.Bool("yes"))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("❌ Removal cancelled")
+		return nil
+	}
+
+	// Deleted records are recreated first, so that e.g. the domain keeps an MX record
+	// while the template's exclusive MX records are removed
+	for _, action := range []templates.Action{templates.ActionCreate, templates.ActionUpdate, templates.ActionDelete} {
+		for _, change := range targets {
+			if change.Action != action {
+				continue
+			}
+			record := change.Record
+
+			switch action {
+			case templates.ActionCreate:
+				if _, err := dns.CreateRecord(ctx, record); err != nil {
+					return fmt.Errorf("failed to recreate %s: %w", templateRecordLabel(record), err)
+				}
+				fmt.Printf("✓ Recreated %s\n", templateRecordLabel(record))
+			case templates.ActionUpdate:
+				if _, err := dns.ReplaceRecord(ctx, record.ID, record); err != nil {
+					return fmt.Errorf("failed to restore %s: %w", templateRecordLabel(record), err)
+				}
+				fmt.Printf("✓ Restored %s\n", templateRecordLabel(record))
+			case templates.ActionDelete:
+				if err := dns.DeleteRecord(ctx, record.ID); err != nil {
+					return fmt.Errorf("failed to delete %s: %w", templateRecordLabel(record), err)
+				}
+				fmt.Printf("✓ Deleted %s\n", templateRecordLabel(record))
+			}
+		}
+	}
+
+	state.Remove(domain, name)
+	if err := state.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Removed template %s from %s\n", name, domain)
+	return nil
+}
+
+// printTemplateChange prints a line of the template preview
+func printTemplateChange(change templates.Change) {
+	switch change.Action {
+	case templates.ActionCreate:
+		fmt.Printf("  + %s\n", templateRecordLabel(change.Record))
+	case templates.ActionDelete:
+		fmt.Printf("  - %s\n", templateRecordLabel(change.Record))
+	case templates.ActionUnchanged:
+		fmt.Printf("  = %s\n", templateRecordLabel(change.Record))
+	case templates.ActionUpdate:
+		current := *change.Current
+		if strings.TrimSpace(current.Content) == strings.TrimSpace(change.Record.Content) {
+			fmt.Printf("  ~ %s → TTL %d\n", templateRecordLabel(current), change.Record.TTL)
+		} else {
+			fmt.Printf("  ~ %s\n    → %s\n", templateRecordLabel(current), templateRecordLabel(change.Record))
+		}
+	}
+}
+
+// sameTemplateRecord reports whether two records have the same type, name, priority
+// and content
+func sameTemplateRecord(a, b inwx.DNSRecord) bool {
+	return strings.EqualFold(a.Type, b.Type) &&
+		strings.EqualFold(a.Name, b.Name) &&
+		a.Prio == b.Prio &&
+		strings.EqualFold(strings.TrimSpace(a.Content), strings.TrimSpace(b.Content))
+}
+
+func templateRecordLabel(record inwx.DNSRecord) string {
+	name := record.Name
+	if name == "" {
+		name = "@"
+	}
+	if record.Type == "MX" || record.Type == "SRV" {
+		return fmt.Sprintf("%s %s %d %s (TTL: %d)", record.Type, name, record.Prio, record.Content, record.TTL)
+	}
+	return fmt.Sprintf("%s %s %s (TTL: %d)", record.Type, name, record.Content, record.TTL)
+}
@@ -3,8 +3,12 @@ package output
 import (
 	"bytes"
 	"encoding/csv"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/nmeilick/inwx-cli/internal/templates"
 	"github.com/nmeilick/inwx-cli/pkg/inwx"
 )
 
@@ -46,21 +50,24 @@ func (f *CSVFormatter) FormatDNSRecords(records []inwx.DNSRecord) string {
 }
 
 func (f *CSVFormatter) FormatDomains(domains []inwx.Domain) string {
-	var buffer bytes.Buffer
-	writer := csv.NewWriter(&buffer)
-
-	// Write header
-	header := []string{"Domain", "Status"}
-	_ = writer.Write(header)
-
-	// Write domains
+	var rows [][]string
 	for _, domain := range domains {
-		row := []string{domain.Name, domain.Status}
-		_ = writer.Write(row)
+		rows = append(rows, []string{
+			domain.Name,
+			domain.Status,
+			csvDate(domain.CreatedAt),
+			csvDate(domain.ExpiresAt),
+			domain.RenewalMode,
+			strconv.FormatBool(domain.TransferLock),
+			strconv.Itoa(domain.Registrant),
+			strconv.Itoa(domain.Admin),
+			strconv.Itoa(domain.Tech),
+			strconv.Itoa(domain.Billing),
+			strings.Join(domain.Nameservers, " "),
+		})
 	}
 
-	writer.Flush()
-	return buffer.String()
+	return writeCSV([]string{"Domain", "Status", "Created", "Expires", "RenewalMode", "TransferLock", "Registrant", "Admin", "Tech", "Billing", "Nameservers"}, rows)
 }
 
 func (f *CSVFormatter) FormatAccountInfo(info *inwx.AccountInfo) string {
@@ -119,3 +126,408 @@ func (f *CSVFormatter) FormatBackupEntries(entries []*inwx.BackupEntry) string {
 	writer.Flush()
 	return buffer.String()
 }
+
+// writeCSV renders a header and rows as CSV
+func writeCSV(header []string, rows [][]string) string {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	_ = writer.Write(header)
+	for _, row := range rows {
+		_ = writer.Write(row)
+	}
+
+	writer.Flush()
+	return buffer.String()
+}
+
+// csvDate formats a timestamp for CSV output, leaving unset timestamps empty
+func csvDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func (f *CSVFormatter) FormatContacts(contacts []inwx.Contact) string {
+	var rows [][]string
+	for _, contact := range contacts {
+		rows = append(rows, []string{
+			strconv.Itoa(contact.ID),
+			contact.Type,
+			contact.Name,
+			contact.Org,
+			contact.Street,
+			contact.PostalCode,
+			contact.City,
+			contact.State,
+			contact.CountryCode,
+			contact.Phone,
+			contact.Fax,
+			contact.Email,
+			strconv.Itoa(contact.UsedCount),
+			contact.VerificationStatus,
+		})
+	}
+
+	return writeCSV([]string{"ID", "Type", "Name", "Org", "Street", "PostalCode", "City", "State", "Country", "Phone", "Fax", "Email", "UsedCount", "Verification"}, rows)
+}
+
+func (f *CSVFormatter) FormatContact(contact *inwx.Contact) string {
+	return f.FormatContacts([]inwx.Contact{*contact})
+}
+
+func (f *CSVFormatter) FormatContactLog(entries []inwx.ContactLogEntry) string {
+	var rows [][]string
+	for _, entry := range entries {
+		rows = append(rows, []string{
+			strconv.Itoa(entry.LogID),
+			csvDate(entry.Date),
+			strconv.Itoa(entry.ContactID),
+			entry.Status,
+		})
+	}
+
+	return writeCSV([]string{"LogID", "Date", "ContactID", "Status"}, rows)
+}
+
+func (f *CSVFormatter) FormatDomainWhois(whois *inwx.DomainWhois) string {
+	return writeCSV([]string{"Domain", "Whois"}, [][]string{{whois.Domain, whois.Whois}})
+}
+
+func (f *CSVFormatter) FormatDomainLog(domainLog *inwx.DomainLog) string {
+	var rows [][]string
+	for _, entry := range domainLog.Entries {
+		rows = append(rows, []string{
+			strconv.Itoa(entry.LogID),
+			csvDate(entry.Date),
+			entry.Domain,
+			entry.Status,
+			strconv.FormatFloat(entry.Price, 'f', 2, 64),
+			entry.Invoice,
+			entry.Account,
+			entry.RemoteAddr,
+			entry.UserText,
+		})
+	}
+
+	return writeCSV([]string{"LogID", "Date", "Domain", "Status", "Price", "Invoice", "Account", "RemoteAddr", "Description"}, rows)
+}
+
+func (f *CSVFormatter) FormatDomainStats(stats *inwx.DomainStats) string {
+	var rows [][]string
+	for _, group := range []struct {
+		name   string
+		counts map[string]int
+	}{
+		{"tld", stats.ByTLD},
+		{"status", stats.ByStatus},
+		{"expiryMonth", stats.ByExpiryMonth},
+	} {
+		keys := make([]string, 0, len(group.counts))
+		for key := range group.counts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			rows = append(rows, []string{group.name, key, strconv.Itoa(group.counts[key])})
+		}
+	}
+
+	return writeCSV([]string{"Group", "Key", "Count"}, rows)
+}
+
+// csvAmount formats a monetary amount with two decimals
+func csvAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func (f *CSVFormatter) FormatAccountBalance(balance *inwx.AccountBalance) string {
+	return writeCSV([]string{"Total", "Available", "Locked", "CreditLimit"}, [][]string{{
+		csvAmount(balance.Total),
+		csvAmount(balance.Available),
+		csvAmount(balance.Locked),
+		csvAmount(balance.CreditLimit),
+	}})
+}
+
+func (f *CSVFormatter) FormatInvoices(invoices []inwx.Invoice) string {
+	var rows [][]string
+	for _, invoice := range invoices {
+		rows = append(rows, []string{
+			invoice.ID,
+			csvDate(invoice.Date),
+			invoice.Type,
+			csvAmount(invoice.PreTax),
+			csvAmount(invoice.AfterTax),
+		})
+	}
+
+	return writeCSV([]string{"InvoiceID", "Date", "Type", "PreTax", "AfterTax"}, rows)
+}
+
+func (f *CSVFormatter) FormatAccountingLog(accountingLog *inwx.AccountingLog) string {
+	var rows [][]string
+	for _, entry := range accountingLog.Entries {
+		rows = append(rows, []string{
+			csvDate(entry.Date),
+			entry.Type,
+			csvAmount(entry.Amount),
+			entry.Details,
+			strconv.FormatBool(entry.Refundable),
+		})
+	}
+
+	return writeCSV([]string{"Date", "Type", "Amount", "Details", "Refundable"}, rows)
+}
+
+func (f *CSVFormatter) FormatLockedFunds(funds *inwx.LockedFunds) string {
+	var rows [][]string
+	for _, fund := range funds.Domains {
+		rows = append(rows, []string{csvDate(fund.Date), "domain", fund.Domain, fund.Status, csvAmount(fund.Amount)})
+	}
+	for _, fund := range funds.Certificates {
+		rows = append(rows, []string{csvDate(fund.CreatedAt), "certificate", fund.CommonName, fund.Status, csvAmount(fund.Net + fund.VAT)})
+	}
+
+	return writeCSV([]string{"Date", "Kind", "Object", "Status", "Amount"}, rows)
+}
+
+func (f *CSVFormatter) FormatCreditLog(creditLog *inwx.CreditLog) string {
+	return writeCSV([]string{"ID", "Time", "Amount", "Currency", "CreditType", "TransactionID", "TransactionDetails", "Last4", "RefundID"}, [][]string{{
+		strconv.Itoa(creditLog.ID),
+		csvDate(creditLog.CreditTime),
+		csvAmount(creditLog.Amount),
+		creditLog.Currency,
+		creditLog.CreditType,
+		creditLog.TransactionID,
+		creditLog.TransactionDetails,
+		creditLog.Last4,
+		strconv.Itoa(creditLog.RefundID),
+	}})
+}
+
+func (f *CSVFormatter) FormatFundsForecast(forecast *inwx.FundsForecast) string {
+	var rows [][]string
+	for _, renewal := range forecast.Renewals {
+		price := csvAmount(renewal.Price)
+		if renewal.PriceUnknown {
+			price = ""
+		}
+		rows = append(rows, []string{renewal.Domain, csvDate(renewal.RenewalDate), price, renewal.Currency})
+	}
+
+	return writeCSV([]string{"Domain", "RenewalDate", "Price", "Currency"}, rows)
+}
+
+func (f *CSVFormatter) FormatSubAccounts(accounts []inwx.SubAccount) string {
+	var rows [][]string
+	for _, account := range accounts {
+		rows = append(rows, []string{
+			strconv.Itoa(account.ID),
+			account.Username,
+			account.Firstname,
+			account.Lastname,
+			account.Org,
+			account.Email,
+			strconv.FormatBool(account.TFAEnabled),
+		})
+	}
+
+	return writeCSV([]string{"ID", "Username", "Firstname", "Lastname", "Org", "Email", "TFAEnabled"}, rows)
+}
+
+func (f *CSVFormatter) FormatRoles(roles []inwx.Role) string {
+	var rows [][]string
+	for _, role := range roles {
+		rows = append(rows, []string{strconv.Itoa(role.ID), role.Name})
+	}
+
+	return writeCSV([]string{"ID", "Role"}, rows)
+}
+
+func messageRow(msg inwx.Message) []string {
+	return []string{strconv.Itoa(msg.ID), csvDate(msg.Date), msg.Type, msg.Object, msg.Status, msg.StatusDetails}
+}
+
+func (f *CSVFormatter) FormatMessageQueue(queue *inwx.MessageQueue) string {
+	var rows [][]string
+	for _, msg := range queue.Messages {
+		rows = append(rows, messageRow(msg))
+	}
+
+	return writeCSV([]string{"ID", "Date", "Type", "Object", "Status", "StatusDetails"}, rows)
+}
+
+// FormatMessage renders a single message as a CSV row without header, so that
+// follow mode produces one continuous CSV stream
+func (f *CSVFormatter) FormatMessage(msg *inwx.Message) string {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	_ = writer.Write(messageRow(*msg))
+	writer.Flush()
+	return buffer.String()
+}
+
+func (f *CSVFormatter) FormatHosts(hosts []inwx.Host) string {
+	var rows [][]string
+	for _, host := range hosts {
+		rows = append(rows, []string{strconv.Itoa(host.ROID), host.Hostname, strings.Join(host.IPs, " "), host.Status})
+	}
+
+	return writeCSV([]string{"ROID", "Hostname", "IPs", "Status"}, rows)
+}
+
+func (f *CSVFormatter) FormatHost(host *inwx.Host) string {
+	return f.FormatHosts([]inwx.Host{*host})
+}
+
+func (f *CSVFormatter) FormatNameserverSets(sets []inwx.NameserverSet) string {
+	var rows [][]string
+	for _, set := range sets {
+		rows = append(rows, []string{
+			strconv.Itoa(set.ID),
+			set.Name,
+			set.Type,
+			strings.Join(set.Nameservers, " "),
+			set.Hostmaster,
+			set.MasterIP,
+			strconv.FormatBool(set.Visible),
+			strconv.FormatBool(set.ReadOnly),
+		})
+	}
+
+	return writeCSV([]string{"ID", "Name", "Type", "Nameservers", "Hostmaster", "MasterIP", "Visible", "ReadOnly"}, rows)
+}
+
+func (f *CSVFormatter) FormatNameserverSet(set *inwx.NameserverSet) string {
+	return f.FormatNameserverSets([]inwx.NameserverSet{*set})
+}
+
+func (f *CSVFormatter) FormatTags(tags []inwx.Tag) string {
+	var rows [][]string
+	for _, tag := range tags {
+		rows = append(rows, []string{strconv.Itoa(tag.ID), tag.Name, strconv.Itoa(tag.DomainCount)})
+	}
+
+	return writeCSV([]string{"ID", "Name", "Domains"}, rows)
+}
+
+func (f *CSVFormatter) FormatCertificates(certs []inwx.Certificate) string {
+	var rows [][]string
+	for _, cert := range certs {
+		rows = append(rows, []string{
+			strconv.Itoa(cert.ID),
+			cert.CommonName,
+			strings.Join(cert.SAN, " "),
+			cert.Name,
+			cert.Brand,
+			cert.Type,
+			cert.Status,
+			strconv.FormatBool(cert.Active),
+			strconv.FormatBool(cert.AutoRenew),
+			cert.ValidationMethod,
+			csvDate(cert.CreatedAt),
+			csvDate(cert.ExpiresAt),
+		})
+	}
+
+	return writeCSV([]string{"ID", "CommonName", "SAN", "Product", "Brand", "Type", "Status", "Active", "AutoRenew", "Validation", "Created", "Expires"}, rows)
+}
+
+func (f *CSVFormatter) FormatCertificate(cert *inwx.Certificate) string {
+	return f.FormatCertificates([]inwx.Certificate{*cert})
+}
+
+func (f *CSVFormatter) FormatCertificateProducts(products []inwx.CertificateProduct) string {
+	var rows [][]string
+	for _, product := range products {
+		rows = append(rows, []string{
+			strconv.Itoa(product.ID),
+			product.Name,
+			product.Brand,
+			product.Type,
+			strconv.FormatBool(product.Wildcard),
+			strconv.Itoa(product.FreeSANIncluded),
+			strconv.Itoa(product.SAN),
+			csvAmount(product.Price1Year),
+			csvAmount(product.PricePerSAN1Year),
+			product.Currency,
+		})
+	}
+
+	return writeCSV([]string{"ID", "Name", "Brand", "Type", "Wildcard", "FreeSAN", "MaxSAN", "Price1Year", "PricePerSAN1Year", "Currency"}, rows)
+}
+
+func (f *CSVFormatter) FormatCustomer(customer *inwx.Customer) string {
+	return writeCSV([]string{"Field", "Value"}, [][]string{
+		{"CustomerID", strconv.Itoa(customer.CustomerID)},
+		{"CustomerNo", strconv.Itoa(customer.CustomerNo)},
+		{"Username", customer.Username},
+		{"Firstname", customer.Firstname},
+		{"Lastname", customer.Lastname},
+		{"Org", customer.Org},
+		{"Street", customer.Street},
+		{"PostalCode", customer.PostalCode},
+		{"City", customer.City},
+		{"CountryCode", customer.CountryCode},
+		{"Phone", customer.Phone},
+		{"Email", customer.Email},
+		{"EmailBilling", customer.EmailBilling},
+		{"EmailAutomated", customer.EmailAutomated},
+		{"VATNo", customer.VATNo},
+		{"Language", customer.Language},
+		{"Currency", customer.Currency},
+		{"Created", csvDate(customer.CreatedAt)},
+	})
+}
+
+func (f *CSVFormatter) FormatCustomerDownloads(downloads []inwx.CustomerDownload) string {
+	var rows [][]string
+	for _, download := range downloads {
+		rows = append(rows, []string{
+			download.Token,
+			download.Status,
+			csvDate(download.Created),
+			csvDate(download.Expires),
+			strconv.Itoa(download.Downloads),
+		})
+	}
+
+	return writeCSV([]string{"Token", "Status", "Created", "Expires", "Downloads"}, rows)
+}
+
+func (f *CSVFormatter) FormatTemplates(list []*templates.Template) string {
+	var rows [][]string
+	for _, t := range list {
+		var vars []string
+		for _, v := range t.Variables {
+			vars = append(vars, v.Name)
+		}
+		rows = append(rows, []string{
+			t.Name,
+			t.Source,
+			strconv.Itoa(len(t.Records)),
+			strings.Join(vars, " "),
+			t.Description,
+		})
+	}
+
+	return writeCSV([]string{"Name", "Source", "Records", "Variables", "Description"}, rows)
+}
+
+func (f *CSVFormatter) FormatTemplate(t *templates.Template) string {
+	var rows [][]string
+	for _, r := range t.Records {
+		rows = append(rows, []string{
+			r.Name,
+			r.Type,
+			strconv.Itoa(r.Prio),
+			r.Content,
+			strconv.Itoa(r.TTL),
+			r.When,
+		})
+	}
+
+	return writeCSV([]string{"Name", "Type", "Prio", "Content", "TTL", "When"}, rows)
+}
@@ -3,6 +3,7 @@ package output
 import (
 	"encoding/json"
 
+	"github.com/nmeilick/inwx-cli/internal/templates"
 	"github.com/nmeilick/inwx-cli/pkg/inwx"
 )
 
@@ -43,3 +44,130 @@ func (f *JSONFormatter) FormatBackupEntries(entries []*inwx.BackupEntry) string
 	}
 	return string(data)
 }
+
+// marshalJSON renders any value as indented JSON
+func marshalJSON(v interface{}) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (f *JSONFormatter) FormatContacts(contacts []inwx.Contact) string {
+	return marshalJSON(contacts)
+}
+
+func (f *JSONFormatter) FormatContact(contact *inwx.Contact) string {
+	return marshalJSON(contact)
+}
+
+func (f *JSONFormatter) FormatContactLog(entries []inwx.ContactLogEntry) string {
+	return marshalJSON(entries)
+}
+
+func (f *JSONFormatter) FormatDomainWhois(whois *inwx.DomainWhois) string {
+	return marshalJSON(whois)
+}
+
+func (f *JSONFormatter) FormatDomainLog(domainLog *inwx.DomainLog) string {
+	return marshalJSON(domainLog)
+}
+
+func (f *JSONFormatter) FormatDomainStats(stats *inwx.DomainStats) string {
+	return marshalJSON(stats)
+}
+
+func (f *JSONFormatter) FormatAccountBalance(balance *inwx.AccountBalance) string {
+	return marshalJSON(balance)
+}
+
+func (f *JSONFormatter) FormatInvoices(invoices []inwx.Invoice) string {
+	return marshalJSON(invoices)
+}
+
+func (f *JSONFormatter) FormatAccountingLog(accountingLog *inwx.AccountingLog) string {
+	return marshalJSON(accountingLog)
+}
+
+func (f *JSONFormatter) FormatLockedFunds(funds *inwx.LockedFunds) string {
+	return marshalJSON(funds)
+}
+
+func (f *JSONFormatter) FormatCreditLog(creditLog *inwx.CreditLog) string {
+	return marshalJSON(creditLog)
+}
+
+func (f *JSONFormatter) FormatFundsForecast(forecast *inwx.FundsForecast) string {
+	return marshalJSON(forecast)
+}
+
+func (f *JSONFormatter) FormatSubAccounts(accounts []inwx.SubAccount) string {
+	return marshalJSON(accounts)
+}
+
+func (f *JSONFormatter) FormatRoles(roles []inwx.Role) string {
+	return marshalJSON(roles)
+}
+
+func (f *JSONFormatter) FormatMessageQueue(queue *inwx.MessageQueue) string {
+	return marshalJSON(queue)
+}
+
+// FormatMessage renders a single message as one compact JSON line, so that follow
+// mode produces newline-delimited JSON
+func (f *JSONFormatter) FormatMessage(msg *inwx.Message) string {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err.Error() + "\n"
+	}
+	return string(data) + "\n"
+}
+
+func (f *JSONFormatter) FormatHosts(hosts []inwx.Host) string {
+	return marshalJSON(hosts)
+}
+
+func (f *JSONFormatter) FormatHost(host *inwx.Host) string {
+	return marshalJSON(host)
+}
+
+func (f *JSONFormatter) FormatNameserverSets(sets []inwx.NameserverSet) string {
+	return marshalJSON(sets)
+}
+
+func (f *JSONFormatter) FormatNameserverSet(set *inwx.NameserverSet) string {
+	return marshalJSON(set)
+}
+
+func (f *JSONFormatter) FormatTags(tags []inwx.Tag) string {
+	return marshalJSON(tags)
+}
+
+func (f *JSONFormatter) FormatCertificates(certs []inwx.Certificate) string {
+	return marshalJSON(certs)
+}
+
+func (f *JSONFormatter) FormatCertificate(cert *inwx.Certificate) string {
+	return marshalJSON(cert)
+}
+
+func (f *JSONFormatter) FormatCertificateProducts(products []inwx.CertificateProduct) string {
+	return marshalJSON(products)
+}
+
+func (f *JSONFormatter) FormatCustomer(customer *inwx.Customer) string {
+	return marshalJSON(customer)
+}
+
+func (f *JSONFormatter) FormatCustomerDownloads(downloads []inwx.CustomerDownload) string {
+	return marshalJSON(downloads)
+}
+
+func (f *JSONFormatter) FormatTemplates(list []*templates.Template) string {
+	return marshalJSON(list)
+}
+
+func (f *JSONFormatter) FormatTemplate(t *templates.Template) string {
+	return marshalJSON(t)
+}
@@ -2,10 +2,14 @@ package output
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 
+	"github.com/nmeilick/inwx-cli/internal/templates"
 	"github.com/nmeilick/inwx-cli/pkg/inwx"
 )
 
@@ -250,23 +254,45 @@ func (f *TableFormatter) FormatDomains(domains []inwx.Domain) string {
 		return "No domains found"
 	}
 
-	// Calculate dynamic column widths
-	widths := f.calculateDomainWidths(domains)
+	var rows [][]string
+	for _, domain := range domains {
+		renewalMode := domain.RenewalMode
+		if renewalMode == "" {
+			renewalMode = "-"
+		}
+		rows = append(rows, []string{
+			domain.Name,
+			domain.Status,
+			formatDate(domain.ExpiresAt),
+			renewalMode,
+		})
+	}
 
+	return f.renderTable([]string{"DOMAIN", "STATUS", "EXPIRES", "RENEWAL"}, rows, func(row []string) *color.Color {
+		switch row[1] {
+		case "OK":
+			return color.New(color.FgGreen)
+		case "PENDING":
+			return color.New(color.FgYellow)
+		case "EXPIRED":
+			return color.New(color.FgRed)
+		default:
+			return color.New(color.FgWhite)
+		}
+	})
+}
+
+func (f *TableFormatter) FormatAccountInfo(info *inwx.AccountInfo) string {
 	var output strings.Builder
 
-	// Header
-	header := fmt.Sprintf("%-*s %s", widths[0], "DOMAIN", "STATUS")
 	if f.useColors {
-		output.WriteString(color.New(color.Bold, color.FgCyan).Sprint(header))
+		output.WriteString(color.New(color.Bold, color.FgCyan).Sprint("Account Information"))
 	} else {
-		output.WriteString(header)
+		output.WriteString("Account Information")
 	}
 	output.WriteString("\n")
 
-	// Separator
-	totalWidth := widths[0] + widths[1] + 1 // space between columns
-	separator := strings.Repeat("-", totalWidth)
+	separator := strings.Repeat("-", 30)
 	if f.useColors {
 		output.WriteString(color.New(color.FgBlue).Sprint(separator))
 	} else {
@@ -274,56 +300,106 @@ func (f *TableFormatter) FormatDomains(domains []inwx.Domain) string {
 	}
 	output.WriteString("\n")
 
-	// Domains
-	for _, domain := range domains {
-		line := fmt.Sprintf("%-*s %s", widths[0], domain.Name, domain.Status)
+	lines := []string{
+		fmt.Sprintf("Account ID:  %d", info.AccountID),
+		fmt.Sprintf("Customer ID: %d", info.CustomerID),
+		fmt.Sprintf("Username:    %s", info.Username),
+		fmt.Sprintf("Email:       %s", info.Email),
+	}
 
+	for _, line := range lines {
 		if f.useColors {
-			switch domain.Status {
-			case "OK":
-				line = color.New(color.FgGreen).Sprint(line)
-			case "PENDING":
-				line = color.New(color.FgYellow).Sprint(line)
-			case "EXPIRED":
-				line = color.New(color.FgRed).Sprint(line)
-			default:
-				line = color.New(color.FgWhite).Sprint(line)
-			}
+			output.WriteString(color.New(color.FgWhite).Sprint(line))
+		} else {
+			output.WriteString(line)
 		}
-
-		output.WriteString(line)
 		output.WriteString("\n")
 	}
 
 	return output.String()
 }
 
-func (f *TableFormatter) calculateDomainWidths(domains []inwx.Domain) []int {
-	// Minimum widths for headers
-	widths := []int{6, 6} // DOMAIN, STATUS
+// renderTable formats rows as an aligned table with a colored header. The last column
+// is not padded. rowColor may be nil or return nil to leave a row uncolored.
+func (f *TableFormatter) renderTable(headers []string, rows [][]string, rowColor func(row []string) *color.Color) string {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = len(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) && len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
 
-	for _, domain := range domains {
-		if len(domain.Name) > widths[0] {
-			widths[0] = len(domain.Name)
+	formatRow := func(cells []string) string {
+		parts := make([]string, len(cells))
+		for i, cell := range cells {
+			if i == len(cells)-1 {
+				parts[i] = cell
+			} else {
+				parts[i] = fmt.Sprintf("%-*s", widths[i], cell)
+			}
 		}
-		if len(domain.Status) > widths[1] {
-			widths[1] = len(domain.Status)
+		return strings.Join(parts, " ")
+	}
+
+	var output strings.Builder
+
+	header := formatRow(headers)
+	if f.useColors {
+		output.WriteString(color.New(color.Bold, color.FgCyan).Sprint(header))
+	} else {
+		output.WriteString(header)
+	}
+	output.WriteString("\n")
+
+	totalWidth := len(headers) - 1
+	for _, w := range widths {
+		totalWidth += w
+	}
+	separator := strings.Repeat("-", totalWidth)
+	if f.useColors {
+		output.WriteString(color.New(color.FgBlue).Sprint(separator))
+	} else {
+		output.WriteString(separator)
+	}
+	output.WriteString("\n")
+
+	for _, row := range rows {
+		line := formatRow(row)
+		if f.useColors && rowColor != nil {
+			if c := rowColor(row); c != nil {
+				line = c.Sprint(line)
+			}
 		}
+		output.WriteString(line)
+		output.WriteString("\n")
 	}
 
-	return widths
+	return output.String()
 }
 
-func (f *TableFormatter) FormatAccountInfo(info *inwx.AccountInfo) string {
+// renderDetails formats a titled list of label/value pairs
+func (f *TableFormatter) renderDetails(title string, fields [][2]string) string {
 	var output strings.Builder
 
 	if f.useColors {
-		output.WriteString(color.New(color.Bold, color.FgCyan).Sprint("Account Information"))
+		output.WriteString(color.New(color.Bold, color.FgCyan).Sprint(title))
 	} else {
-		output.WriteString("Account Information")
+		output.WriteString(title)
 	}
 	output.WriteString("\n")
 
+	labelWidth := 0
+	for _, field := range fields {
+		if len(field[0]) > labelWidth {
+			labelWidth = len(field[0])
+		}
+	}
+
 	separator := strings.Repeat("-", 30)
 	if f.useColors {
 		output.WriteString(color.New(color.FgBlue).Sprint(separator))
@@ -332,14 +408,11 @@ func (f *TableFormatter) FormatAccountInfo(info *inwx.AccountInfo) string {
 	}
 	output.WriteString("\n")
 
-	lines := []string{
-		fmt.Sprintf("Account ID:  %d", info.AccountID),
-		fmt.Sprintf("Customer ID: %d", info.CustomerID),
-		fmt.Sprintf("Username:    %s", info.Username),
-		fmt.Sprintf("Email:       %s", info.Email),
-	}
-
-	for _, line := range lines {
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		line := fmt.Sprintf("%-*s %s", labelWidth+1, field[0]+":", field[1])
 		if f.useColors {
 			output.WriteString(color.New(color.FgWhite).Sprint(line))
 		} else {
@@ -350,3 +423,636 @@ func (f *TableFormatter) FormatAccountInfo(info *inwx.AccountInfo) string {
 
 	return output.String()
 }
+
+// formatDate formats a date for tables, returning "-" for unset dates
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+// formatDateTime formats a timestamp for tables, returning "-" for unset timestamps
+func formatDateTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func (f *TableFormatter) FormatContacts(contacts []inwx.Contact) string {
+	if len(contacts) == 0 {
+		return "No contacts found"
+	}
+
+	var rows [][]string
+	for _, contact := range contacts {
+		rows = append(rows, []string{
+			strconv.Itoa(contact.ID),
+			contact.Type,
+			contact.Name,
+			contact.Org,
+			contact.Email,
+			contact.CountryCode,
+			strconv.Itoa(contact.UsedCount),
+			contact.VerificationStatus,
+		})
+	}
+
+	return f.renderTable(
+		[]string{"ID", "TYPE", "NAME", "ORG", "EMAIL", "CC", "USED", "VERIFICATION"},
+		rows,
+		func(row []string) *color.Color {
+			switch row[7] {
+			case "CONFIRMED":
+				return color.New(color.FgGreen)
+			case "AWAIT_CONFIRMATION", "TO_NOTIFY":
+				return color.New(color.FgYellow)
+			}
+			return nil
+		},
+	)
+}
+
+func (f *TableFormatter) FormatContact(contact *inwx.Contact) string {
+	return f.renderDetails("Contact Handle", [][2]string{
+		{"ID", strconv.Itoa(contact.ID)},
+		{"Type", contact.Type},
+		{"Name", contact.Name},
+		{"Organisation", contact.Org},
+		{"Street", contact.Street},
+		{"Postal code", contact.PostalCode},
+		{"City", contact.City},
+		{"State", contact.State},
+		{"Country", contact.CountryCode},
+		{"Phone", contact.Phone},
+		{"Fax", contact.Fax},
+		{"Email", contact.Email},
+		{"Remarks", contact.Remarks},
+		{"Used by", fmt.Sprintf("%d domain(s)", contact.UsedCount)},
+		{"Verification", contact.VerificationStatus},
+	})
+}
+
+func (f *TableFormatter) FormatContactLog(entries []inwx.ContactLogEntry) string {
+	if len(entries) == 0 {
+		return "No log entries found"
+	}
+
+	var rows [][]string
+	for _, entry := range entries {
+		rows = append(rows, []string{
+			strconv.Itoa(entry.LogID),
+			formatDateTime(entry.Date),
+			strconv.Itoa(entry.ContactID),
+			entry.Status,
+		})
+	}
+
+	return f.renderTable([]string{"LOG ID", "DATE", "CONTACT", "STATUS"}, rows, nil)
+}
+
+func (f *TableFormatter) FormatDomainWhois(whois *inwx.DomainWhois) string {
+	return strings.TrimRight(whois.Whois, "\n")
+}
+
+func (f *TableFormatter) FormatDomainLog(domainLog *inwx.DomainLog) string {
+	if len(domainLog.Entries) == 0 {
+		return "No log entries found"
+	}
+
+	var rows [][]string
+	for _, entry := range domainLog.Entries {
+		price := "-"
+		if entry.Price != 0 {
+			price = strconv.FormatFloat(entry.Price, 'f', 2, 64)
+		}
+		rows = append(rows, []string{
+			formatDateTime(entry.Date),
+			entry.Domain,
+			entry.Status,
+			price,
+			entry.Account,
+			entry.UserText,
+		})
+	}
+
+	var output strings.Builder
+	output.WriteString(f.renderTable([]string{"DATE", "DOMAIN", "STATUS", "PRICE", "ACCOUNT", "DESCRIPTION"}, rows, nil))
+	output.WriteString(fmt.Sprintf("\nPage %d: %d of %d entries, total price %.2f", domainLog.Page, len(domainLog.Entries), domainLog.Count, domainLog.Sum))
+
+	return output.String()
+}
+
+func (f *TableFormatter) FormatDomainStats(stats *inwx.DomainStats) string {
+	var output strings.Builder
+
+	output.WriteString(fmt.Sprintf("Total domains: %d\n", stats.Total))
+
+	sections := []struct {
+		title  string
+		counts map[string]int
+		byKey  bool
+	}{
+		{"TLD", stats.ByTLD, false},
+		{"STATUS", stats.ByStatus, false},
+		{"EXPIRY MONTH", stats.ByExpiryMonth, true},
+	}
+
+	for _, section := range sections {
+		if len(section.counts) == 0 {
+			continue
+		}
+
+		keys := make([]string, 0, len(section.counts))
+		for key := range section.counts {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			// Months are listed chronologically, everything else by count
+			if section.byKey || section.counts[keys[i]] == section.counts[keys[j]] {
+				return keys[i] < keys[j]
+			}
+			return section.counts[keys[i]] > section.counts[keys[j]]
+		})
+
+		var rows [][]string
+		for _, key := range keys {
+			label := key
+			if label == "" {
+				label = "-"
+			}
+			rows = append(rows, []string{label, strconv.Itoa(section.counts[key])})
+		}
+
+		output.WriteString("\n")
+		output.WriteString(f.renderTable([]string{section.title, "COUNT"}, rows, nil))
+	}
+
+	return strings.TrimRight(output.String(), "\n")
+}
+
+// formatAmount formats a monetary amount with two decimals
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func (f *TableFormatter) FormatAccountBalance(balance *inwx.AccountBalance) string {
+	return f.renderDetails("Account Balance", [][2]string{
+		{"Payments received", formatAmount(balance.Total)},
+		{"Available", formatAmount(balance.Available)},
+		{"Locked", formatAmount(balance.Locked)},
+		{"Credit limit", formatAmount(balance.CreditLimit)},
+	})
+}
+
+func (f *TableFormatter) FormatInvoices(invoices []inwx.Invoice) string {
+	if len(invoices) == 0 {
+		return "No invoices found"
+	}
+
+	var rows [][]string
+	for _, invoice := range invoices {
+		rows = append(rows, []string{
+			invoice.ID,
+			formatDate(invoice.Date),
+			invoice.Type,
+			formatAmount(invoice.PreTax),
+			formatAmount(invoice.AfterTax),
+		})
+	}
+
+	return f.renderTable([]string{"INVOICE", "DATE", "TYPE", "NET", "GROSS"}, rows, nil)
+}
+
+func (f *TableFormatter) FormatAccountingLog(accountingLog *inwx.AccountingLog) string {
+	if len(accountingLog.Entries) == 0 {
+		return "No transactions found"
+	}
+
+	var rows [][]string
+	for _, entry := range accountingLog.Entries {
+		rows = append(rows, []string{
+			formatDateTime(entry.Date),
+			entry.Type,
+			formatAmount(entry.Amount),
+			entry.Details,
+		})
+	}
+
+	var output strings.Builder
+	output.WriteString(f.renderTable([]string{"DATE", "TYPE", "AMOUNT", "DETAILS"}, rows, func(row []string) *color.Color {
+		if strings.HasPrefix(row[2], "-") {
+			return color.New(color.FgRed)
+		}
+		return color.New(color.FgGreen)
+	}))
+	output.WriteString(fmt.Sprintf("\nPage %d: %d of %d entries, sum %s", accountingLog.Page, len(accountingLog.Entries), accountingLog.Count, formatAmount(accountingLog.Sum)))
+
+	return output.String()
+}
+
+func (f *TableFormatter) FormatLockedFunds(funds *inwx.LockedFunds) string {
+	if len(funds.Domains) == 0 && len(funds.Certificates) == 0 {
+		return "No locked funds"
+	}
+
+	var rows [][]string
+	for _, fund := range funds.Domains {
+		rows = append(rows, []string{formatDateTime(fund.Date), "domain", fund.Domain, fund.Status, formatAmount(fund.Amount)})
+	}
+	for _, fund := range funds.Certificates {
+		name := fund.CommonName
+		if name == "" {
+			name = fmt.Sprintf("%s (#%d)", fund.ProductName, fund.CertificateID)
+		}
+		rows = append(rows, []string{formatDateTime(fund.CreatedAt), "certificate", name, fund.Status, formatAmount(fund.Net + fund.VAT)})
+	}
+
+	var output strings.Builder
+	output.WriteString(f.renderTable([]string{"DATE", "KIND", "OBJECT", "STATUS", "AMOUNT"}, rows, nil))
+	output.WriteString(fmt.Sprintf("\nTotal locked: %s", formatAmount(funds.Total())))
+
+	return output.String()
+}
+
+func (f *TableFormatter) FormatCreditLog(creditLog *inwx.CreditLog) string {
+	refundID := ""
+	if creditLog.RefundID > 0 {
+		refundID = strconv.Itoa(creditLog.RefundID)
+	}
+
+	return f.renderDetails("Credit Log", [][2]string{
+		{"ID", strconv.Itoa(creditLog.ID)},
+		{"Time", formatDateTime(creditLog.CreditTime)},
+		{"Amount", formatAmount(creditLog.Amount) + " " + creditLog.Currency},
+		{"Payment method", creditLog.CreditType},
+		{"Transaction", creditLog.TransactionID},
+		{"Details", creditLog.TransactionDetails},
+		{"Card", creditLog.Last4},
+		{"Refund ID", refundID},
+	})
+}
+
+func (f *TableFormatter) FormatFundsForecast(forecast *inwx.FundsForecast) string {
+	var output strings.Builder
+
+	if len(forecast.Renewals) > 0 {
+		var rows [][]string
+		for _, renewal := range forecast.Renewals {
+			price := "unknown"
+			if !renewal.PriceUnknown {
+				price = strings.TrimSpace(formatAmount(renewal.Price) + " " + renewal.Currency)
+			}
+			rows = append(rows, []string{renewal.Domain, formatDate(renewal.RenewalDate), price})
+		}
+		output.WriteString(f.renderTable([]string{"DOMAIN", "RENEWAL", "PRICE"}, rows, nil))
+		output.WriteString("\n")
+	}
+
+	output.WriteString(f.renderDetails("Funds Check", [][2]string{
+		{"Deposit", formatAmount(forecast.Balance)},
+		{"Locked", formatAmount(forecast.Locked)},
+		{"Available", formatAmount(forecast.Available)},
+		{fmt.Sprintf("Renewals (%d days)", forecast.Days), fmt.Sprintf("%s (%d domains)", formatAmount(forecast.RenewalCosts), len(forecast.Renewals))},
+		{"Remaining", formatAmount(forecast.Remaining)},
+		{"Minimum balance", formatAmount(forecast.MinBalance)},
+		{"Credit limit", formatAmount(forecast.CreditLimit)},
+	}))
+
+	status := "✓ Funds are sufficient"
+	statusColor := color.New(color.FgGreen)
+	switch {
+	case forecast.UnknownCosts > 0:
+		status = fmt.Sprintf("✗ Unknown renewal price for %d domain(s), funds cannot be checked", forecast.UnknownCosts)
+		statusColor = color.New(color.FgRed, color.Bold)
+	case !forecast.Sufficient:
+		status = fmt.Sprintf("✗ Insufficient funds: %s below the minimum balance", formatAmount(forecast.MinBalance-forecast.Remaining))
+		statusColor = color.New(color.FgRed, color.Bold)
+	}
+	if f.useColors {
+		status = statusColor.Sprint(status)
+	}
+	output.WriteString(status)
+
+	return output.String()
+}
+
+func (f *TableFormatter) FormatSubAccounts(accounts []inwx.SubAccount) string {
+	if len(accounts) == 0 {
+		return "No sub-accounts found"
+	}
+
+	var rows [][]string
+	for _, account := range accounts {
+		tfa := "no"
+		if account.TFAEnabled {
+			tfa = "yes"
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(account.ID),
+			account.Username,
+			strings.TrimSpace(account.Firstname + " " + account.Lastname),
+			account.Email,
+			tfa,
+		})
+	}
+
+	return f.renderTable([]string{"ID", "USERNAME", "NAME", "EMAIL", "2FA"}, rows, nil)
+}
+
+func (f *TableFormatter) FormatRoles(roles []inwx.Role) string {
+	if len(roles) == 0 {
+		return "No roles assigned"
+	}
+
+	var rows [][]string
+	for _, role := range roles {
+		rows = append(rows, []string{strconv.Itoa(role.ID), role.Name})
+	}
+
+	return f.renderTable([]string{"ID", "ROLE"}, rows, nil)
+}
+
+func (f *TableFormatter) FormatMessageQueue(queue *inwx.MessageQueue) string {
+	if len(queue.Messages) == 0 {
+		return "No messages queued"
+	}
+
+	var rows [][]string
+	for _, msg := range queue.Messages {
+		rows = append(rows, []string{
+			strconv.Itoa(msg.ID),
+			formatDateTime(msg.Date),
+			msg.Type,
+			msg.Object,
+			msg.Status,
+			msg.StatusDetails,
+		})
+	}
+
+	result := f.renderTable([]string{"ID", "DATE", "TYPE", "OBJECT", "STATUS", "DETAILS"}, rows, nil)
+	if queue.Count > 0 {
+		result += fmt.Sprintf("\n%d unread message(s) in queue\n", queue.Count)
+	}
+	return result
+}
+
+// FormatMessage renders a single message as one line for follow mode
+func (f *TableFormatter) FormatMessage(msg *inwx.Message) string {
+	line := fmt.Sprintf("%s  %-11s  %s  %s", formatDateTime(msg.Date), msg.Type, msg.Object, msg.Status)
+	if msg.StatusDetails != "" {
+		line += " (" + msg.StatusDetails + ")"
+	}
+	return fmt.Sprintf("[%d] %s\n", msg.ID, line)
+}
+
+func (f *TableFormatter) FormatHosts(hosts []inwx.Host) string {
+	if len(hosts) == 0 {
+		return "No hosts found"
+	}
+
+	var rows [][]string
+	for _, host := range hosts {
+		rows = append(rows, []string{host.Hostname, strings.Join(host.IPs, ", "), host.Status})
+	}
+
+	return f.renderTable([]string{"HOSTNAME", "IPS", "STATUS"}, rows, nil)
+}
+
+func (f *TableFormatter) FormatHost(host *inwx.Host) string {
+	return f.renderDetails("Host", [][2]string{
+		{"Hostname", host.Hostname},
+		{"ID", strconv.Itoa(host.ROID)},
+		{"Status", host.Status},
+		{"IPs", strings.Join(host.IPs, ", ")},
+	})
+}
+
+func (f *TableFormatter) FormatNameserverSets(sets []inwx.NameserverSet) string {
+	if len(sets) == 0 {
+		return "No nameserver sets found"
+	}
+
+	var rows [][]string
+	for _, set := range sets {
+		rows = append(rows, []string{strconv.Itoa(set.ID), set.Name, set.Type, strings.Join(set.Nameservers, ", ")})
+	}
+
+	return f.renderTable([]string{"ID", "NAME", "TYPE", "NAMESERVERS"}, rows, nil)
+}
+
+func (f *TableFormatter) FormatNameserverSet(set *inwx.NameserverSet) string {
+	return f.renderDetails("Nameserver Set", [][2]string{
+		{"ID", strconv.Itoa(set.ID)},
+		{"Name", set.Name},
+		{"Type", set.Type},
+		{"Nameservers", strings.Join(set.Nameservers, ", ")},
+		{"Hostmaster", set.Hostmaster},
+		{"Master IP", set.MasterIP},
+		{"Web", set.Web},
+		{"Mail", set.Mail},
+		{"Visible", strconv.FormatBool(set.Visible)},
+		{"Read-only", strconv.FormatBool(set.ReadOnly)},
+	})
+}
+
+func (f *TableFormatter) FormatTags(tags []inwx.Tag) string {
+	if len(tags) == 0 {
+		return "No tags found"
+	}
+
+	var rows [][]string
+	for _, tag := range tags {
+		rows = append(rows, []string{strconv.Itoa(tag.ID), tag.Name, strconv.Itoa(tag.DomainCount)})
+	}
+
+	return f.renderTable([]string{"ID", "NAME", "DOMAINS"}, rows, nil)
+}
+
+func (f *TableFormatter) FormatCertificates(certs []inwx.Certificate) string {
+	if len(certs) == 0 {
+		return "No certificates found"
+	}
+
+	var rows [][]string
+	for _, cert := range certs {
+		rows = append(rows, []string{
+			strconv.Itoa(cert.ID),
+			cert.CommonName,
+			cert.Name,
+			cert.Status,
+			formatDateTime(cert.ExpiresAt),
+			strconv.FormatBool(cert.AutoRenew),
+		})
+	}
+
+	return f.renderTable([]string{"ID", "COMMON NAME", "PRODUCT", "STATUS", "EXPIRES", "AUTORENEW"}, rows, nil)
+}
+
+func (f *TableFormatter) FormatCertificate(cert *inwx.Certificate) string {
+	fields := [][2]string{
+		{"ID", strconv.Itoa(cert.ID)},
+		{"Common Name", cert.CommonName},
+		{"SAN", strings.Join(cert.SAN, ", ")},
+		{"Product", strings.TrimSpace(cert.Brand + " " + cert.Name)},
+		{"Type", cert.Type},
+		{"Status", cert.Status},
+		{"Active", strconv.FormatBool(cert.Active)},
+		{"Auto-renew", strconv.FormatBool(cert.AutoRenew)},
+		{"Created", formatDateTime(cert.CreatedAt)},
+		{"Expires", formatDateTime(cert.ExpiresAt)},
+		{"Validation", cert.ValidationMethod},
+	}
+	if cert.ValidationEmail != "" {
+		fields = append(fields, [2]string{"Approver Email", cert.ValidationEmail})
+	}
+	if !cert.IsIssued() && strings.EqualFold(cert.ValidationMethod, inwx.CertificateValidationDNS) {
+		if records, err := cert.DNSValidationRecords(); err == nil {
+			for _, rec := range records {
+				fields = append(fields, [2]string{"Validation Record", fmt.Sprintf("%s %s %s", rec.Name, rec.Type, rec.Content)})
+			}
+		} else if cert.ValidationToken != "" {
+			fields = append(fields, [2]string{"Validation Token", cert.ValidationToken})
+		}
+	}
+
+	return f.renderDetails("Certificate", fields)
+}
+
+func (f *TableFormatter) FormatCertificateProducts(products []inwx.CertificateProduct) string {
+	if len(products) == 0 {
+		return "No certificate products found"
+	}
+
+	var rows [][]string
+	for _, product := range products {
+		rows = append(rows, []string{
+			strconv.Itoa(product.ID),
+			product.Name,
+			product.Brand,
+			product.Type,
+			strconv.FormatBool(product.Wildcard),
+			fmt.Sprintf("%d/%d", product.FreeSANIncluded, product.SAN),
+			fmt.Sprintf("%.2f %s", product.Price1Year, product.Currency),
+		})
+	}
+
+	return f.renderTable([]string{"ID", "NAME", "BRAND", "TYPE", "WILDCARD", "SAN (FREE/MAX)", "PRICE/YEAR"}, rows, nil)
+}
+
+func (f *TableFormatter) FormatCustomer(customer *inwx.Customer) string {
+	name := strings.TrimSpace(customer.Firstname + " " + customer.Lastname)
+	var addressParts []string
+	for _, part := range []string{customer.Street, strings.TrimSpace(customer.PostalCode + " " + customer.City), customer.CountryCode} {
+		if part != "" {
+			addressParts = append(addressParts, part)
+		}
+	}
+
+	return f.renderDetails("Customer", [][2]string{
+		{"Customer No", strconv.Itoa(customer.CustomerNo)},
+		{"Customer ID", strconv.Itoa(customer.CustomerID)},
+		{"Username", customer.Username},
+		{"Name", name},
+		{"Organisation", customer.Org},
+		{"Address", strings.Join(addressParts, ", ")},
+		{"Phone", customer.Phone},
+		{"Email", customer.Email},
+		{"Billing Email", customer.EmailBilling},
+		{"Automated Email", customer.EmailAutomated},
+		{"VAT No", customer.VATNo},
+		{"Language", customer.Language},
+		{"Currency", customer.Currency},
+		{"Payment", customer.PaymentType},
+		{"2FA", customer.TFA},
+		{"Summary Invoice", strconv.FormatBool(customer.SummaryInvoice)},
+		{"Created", formatDateTime(customer.CreatedAt)},
+		{"Last Login", formatDateTime(customer.LastLogin)},
+	})
+}
+
+func (f *TableFormatter) FormatCustomerDownloads(downloads []inwx.CustomerDownload) string {
+	if len(downloads) == 0 {
+		return "No downloads found"
+	}
+
+	var rows [][]string
+	for _, download := range downloads {
+		rows = append(rows, []string{
+			download.Token,
+			download.Status,
+			formatDateTime(download.Created),
+			formatDateTime(download.Expires),
+			strconv.Itoa(download.Downloads),
+		})
+	}
+
+	return f.renderTable([]string{"TOKEN", "STATUS", "CREATED", "EXPIRES", "DOWNLOADS"}, rows, nil)
+}
+
+func (f *TableFormatter) FormatTemplates(list []*templates.Template) string {
+	if len(list) == 0 {
+		return "No templates found"
+	}
+
+	var rows [][]string
+	for _, t := range list {
+		var vars []string
+		for _, v := range t.Variables {
+			if v.Required {
+				vars = append(vars, v.Name+"*")
+			} else {
+				vars = append(vars, v.Name)
+			}
+		}
+		rows = append(rows, []string{
+			t.Name,
+			t.Source,
+			strconv.Itoa(len(t.Records)),
+			strings.Join(vars, ", "),
+			t.Description,
+		})
+	}
+
+	return f.renderTable([]string{"NAME", "SOURCE", "RECORDS", "VARIABLES", "DESCRIPTION"}, rows, nil)
+}
+
+func (f *TableFormatter) FormatTemplate(t *templates.Template) string {
+	var output strings.Builder
+
+	output.WriteString(f.renderDetails("Template "+t.Name, [][2]string{
+		{"Description", t.Description},
+		{"Source", t.Source},
+	}))
+
+	if len(t.Variables) > 0 {
+		var rows [][]string
+		for _, v := range t.Variables {
+			required := ""
+			if v.Required {
+				required = "yes"
+			}
+			rows = append(rows, []string{v.Name, required, v.Default, v.Description})
+		}
+		output.WriteString("\n")
+		output.WriteString(f.renderTable([]string{"VARIABLE", "REQUIRED", "DEFAULT", "DESCRIPTION"}, rows, nil))
+	}
+
+	var rows [][]string
+	for _, r := range t.Records {
+		prio := ""
+		if r.Type == "MX" || r.Type == "SRV" {
+			prio = strconv.Itoa(r.Prio)
+		}
+		ttl := ""
+		if r.TTL > 0 {
+			ttl = strconv.Itoa(r.TTL)
+		}
+		rows = append(rows, []string{r.Name, r.Type, prio, r.Content, ttl, r.When})
+	}
+	output.WriteString("\n")
+	output.WriteString(f.renderTable([]string{"NAME", "TYPE", "PRIO", "CONTENT", "TTL", "WHEN"}, rows, nil))
+
+	return output.String()
+}
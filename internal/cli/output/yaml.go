@@ -3,6 +3,7 @@ package output
 import (
 	"gopkg.in/yaml.v3"
 
+	"github.com/nmeilick/inwx-cli/internal/templates"
 	"github.com/nmeilick/inwx-cli/pkg/inwx"
 )
 
@@ -43,3 +44,124 @@ func (f *YAMLFormatter) FormatBackupEntries(entries []*inwx.BackupEntry) string
 	}
 	return string(data)
 }
+
+// marshalYAML renders any value as YAML
+func marshalYAML(v interface{}) string {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (f *YAMLFormatter) FormatContacts(contacts []inwx.Contact) string {
+	return marshalYAML(contacts)
+}
+
+func (f *YAMLFormatter) FormatContact(contact *inwx.Contact) string {
+	return marshalYAML(contact)
+}
+
+func (f *YAMLFormatter) FormatContactLog(entries []inwx.ContactLogEntry) string {
+	return marshalYAML(entries)
+}
+
+func (f *YAMLFormatter) FormatDomainWhois(whois *inwx.DomainWhois) string {
+	return marshalYAML(whois)
+}
+
+func (f *YAMLFormatter) FormatDomainLog(domainLog *inwx.DomainLog) string {
+	return marshalYAML(domainLog)
+}
+
+func (f *YAMLFormatter) FormatDomainStats(stats *inwx.DomainStats) string {
+	return marshalYAML(stats)
+}
+
+func (f *YAMLFormatter) FormatAccountBalance(balance *inwx.AccountBalance) string {
+	return marshalYAML(balance)
+}
+
+func (f *YAMLFormatter) FormatInvoices(invoices []inwx.Invoice) string {
+	return marshalYAML(invoices)
+}
+
+func (f *YAMLFormatter) FormatAccountingLog(accountingLog *inwx.AccountingLog) string {
+	return marshalYAML(accountingLog)
+}
+
+func (f *YAMLFormatter) FormatLockedFunds(funds *inwx.LockedFunds) string {
+	return marshalYAML(funds)
+}
+
+func (f *YAMLFormatter) FormatCreditLog(creditLog *inwx.CreditLog) string {
+	return marshalYAML(creditLog)
+}
+
+func (f *YAMLFormatter) FormatFundsForecast(forecast *inwx.FundsForecast) string {
+	return marshalYAML(forecast)
+}
+
+func (f *YAMLFormatter) FormatSubAccounts(accounts []inwx.SubAccount) string {
+	return marshalYAML(accounts)
+}
+
+func (f *YAMLFormatter) FormatRoles(roles []inwx.Role) string {
+	return marshalYAML(roles)
+}
+
+func (f *YAMLFormatter) FormatMessageQueue(queue *inwx.MessageQueue) string {
+	return marshalYAML(queue)
+}
+
+func (f *YAMLFormatter) FormatMessage(msg *inwx.Message) string {
+	return "---\n" + marshalYAML(msg)
+}
+
+func (f *YAMLFormatter) FormatHosts(hosts []inwx.Host) string {
+	return marshalYAML(hosts)
+}
+
+func (f *YAMLFormatter) FormatHost(host *inwx.Host) string {
+	return marshalYAML(host)
+}
+
+func (f *YAMLFormatter) FormatNameserverSets(sets []inwx.NameserverSet) string {
+	return marshalYAML(sets)
+}
+
+func (f *YAMLFormatter) FormatNameserverSet(set *inwx.NameserverSet) string {
+	return marshalYAML(set)
+}
+
+func (f *YAMLFormatter) FormatTags(tags []inwx.Tag) string {
+	return marshalYAML(tags)
+}
+
+func (f *YAMLFormatter) FormatCertificates(certs []inwx.Certificate) string {
+	return marshalYAML(certs)
+}
+
+func (f *YAMLFormatter) FormatCertificate(cert *inwx.Certificate) string {
+	return marshalYAML(cert)
+}
+
+func (f *YAMLFormatter) FormatCertificateProducts(products []inwx.CertificateProduct) string {
+	return marshalYAML(products)
+}
+
+func (f *YAMLFormatter) FormatCustomer(customer *inwx.Customer) string {
+	return marshalYAML(customer)
+}
+
+func (f *YAMLFormatter) FormatCustomerDownloads(downloads []inwx.CustomerDownload) string {
+	return marshalYAML(downloads)
+}
+
+func (f *YAMLFormatter) FormatTemplates(list []*templates.Template) string {
+	return marshalYAML(list)
+}
+
+func (f *YAMLFormatter) FormatTemplate(t *templates.Template) string {
+	return marshalYAML(t)
+}
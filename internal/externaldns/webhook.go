@@ -0,0 +1,667 @@
+// Package externaldns implements the ExternalDNS webhook provider protocol on top of the
+// INWX DNS service, so that ExternalDNS can manage records in INWX hosted zones.
+package externaldns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+const (
+	// MediaType is the content type negotiated with ExternalDNS
+	MediaType = "application/external.dns.webhook+json;version=1"
+
+	// minTTL is the lowest TTL accepted by the INWX nameservers
+	minTTL = 300
+	// zoneCacheTTL is how long the list of zones is cached
+	zoneCacheTTL = 5 * time.Minute
+)
+
+// SupportedTypes lists the record types managed through the webhook
+var SupportedTypes = []string{"A", "AAAA", "CNAME", "TXT", "MX", "SRV", "NS", "CAA"}
+
+// Endpoint is a DNS name with its targets as used by ExternalDNS
+type Endpoint struct {
+	DNSName          string                     `json:"dnsName"`
+	Targets          []string                   `json:"targets"`
+	RecordType       string                     `json:"recordType"`
+	SetIdentifier    string                     `json:"setIdentifier,omitempty"`
+	RecordTTL        int64                      `json:"recordTTL,omitempty"`
+	Labels           map[string]string          `json:"labels,omitempty"`
+	ProviderSpecific []ProviderSpecificProperty `json:"providerSpecific,omitempty"`
+}
+
+// ProviderSpecificProperty is a provider specific endpoint setting
+type ProviderSpecificProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Changes is the set of endpoint changes ExternalDNS asks the provider to apply
+type Changes struct {
+	Create    []*Endpoint `json:"Create"`
+	UpdateOld []*Endpoint `json:"UpdateOld"`
+	UpdateNew []*Endpoint `json:"UpdateNew"`
+	Delete    []*Endpoint `json:"Delete"`
+}
+
+// DomainFilter restricts the zones and names managed by the webhook
+type DomainFilter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Match reports whether name is covered by the filter
+func (f DomainFilter) Match(name string) bool {
+	name = normalizeName(name)
+	for _, exclude := range f.Exclude {
+		if matchesDomain(name, exclude) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, include := range f.Include {
+		if matchesDomain(name, include) {
+			return true
+		}
+	}
+	return false
+}
+
+// ZoneLister returns the zones available in the account
+type ZoneLister func(ctx context.Context) ([]string, error)
+
+// Server serves the ExternalDNS webhook API
+type Server struct {
+	dns    *inwx.DNSService
+	zones  ZoneLister
+	filter DomainFilter
+	dryRun bool
+
+	// mu serialises changes, as ExternalDNS does not expect concurrent applies to interleave
+	mu sync.Mutex
+
+	zoneMu      sync.Mutex
+	cachedZones []string
+	cachedAt    time.Time
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithDomainFilter restricts the managed zones and names
+func WithDomainFilter(filter DomainFilter) Option {
+	return func(s *Server) {
+		s.filter = filter
+	}
+}
+
+// WithDryRun logs changes instead of applying them
+func WithDryRun(dryRun bool) Option {
+	return func(s *Server) {
+		s.dryRun = dryRun
+	}
+}
+
+// NewServer creates a webhook server for the given DNS service
+func NewServer(dns *inwx.DNSService, zones ZoneLister, opts ...Option) *Server {
+	s := &Server{
+		dns:   dns,
+		zones: zones,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Handler returns the HTTP handler implementing the webhook endpoints
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleNegotiate)
+	mux.HandleFunc("/records", s.handleRecords)
+	mux.HandleFunc("/adjustendpoints", s.handleAdjustEndpoints)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	return mux
+}
+
+func (s *Server) handleNegotiate(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter := s.filter
+	if len(filter.Include) == 0 {
+		// Announce the zones of the account so ExternalDNS skips foreign names early
+		if zones, err := s.managedZones(r.Context()); err == nil {
+			filter.Include = zones
+		} else {
+			log.Warn().Err(err).Msg("Failed to list zones for negotiation")
+		}
+	}
+
+	writeJSON(w, http.StatusOK, filter)
+}
+
+func (s *Server) handleRecords(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		endpoints, err := s.Records(r.Context())
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to list records")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, endpoints)
+
+	case http.MethodPost:
+		var changes Changes
+		if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+			http.Error(w, fmt.Sprintf("invalid changes: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := s.ApplyChanges(r.Context(), &changes); err != nil {
+			log.Error().Err(err).Msg("Failed to apply changes")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleAdjustEndpoints(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var endpoints []*Endpoint
+	if err := json.NewDecoder(r.Body).Decode(&endpoints); err != nil {
+		http.Error(w, fmt.Sprintf("invalid endpoints: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AdjustEndpoints(endpoints))
+}
+
+// AdjustEndpoints normalises desired endpoints to what INWX stores, so that ExternalDNS
+// does not plan updates for differences the API would discard anyway
+func AdjustEndpoints(endpoints []*Endpoint) []*Endpoint {
+	adjusted := make([]*Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if ep == nil {
+			continue
+		}
+		ep.DNSName = normalizeName(ep.DNSName)
+		ep.RecordType = strings.ToUpper(ep.RecordType)
+		if ep.RecordTTL > 0 && ep.RecordTTL < minTTL {
+			ep.RecordTTL = minTTL
+		}
+		for i, target := range ep.Targets {
+			ep.Targets[i] = normalizeTarget(ep.RecordType, target)
+		}
+		adjusted = append(adjusted, ep)
+	}
+	return adjusted
+}
+
+// Records returns all supported records of the managed zones grouped into endpoints
+func (s *Server) Records(ctx context.Context) ([]*Endpoint, error) {
+	zones, err := s.managedZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := []*Endpoint{}
+	for _, zone := range zones {
+		records, err := s.dns.ListRecords(ctx, inwx.WithDomainFilter(zone))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list records of %s: %w", zone, err)
+		}
+		endpoints = append(endpoints, s.recordsToEndpoints(records)...)
+	}
+
+	return endpoints, nil
+}
+
+// ApplyChanges deletes, updates and creates records as requested by ExternalDNS
+func (s *Server) ApplyChanges(ctx context.Context, changes *Changes) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	zones, err := s.managedZones(ctx)
+	if err != nil {
+		return err
+	}
+	// Records are listed once per zone and re-listed after the zone has been modified
+	current := make(map[string][]inwx.DNSRecord)
+	lookup := func(zone, name, recordType string) ([]inwx.DNSRecord, error) {
+		records, ok := current[zone]
+		if !ok {
+			listed, err := s.dns.ListRecords(ctx, inwx.WithDomainFilter(zone))
+			if err != nil {
+				return nil, fmt.Errorf("failed to list records of %s: %w", zone, err)
+			}
+			records = listed
+			current[zone] = records
+		}
+
+		var matching []inwx.DNSRecord
+		for _, record := range records {
+			if strings.EqualFold(record.Type, recordType) && fqdn(record) == name {
+				matching = append(matching, record)
+			}
+		}
+		return matching, nil
+	}
+
+	for _, ep := range changes.Delete {
+		zone, err := s.zoneFor(zones, ep.DNSName)
+		if err != nil {
+			return err
+		}
+		records, err := lookup(zone, normalizeName(ep.DNSName), ep.RecordType)
+		if err != nil {
+			return err
+		}
+		if err := s.deleteTargets(ctx, records, ep.RecordType, ep.Targets); err != nil {
+			return err
+		}
+		delete(current, zone)
+	}
+
+	for i, ep := range changes.UpdateNew {
+		zone, err := s.zoneFor(zones, ep.DNSName)
+		if err != nil {
+			return err
+		}
+		records, err := lookup(zone, normalizeName(ep.DNSName), ep.RecordType)
+		if err != nil {
+			return err
+		}
+		if err := s.updateEndpoint(ctx, zone, records, findOld(changes.UpdateOld, ep, i), ep); err != nil {
+			return err
+		}
+		delete(current, zone)
+	}
+
+	for _, ep := range changes.Create {
+		zone, err := s.zoneFor(zones, ep.DNSName)
+		if err != nil {
+			return err
+		}
+		records, err := lookup(zone, normalizeName(ep.DNSName), ep.RecordType)
+		if err != nil {
+			return err
+		}
+		existing := make(map[string]bool)
+		for _, record := range records {
+			existing[recordTarget(record)] = true
+		}
+		for _, target := range ep.Targets {
+			if existing[normalizeTarget(ep.RecordType, target)] {
+				continue
+			}
+			if err := s.createTarget(ctx, zone, ep, target); err != nil {
+				return err
+			}
+		}
+		delete(current, zone)
+	}
+
+	return nil
+}
+
+// updateEndpoint replaces the targets of an endpoint, keeping records whose target is
+// unchanged and only adjusting their TTL
+func (s *Server) updateEndpoint(ctx context.Context, zone string, records []inwx.DNSRecord, old, ep *Endpoint) error {
+	desired := make(map[string]bool)
+	for _, target := range ep.Targets {
+		desired[normalizeTarget(ep.RecordType, target)] = true
+	}
+
+	var remove []string
+	existing := make(map[string]bool)
+	for _, record := range records {
+		target := recordTarget(record)
+		if !desired[target] {
+			// Only remove targets owned by the old endpoint when it is known
+			if old == nil || containsTarget(old.Targets, ep.RecordType, target) {
+				remove = append(remove, target)
+			}
+			continue
+		}
+		existing[target] = true
+
+		if ttl := endpointTTL(ep); ep.RecordTTL > 0 && record.TTL != ttl {
+			if s.dryRun {
+				log.Info().Str("name", ep.DNSName).Str("type", ep.RecordType).Int("ttl", ttl).Msg("Would update TTL")
+				continue
+			}
+			if _, err := s.dns.UpdateRecord(ctx, record.ID, inwx.DNSRecord{TTL: ttl}); err != nil {
+				return fmt.Errorf("failed to update %s %s: %w", ep.DNSName, ep.RecordType, err)
+			}
+			log.Info().Str("name", ep.DNSName).Str("type", ep.RecordType).Int("ttl", ttl).Msg("Updated TTL")
+		}
+	}
+
+	if err := s.deleteTargets(ctx, records, ep.RecordType, remove); err != nil {
+		return err
+	}
+
+	for _, target := range ep.Targets {
+		if existing[normalizeTarget(ep.RecordType, target)] {
+			continue
+		}
+		if err := s.createTarget(ctx, zone, ep, target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) createTarget(ctx context.Context, zone string, ep *Endpoint, target string) error {
+	content, prio, err := targetToContent(ep.RecordType, target)
+	if err != nil {
+		return fmt.Errorf("invalid target for %s %s: %w", ep.DNSName, ep.RecordType, err)
+	}
+
+	record := inwx.DNSRecord{
+		Domain:  zone,
+		Name:    recordName(zone, normalizeName(ep.DNSName)),
+		Type:    strings.ToUpper(ep.RecordType),
+		Content: content,
+		TTL:     endpointTTL(ep),
+		Prio:    prio,
+	}
+
+	if s.dryRun {
+		log.Info().Str("name", ep.DNSName).Str("type", record.Type).Str("content", content).Msg("Would create record")
+		return nil
+	}
+	if _, err := s.dns.CreateRecord(ctx, record); err != nil {
+		return fmt.Errorf("failed to create %s %s: %w", ep.DNSName, record.Type, err)
+	}
+	log.Info().Str("name", ep.DNSName).Str("type", record.Type).Str("content", content).Msg("Created record")
+	return nil
+}
+
+func (s *Server) deleteTargets(ctx context.Context, records []inwx.DNSRecord, recordType string, targets []string) error {
+	for _, record := range records {
+		if !containsTarget(targets, recordType, recordTarget(record)) {
+			continue
+		}
+		name := fqdn(record)
+		if s.dryRun {
+			log.Info().Str("name", name).Str("type", record.Type).Str("content", record.Content).Msg("Would delete record")
+			continue
+		}
+		if err := s.dns.DeleteRecord(ctx, record.ID); err != nil {
+			return fmt.Errorf("failed to delete %s %s: %w", name, record.Type, err)
+		}
+		log.Info().Str("name", name).Str("type", record.Type).Str("content", record.Content).Msg("Deleted record")
+	}
+	return nil
+}
+
+// recordsToEndpoints groups the records of a zone by name and type
+func (s *Server) recordsToEndpoints(records []inwx.DNSRecord) []*Endpoint {
+	byKey := make(map[string]*Endpoint)
+	var keys []string
+
+	for _, record := range records {
+		recordType := strings.ToUpper(record.Type)
+		if !isSupported(recordType) {
+			continue
+		}
+		name := fqdn(record)
+		if !s.filter.Match(name) {
+			continue
+		}
+
+		key := name + "|" + recordType
+		ep, ok := byKey[key]
+		if !ok {
+			ep = &Endpoint{
+				DNSName:    name,
+				RecordType: recordType,
+				RecordTTL:  int64(record.TTL),
+			}
+			byKey[key] = ep
+			keys = append(keys, key)
+		}
+		ep.Targets = append(ep.Targets, recordTarget(record))
+	}
+
+	sort.Strings(keys)
+	endpoints := make([]*Endpoint, 0, len(keys))
+	for _, key := range keys {
+		sort.Strings(byKey[key].Targets)
+		endpoints = append(endpoints, byKey[key])
+	}
+	return endpoints
+}
+
+// managedZones returns the account zones allowed by the domain filter
+func (s *Server) managedZones(ctx context.Context) ([]string, error) {
+	s.zoneMu.Lock()
+	defer s.zoneMu.Unlock()
+
+	if s.cachedZones != nil && time.Since(s.cachedAt) < zoneCacheTTL {
+		return s.cachedZones, nil
+	}
+
+	all, err := s.zones(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list zones: %w", err)
+	}
+
+	zones := []string{}
+	for _, zone := range all {
+		zone = normalizeName(zone)
+		if s.zoneAllowed(zone) {
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+
+	s.cachedZones = zones
+	s.cachedAt = time.Now()
+	return zones, nil
+}
+
+// zoneAllowed reports whether a zone may contain names covered by the filter. Zones
+// below an include filter and zones containing one are both allowed.
+func (s *Server) zoneAllowed(zone string) bool {
+	for _, exclude := range s.filter.Exclude {
+		if matchesDomain(zone, exclude) {
+			return false
+		}
+	}
+	if len(s.filter.Include) == 0 {
+		return true
+	}
+	for _, include := range s.filter.Include {
+		include = normalizeName(include)
+		if matchesDomain(zone, include) || matchesDomain(include, zone) {
+			return true
+		}
+	}
+	return false
+}
+
+// zoneFor returns the longest managed zone containing name
+func (s *Server) zoneFor(zones []string, name string) (string, error) {
+	name = normalizeName(name)
+	if !s.filter.Match(name) {
+		return "", fmt.Errorf("%s is excluded by the domain filter", name)
+	}
+
+	best := ""
+	for _, zone := range zones {
+		if matchesDomain(name, zone) && len(zone) > len(best) {
+			best = zone
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no managed zone found for %s", name)
+	}
+	return best, nil
+}
+
+// findOld returns the old endpoint matching an updated endpoint by name, type and set
+// identifier, falling back to the same position in the list
+func findOld(olds []*Endpoint, ep *Endpoint, index int) *Endpoint {
+	for _, old := range olds {
+		if normalizeName(old.DNSName) == normalizeName(ep.DNSName) &&
+			strings.EqualFold(old.RecordType, ep.RecordType) &&
+			old.SetIdentifier == ep.SetIdentifier {
+			return old
+		}
+	}
+	if index < len(olds) {
+		return olds[index]
+	}
+	return nil
+}
+
+func endpointTTL(ep *Endpoint) int {
+	if ep.RecordTTL < minTTL {
+		return minTTL
+	}
+	return int(ep.RecordTTL)
+}
+
+// recordTarget converts an INWX record to an ExternalDNS target. MX and SRV targets
+// carry the priority in front, TXT targets are quoted as written by the TXT registry.
+func recordTarget(record inwx.DNSRecord) string {
+	switch strings.ToUpper(record.Type) {
+	case "MX", "SRV":
+		return strconv.Itoa(record.Prio) + " " + normalizeTarget(record.Type, record.Content)
+	default:
+		return normalizeTarget(record.Type, record.Content)
+	}
+}
+
+// targetToContent converts an ExternalDNS target to INWX record content and priority
+func targetToContent(recordType, target string) (string, int, error) {
+	target = normalizeTarget(recordType, target)
+
+	switch strings.ToUpper(recordType) {
+	case "MX", "SRV":
+		fields := strings.Fields(target)
+		minFields := 2
+		if strings.EqualFold(recordType, "SRV") {
+			minFields = 4
+		}
+		if len(fields) != minFields {
+			return "", 0, fmt.Errorf("expected %d fields in %q", minFields, target)
+		}
+		prio, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid priority in %q", target)
+		}
+		return strings.Join(fields[1:], " "), prio, nil
+	case "TXT":
+		return unquote(target), 0, nil
+	default:
+		return target, 0, nil
+	}
+}
+
+// normalizeTarget brings targets into the form returned by recordTarget
+func normalizeTarget(recordType, target string) string {
+	target = strings.TrimSpace(target)
+	switch strings.ToUpper(recordType) {
+	case "CNAME", "NS", "MX", "SRV":
+		return strings.ToLower(strings.TrimSuffix(target, "."))
+	case "TXT":
+		return `"` + unquote(target) + `"`
+	default:
+		return target
+	}
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func containsTarget(targets []string, recordType, target string) bool {
+	for _, t := range targets {
+		if normalizeTarget(recordType, t) == target {
+			return true
+		}
+	}
+	return false
+}
+
+func isSupported(recordType string) bool {
+	for _, t := range SupportedTypes {
+		if t == recordType {
+			return true
+		}
+	}
+	return false
+}
+
+func fqdn(record inwx.DNSRecord) string {
+	if record.Name == "" || record.Name == "@" {
+		return normalizeName(record.Domain)
+	}
+	return normalizeName(record.Name + "." + record.Domain)
+}
+
+func recordName(zone, name string) string {
+	if name == zone {
+		return "@"
+	}
+	return strings.TrimSuffix(name, "."+zone)
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
+
+func matchesDomain(name, domain string) bool {
+	domain = normalizeName(domain)
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", MediaType)
+	w.Header().Set("Vary", "Content-Type")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error().Err(err).Msg("Failed to write response")
+	}
+}
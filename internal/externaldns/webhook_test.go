@@ -0,0 +1,499 @@
+package externaldns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+func init() {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+}
+
+// fakeRecord is a record stored by fakeRobot
+type fakeRecord struct {
+	ID      int
+	Domain  string
+	Name    string // fully qualified, as returned by nameserver.info
+	Type    string
+	Content string
+	TTL     int
+	Prio    int
+}
+
+// fakeRobot is a minimal DomRobot JSON-RPC backend keeping records in memory. Calls
+// without a valid session cookie fail with code 2200 like the real API.
+type fakeRobot struct {
+	mu      sync.Mutex
+	records []fakeRecord
+	nextID  int
+	session string
+	logins  int
+	calls   []string
+}
+
+func newFakeRobot(records ...fakeRecord) *fakeRobot {
+	f := &fakeRobot{nextID: 1000}
+	for i, record := range records {
+		record.ID = i + 1
+		f.records = append(f.records, record)
+	}
+	return f
+}
+
+// expireSession invalidates the current session, as INWX does after inactivity
+func (f *fakeRobot) expireSession() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.session = ""
+}
+
+func (f *fakeRobot) find(name, recordType string) []fakeRecord {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var found []fakeRecord
+	for _, record := range f.records {
+		if record.Name == name && record.Type == recordType {
+			found = append(found, record)
+		}
+	}
+	return found
+}
+
+func (f *fakeRobot) methods() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+func (f *fakeRobot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Method string                 `json:"method"`
+		Params map[string]interface{} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, request.Method)
+
+	response := map[string]interface{}{"code": 1000, "msg": "Command completed successfully"}
+	params := request.Params
+
+	if request.Method == "account.login" {
+		f.logins++
+		f.session = fmt.Sprintf("session-%d", f.logins)
+		http.SetCookie(w, &http.Cookie{Name: "domrobot", Value: f.session})
+		_ = json.NewEncoder(w).Encode(response)
+		return
+	}
+	if cookie, err := r.Cookie("domrobot"); err != nil || f.session == "" || cookie.Value != f.session {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 2200, "msg": "Authentication error"})
+		return
+	}
+
+	switch request.Method {
+	case "account.logout":
+		f.session = ""
+
+	case "nameserver.info":
+		domain, _ := params["domain"].(string)
+		records := []map[string]interface{}{}
+		for _, record := range f.records {
+			if record.Domain != domain {
+				continue
+			}
+			records = append(records, map[string]interface{}{
+				"id":      record.ID,
+				"name":    record.Name,
+				"type":    record.Type,
+				"content": record.Content,
+				"ttl":     record.TTL,
+				"prio":    record.Prio,
+			})
+		}
+		response["resData"] = map[string]interface{}{"domain": domain, "record": records}
+
+	case "nameserver.createRecord":
+		f.nextID++
+		record := fakeRecord{
+			ID:      f.nextID,
+			Domain:  params["domain"].(string),
+			Type:    params["type"].(string),
+			Content: params["content"].(string),
+			TTL:     int(params["ttl"].(float64)),
+		}
+		record.Name = record.Domain
+		if name := params["name"].(string); name != "@" && name != "" {
+			record.Name = name + "." + record.Domain
+		}
+		if prio, ok := params["prio"].(float64); ok {
+			record.Prio = int(prio)
+		}
+		f.records = append(f.records, record)
+		response["resData"] = map[string]interface{}{"id": record.ID}
+
+	case "nameserver.updateRecord":
+		id := int(params["id"].(float64))
+		for i := range f.records {
+			if f.records[i].ID != id {
+				continue
+			}
+			if content, ok := params["content"].(string); ok {
+				f.records[i].Content = content
+			}
+			if ttl, ok := params["ttl"].(float64); ok {
+				f.records[i].TTL = int(ttl)
+			}
+			if prio, ok := params["prio"].(float64); ok {
+				f.records[i].Prio = int(prio)
+			}
+		}
+
+	case "nameserver.deleteRecord":
+		id := int(params["id"].(float64))
+		kept := f.records[:0]
+		for _, record := range f.records {
+			if record.ID != id {
+				kept = append(kept, record)
+			}
+		}
+		f.records = kept
+
+	default:
+		response = map[string]interface{}{"code": 2000, "msg": "Unknown command " + request.Method}
+	}
+
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// newTestWebhook starts a fake DomRobot and a webhook server using it
+func newTestWebhook(t *testing.T, robot *fakeRobot, zones []string, opts ...Option) *httptest.Server {
+	t.Helper()
+
+	backend := httptest.NewServer(robot)
+	t.Cleanup(backend.Close)
+
+	client, err := inwx.NewClient(
+		inwx.WithCredentials("user", "secret"),
+		inwx.WithEndpoint(backend.URL),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	lister := func(ctx context.Context) ([]string, error) {
+		return zones, nil
+	}
+	webhook := httptest.NewServer(NewServer(client.DNS(), lister, opts...).Handler())
+	t.Cleanup(webhook.Close)
+	return webhook
+}
+
+func doRequest(t *testing.T, method, url string, body interface{}) *http.Response {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", MediaType)
+	if body != nil {
+		req.Header.Set("Content-Type", MediaType)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func decodeResponse(t *testing.T, resp *http.Response, v interface{}) {
+	t.Helper()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %s", resp.Status)
+	}
+	if got := resp.Header.Get("Content-Type"); got != MediaType {
+		t.Errorf("Content-Type = %q, want %q", got, MediaType)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func sampleRobot() *fakeRobot {
+	return newFakeRobot(
+		fakeRecord{Domain: "example.com", Name: "example.com", Type: "SOA", Content: "ns.inwx.de hostmaster.inwx.de 2024010101 10800 3600 604800 3600", TTL: 86400},
+		fakeRecord{Domain: "example.com", Name: "example.com", Type: "MX", Content: "Mail.Example.com", TTL: 3600, Prio: 10},
+		fakeRecord{Domain: "example.com", Name: "www.example.com", Type: "A", Content: "192.0.2.2", TTL: 300},
+		fakeRecord{Domain: "example.com", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: 300},
+		fakeRecord{Domain: "example.com", Name: "a-www.example.com", Type: "TXT", Content: "heritage=external-dns,external-dns/owner=default,external-dns/resource=ingress/default/web", TTL: 300},
+		fakeRecord{Domain: "example.com", Name: "app.internal.example.com", Type: "A", Content: "10.0.0.1", TTL: 300},
+		fakeRecord{Domain: "example.org", Name: "www.example.org", Type: "A", Content: "198.51.100.1", TTL: 300},
+	)
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter DomainFilter
+		want   DomainFilter
+	}{
+		{
+			name: "account zones",
+			want: DomainFilter{Include: []string{"example.com", "example.org"}},
+		},
+		{
+			name:   "configured filter",
+			filter: DomainFilter{Include: []string{"example.com"}, Exclude: []string{"internal.example.com"}},
+			want:   DomainFilter{Include: []string{"example.com"}, Exclude: []string{"internal.example.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := newTestWebhook(t, sampleRobot(), []string{"Example.org", "example.com."}, WithDomainFilter(tt.filter))
+
+			var got DomainFilter
+			decodeResponse(t, doRequest(t, http.MethodGet, webhook.URL+"/", nil), &got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("negotiated %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRecords(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter DomainFilter
+		want   []*Endpoint
+	}{
+		{
+			name: "all zones",
+			want: []*Endpoint{
+				{DNSName: "a-www.example.com", RecordType: "TXT", RecordTTL: 300, Targets: []string{`"heritage=external-dns,external-dns/owner=default,external-dns/resource=ingress/default/web"`}},
+				{DNSName: "app.internal.example.com", RecordType: "A", RecordTTL: 300, Targets: []string{"10.0.0.1"}},
+				{DNSName: "example.com", RecordType: "MX", RecordTTL: 3600, Targets: []string{"10 mail.example.com"}},
+				{DNSName: "www.example.com", RecordType: "A", RecordTTL: 300, Targets: []string{"192.0.2.1", "192.0.2.2"}},
+				{DNSName: "www.example.org", RecordType: "A", RecordTTL: 300, Targets: []string{"198.51.100.1"}},
+			},
+		},
+		{
+			name:   "domain filter",
+			filter: DomainFilter{Include: []string{"example.com"}, Exclude: []string{"internal.example.com"}},
+			want: []*Endpoint{
+				{DNSName: "a-www.example.com", RecordType: "TXT", RecordTTL: 300, Targets: []string{`"heritage=external-dns,external-dns/owner=default,external-dns/resource=ingress/default/web"`}},
+				{DNSName: "example.com", RecordType: "MX", RecordTTL: 3600, Targets: []string{"10 mail.example.com"}},
+				{DNSName: "www.example.com", RecordType: "A", RecordTTL: 300, Targets: []string{"192.0.2.1", "192.0.2.2"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := newTestWebhook(t, sampleRobot(), []string{"example.com", "example.org"}, WithDomainFilter(tt.filter))
+
+			var got []*Endpoint
+			decodeResponse(t, doRequest(t, http.MethodGet, webhook.URL+"/records", nil), &got)
+			if !reflect.DeepEqual(got, tt.want) {
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(tt.want)
+				t.Errorf("records\n got %s\nwant %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestPostRecords(t *testing.T) {
+	robot := sampleRobot()
+	webhook := newTestWebhook(t, robot, []string{"example.com", "example.org"},
+		WithDomainFilter(DomainFilter{Exclude: []string{"internal.example.com"}}))
+
+	changes := Changes{
+		Create: []*Endpoint{
+			{DNSName: "api.example.com", RecordType: "A", RecordTTL: 60, Targets: []string{"192.0.2.10"}},
+			{DNSName: "a-api.example.com", RecordType: "TXT", Targets: []string{`"heritage=external-dns,external-dns/owner=default,external-dns/resource=ingress/default/api"`}},
+		},
+		UpdateOld: []*Endpoint{
+			{DNSName: "www.example.com", RecordType: "A", RecordTTL: 300, Targets: []string{"192.0.2.1", "192.0.2.2"}},
+		},
+		UpdateNew: []*Endpoint{
+			{DNSName: "www.example.com", RecordType: "A", RecordTTL: 600, Targets: []string{"192.0.2.1", "192.0.2.3"}},
+		},
+		Delete: []*Endpoint{
+			{DNSName: "www.example.org", RecordType: "A", Targets: []string{"198.51.100.1"}},
+		},
+	}
+
+	resp := doRequest(t, http.MethodPost, webhook.URL+"/records", changes)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("unexpected status %s", resp.Status)
+	}
+
+	created := robot.find("api.example.com", "A")
+	if len(created) != 1 || created[0].Content != "192.0.2.10" || created[0].TTL != minTTL {
+		t.Errorf("api.example.com A = %+v, want 192.0.2.10 with TTL %d", created, minTTL)
+	}
+
+	registry := robot.find("a-api.example.com", "TXT")
+	if len(registry) != 1 || strings.HasPrefix(registry[0].Content, `"`) ||
+		registry[0].Content != "heritage=external-dns,external-dns/owner=default,external-dns/resource=ingress/default/api" {
+		t.Errorf("registry TXT record = %+v, want unquoted content", registry)
+	}
+
+	var targets []string
+	for _, record := range robot.find("www.example.com", "A") {
+		targets = append(targets, record.Content)
+		if record.TTL != 600 {
+			t.Errorf("www.example.com A %s has TTL %d, want 600", record.Content, record.TTL)
+		}
+	}
+	sort.Strings(targets)
+	if want := []string{"192.0.2.1", "192.0.2.3"}; !reflect.DeepEqual(targets, want) {
+		t.Errorf("www.example.com A targets = %v, want %v", targets, want)
+	}
+
+	if deleted := robot.find("www.example.org", "A"); len(deleted) != 0 {
+		t.Errorf("www.example.org A was not deleted: %+v", deleted)
+	}
+}
+
+func TestPostRecordsDomainFilter(t *testing.T) {
+	robot := sampleRobot()
+	webhook := newTestWebhook(t, robot, []string{"example.com", "example.org"},
+		WithDomainFilter(DomainFilter{Include: []string{"example.com"}, Exclude: []string{"internal.example.com"}}))
+
+	tests := []struct {
+		name     string
+		endpoint *Endpoint
+	}{
+		{"excluded name", &Endpoint{DNSName: "db.internal.example.com", RecordType: "A", Targets: []string{"10.0.0.2"}}},
+		{"zone outside filter", &Endpoint{DNSName: "new.example.org", RecordType: "A", Targets: []string{"198.51.100.2"}}},
+		{"unknown zone", &Endpoint{DNSName: "www.example.net", RecordType: "A", Targets: []string{"203.0.113.1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := doRequest(t, http.MethodPost, webhook.URL+"/records", Changes{Create: []*Endpoint{tt.endpoint}})
+			if resp.StatusCode != http.StatusInternalServerError {
+				t.Errorf("status = %s, want %d", resp.Status, http.StatusInternalServerError)
+			}
+			if records := robot.find(tt.endpoint.DNSName, tt.endpoint.RecordType); len(records) != 0 {
+				t.Errorf("record was created: %+v", records)
+			}
+		})
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	robot := sampleRobot()
+	webhook := newTestWebhook(t, robot, []string{"example.com"}, WithDryRun(true))
+
+	changes := Changes{
+		Create: []*Endpoint{{DNSName: "api.example.com", RecordType: "A", Targets: []string{"192.0.2.10"}}},
+		Delete: []*Endpoint{{DNSName: "www.example.com", RecordType: "A", Targets: []string{"192.0.2.1"}}},
+	}
+	resp := doRequest(t, http.MethodPost, webhook.URL+"/records", changes)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("unexpected status %s", resp.Status)
+	}
+
+	for _, method := range robot.methods() {
+		if method != "account.login" && method != "nameserver.info" {
+			t.Errorf("dry run called %s", method)
+		}
+	}
+}
+
+func TestAdjustEndpoints(t *testing.T) {
+	webhook := newTestWebhook(t, sampleRobot(), []string{"example.com"})
+
+	endpoints := []*Endpoint{
+		{DNSName: "WWW.Example.com.", RecordType: "a", RecordTTL: 60, Targets: []string{"192.0.2.1"}},
+		{DNSName: "a-www.example.com", RecordType: "TXT", Targets: []string{"heritage=external-dns,external-dns/owner=default"}},
+		{DNSName: "alias.example.com", RecordType: "CNAME", RecordTTL: 3600, Targets: []string{"Target.Example.NET."}},
+		{DNSName: "example.com", RecordType: "MX", Targets: []string{"10 Mail.Example.com."}},
+	}
+	want := []*Endpoint{
+		{DNSName: "www.example.com", RecordType: "A", RecordTTL: minTTL, Targets: []string{"192.0.2.1"}},
+		{DNSName: "a-www.example.com", RecordType: "TXT", Targets: []string{`"heritage=external-dns,external-dns/owner=default"`}},
+		{DNSName: "alias.example.com", RecordType: "CNAME", RecordTTL: 3600, Targets: []string{"target.example.net"}},
+		{DNSName: "example.com", RecordType: "MX", Targets: []string{"10 mail.example.com"}},
+	}
+
+	var got []*Endpoint
+	decodeResponse(t, doRequest(t, http.MethodPost, webhook.URL+"/adjustendpoints", endpoints), &got)
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		t.Errorf("adjusted\n got %s\nwant %s", gotJSON, wantJSON)
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	robot := sampleRobot()
+	webhook := newTestWebhook(t, robot, []string{"example.com"})
+
+	robot.expireSession()
+
+	var got []*Endpoint
+	decodeResponse(t, doRequest(t, http.MethodGet, webhook.URL+"/records", nil), &got)
+	if len(got) == 0 {
+		t.Error("no records returned after the session expired")
+	}
+	robot.mu.Lock()
+	logins := robot.logins
+	robot.mu.Unlock()
+	if logins != 2 {
+		t.Errorf("logged in %d times, want 2", logins)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	webhook := newTestWebhook(t, sampleRobot(), []string{"example.com"})
+
+	for _, tt := range []struct{ method, path string }{
+		{http.MethodPost, "/"},
+		{http.MethodDelete, "/records"},
+		{http.MethodGet, "/adjustendpoints"},
+	} {
+		resp := doRequest(t, tt.method, webhook.URL+tt.path, nil)
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("%s %s: status = %s, want %d", tt.method, tt.path, resp.Status, http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package rfc2136
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DNS header values used by the gateway
+const (
+	opcodeQuery  = 0
+	opcodeUpdate = 5
+
+	flagQR = 1 << 15
+
+	classIN   = 1
+	classNONE = 254
+	classANY  = 255
+
+	typeSOA  = 6
+	typeTSIG = 250
+	typeANY  = 255
+)
+
+// Response codes (RFC 1035, RFC 2136 and RFC 8945)
+const (
+	RcodeSuccess  = 0
+	RcodeFormErr  = 1
+	RcodeServFail = 2
+	RcodeNXDomain = 3
+	RcodeNotImp   = 4
+	RcodeRefused  = 5
+	RcodeYXDomain = 6
+	RcodeYXRRSet  = 7
+	RcodeNXRRSet  = 8
+	RcodeNotAuth  = 9
+	RcodeNotZone  = 10
+
+	RcodeBadSig  = 16
+	RcodeBadKey  = 17
+	RcodeBadTime = 18
+)
+
+var errTruncated = errors.New("message truncated")
+
+// header is the fixed part of a DNS message. In UPDATE messages the four sections are
+// zone, prerequisite, update and additional.
+type header struct {
+	ID      uint16
+	Flags   uint16
+	QDCount uint16
+	ANCount uint16
+	NSCount uint16
+	ARCount uint16
+}
+
+func (h header) opcode() int {
+	return int(h.Flags>>11) & 0xf
+}
+
+// question is an entry of the question (zone) section
+type question struct {
+	Name  string
+	Type  uint16
+	Class uint16
+}
+
+// rr is a resource record. RData is kept in wire format, with compressed names in
+// the RDATA of the well-known types expanded by the parser.
+type rr struct {
+	Name  string
+	Type  uint16
+	Class uint16
+	TTL   uint32
+	RData []byte
+
+	// offset is where the record starts in the message, needed to strip the TSIG record
+	offset int
+}
+
+// message is a parsed DNS message
+type message struct {
+	header     header
+	question   []question
+	answer     []rr
+	authority  []rr
+	additional []rr
+}
+
+// parseMessage parses a DNS message in wire format
+func parseMessage(data []byte) (*message, error) {
+	if len(data) < 12 {
+		return nil, errTruncated
+	}
+
+	m := &message{header: header{
+		ID:      binary.BigEndian.Uint16(data[0:]),
+		Flags:   binary.BigEndian.Uint16(data[2:]),
+		QDCount: binary.BigEndian.Uint16(data[4:]),
+		ANCount: binary.BigEndian.Uint16(data[6:]),
+		NSCount: binary.BigEndian.Uint16(data[8:]),
+		ARCount: binary.BigEndian.Uint16(data[10:]),
+	}}
+
+	off := 12
+	for i := 0; i < int(m.header.QDCount); i++ {
+		name, next, err := readName(data, off)
+		if err != nil {
+			return nil, err
+		}
+		if next+4 > len(data) {
+			return nil, errTruncated
+		}
+		m.question = append(m.question, question{
+			Name:  name,
+			Type:  binary.BigEndian.Uint16(data[next:]),
+			Class: binary.BigEndian.Uint16(data[next+2:]),
+		})
+		off = next + 4
+	}
+
+	sections := []struct {
+		count uint16
+		rrs   *[]rr
+	}{
+		{m.header.ANCount, &m.answer},
+		{m.header.NSCount, &m.authority},
+		{m.header.ARCount, &m.additional},
+	}
+	for _, section := range sections {
+		for i := 0; i < int(section.count); i++ {
+			record, next, err := readRR(data, off)
+			if err != nil {
+				return nil, err
+			}
+			*section.rrs = append(*section.rrs, record)
+			off = next
+		}
+	}
+
+	if off != len(data) {
+		return nil, fmt.Errorf("%d trailing bytes after message", len(data)-off)
+	}
+
+	return m, nil
+}
+
+func readRR(data []byte, off int) (rr, int, error) {
+	start := off
+	name, off, err := readName(data, off)
+	if err != nil {
+		return rr{}, 0, err
+	}
+	if off+10 > len(data) {
+		return rr{}, 0, errTruncated
+	}
+
+	record := rr{
+		Name:   name,
+		Type:   binary.BigEndian.Uint16(data[off:]),
+		Class:  binary.BigEndian.Uint16(data[off+2:]),
+		TTL:    binary.BigEndian.Uint32(data[off+4:]),
+		offset: start,
+	}
+	length := int(binary.BigEndian.Uint16(data[off+8:]))
+	off += 10
+	if off+length > len(data) {
+		return rr{}, 0, errTruncated
+	}
+
+	rdata, err := expandRData(data, off, length, record.Type)
+	if err != nil {
+		return rr{}, 0, err
+	}
+	record.RData = rdata
+
+	return record, off + length, nil
+}
+
+// expandRData copies the RDATA of a record and expands compressed names, so that the
+// record can be interpreted without the surrounding message
+func expandRData(data []byte, off, length int, rrType uint16) ([]byte, error) {
+	end := off + length
+
+	// Number of fixed bytes in front of the domain name, for types that contain one
+	prefix := -1
+	switch rrType {
+	case typeNS, typeCNAME, typePTR:
+		prefix = 0
+	case typeMX:
+		prefix = 2
+	case typeSRV:
+		prefix = 6
+	}
+	if prefix < 0 || length == 0 {
+		return append([]byte(nil), data[off:end]...), nil
+	}
+	if prefix > length {
+		return nil, errTruncated
+	}
+
+	name, next, err := readName(data, off+prefix)
+	if err != nil {
+		return nil, err
+	}
+	if next != end {
+		return nil, fmt.Errorf("invalid RDATA length for type %d", rrType)
+	}
+
+	rdata := append([]byte(nil), data[off:off+prefix]...)
+	return appendName(rdata, name), nil
+}
+
+// readName reads a possibly compressed domain name. The name is returned in lower case
+// without the trailing dot; the root is returned as the empty string.
+func readName(data []byte, off int) (string, int, error) {
+	var labels []string
+	next := -1
+	jumps := 0
+
+	for {
+		if off >= len(data) {
+			return "", 0, errTruncated
+		}
+		length := int(data[off])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = off + 1
+			}
+			return strings.ToLower(strings.Join(labels, ".")), next, nil
+		case length&0xc0 == 0xc0:
+			if off+1 >= len(data) {
+				return "", 0, errTruncated
+			}
+			if jumps++; jumps > 64 {
+				return "", 0, errors.New("compression loop in domain name")
+			}
+			if next < 0 {
+				next = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(data[off:]) & 0x3fff)
+		case length&0xc0 != 0:
+			return "", 0, errors.New("unsupported label type in domain name")
+		default:
+			if off+1+length > len(data) {
+				return "", 0, errTruncated
+			}
+			label := string(data[off+1 : off+1+length])
+			if strings.ContainsAny(label, ". ") {
+				return "", 0, fmt.Errorf("unsupported character in label %q", label)
+			}
+			labels = append(labels, label)
+			off += 1 + length
+		}
+	}
+}
+
+// appendName appends a domain name in uncompressed wire format
+func appendName(b []byte, name string) []byte {
+	name = strings.TrimSuffix(name, ".")
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			b = append(b, byte(len(label)))
+			b = append(b, label...)
+		}
+	}
+	return append(b, 0)
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return binary.BigEndian.AppendUint16(b, v)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return binary.BigEndian.AppendUint32(b, v)
+}
+
+// appendUint48 appends the 48 bit time format used by TSIG
+func appendUint48(b []byte, v uint64) []byte {
+	return append(b, byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendRR(b []byte, record rr) []byte {
+	b = appendName(b, record.Name)
+	b = appendUint16(b, record.Type)
+	b = appendUint16(b, record.Class)
+	b = appendUint32(b, record.TTL)
+	b = appendUint16(b, uint16(len(record.RData)))
+	return append(b, record.RData...)
+}
+
+// packResponse builds the response to a request with the given rcode. The zone section
+// is echoed; the other sections are empty apart from an optional TSIG record, which is
+// appended by the caller.
+func packResponse(request *message, rcode int) []byte {
+	flags := flagQR | request.header.Flags&(0xf<<11) | request.header.Flags&(1<<8) | uint16(rcode&0xf)
+
+	b := make([]byte, 0, 512)
+	b = appendUint16(b, request.header.ID)
+	b = appendUint16(b, flags)
+	b = appendUint16(b, uint16(len(request.question)))
+	b = appendUint16(b, 0)
+	b = appendUint16(b, 0)
+	b = appendUint16(b, 0)
+	for _, q := range request.question {
+		b = appendName(b, q.Name)
+		b = appendUint16(b, q.Type)
+		b = appendUint16(b, q.Class)
+	}
+	return b
+}
+
+// packFormErr builds a FORMERR response for a message that could not be parsed
+func packFormErr(data []byte) []byte {
+	if len(data) < 4 {
+		return nil
+	}
+	b := make([]byte, 12)
+	copy(b, data[:2])
+	flags := flagQR | binary.BigEndian.Uint16(data[2:])&(0xf<<11) | RcodeFormErr
+	binary.BigEndian.PutUint16(b[2:], flags)
+	return b
+}
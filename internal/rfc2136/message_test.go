@@ -0,0 +1,113 @@
+package rfc2136
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseMessage(t *testing.T) {
+	m, err := parseMessage(signedUpdateBytes(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.header.opcode() != opcodeUpdate || m.header.ID != 0x1234 {
+		t.Errorf("header %+v, want UPDATE with ID 0x1234", m.header)
+	}
+	if len(m.question) != 1 || m.question[0].Name != "example.com" || m.question[0].Type != typeSOA {
+		t.Errorf("zone section %+v, want example.com SOA", m.question)
+	}
+	if len(m.authority) != 1 || m.authority[0].Name != "www.example.com" || m.authority[0].TTL != 300 {
+		t.Errorf("update section %+v, want www.example.com with TTL 300", m.authority)
+	}
+	if len(m.additional) != 1 || m.additional[0].Name != "update-key" || m.additional[0].Type != typeTSIG {
+		t.Errorf("additional section %+v, want the TSIG record of update-key", m.additional)
+	}
+}
+
+func TestParseMessageTruncated(t *testing.T) {
+	data := signedUpdateBytes(t)
+
+	// Every prefix of the message lacks part of a section the header announces
+	for n := 0; n < len(data); n++ {
+		if _, err := parseMessage(data[:n]); err == nil {
+			t.Errorf("parseMessage of the first %d bytes succeeded, want error", n)
+		}
+	}
+
+	if _, err := parseMessage(data[:11]); !errors.Is(err, errTruncated) {
+		t.Errorf("parseMessage of a short header = %v, want %v", err, errTruncated)
+	}
+
+	// A record count beyond the records present
+	data[7] = 1
+	if _, err := parseMessage(data); !errors.Is(err, errTruncated) {
+		t.Errorf("parseMessage with a missing record = %v, want %v", err, errTruncated)
+	}
+}
+
+func TestParseMessageTrailingData(t *testing.T) {
+	data := append(signedUpdateBytes(t), 0)
+
+	if _, err := parseMessage(data); err == nil || !strings.Contains(err.Error(), "trailing") {
+		t.Errorf("parseMessage with a trailing byte = %v, want trailing bytes error", err)
+	}
+}
+
+func TestParseMessageCompressionLoop(t *testing.T) {
+	header := []byte{0x12, 0x34, 0x28, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+	tests := []struct {
+		name string
+		zone []byte
+	}{
+		// A pointer to itself at offset 12
+		{"self", []byte{0xc0, 0x0c}},
+		// Two pointers at offsets 12 and 14 pointing to each other
+		{"mutual", []byte{0xc0, 0x0e, 0xc0, 0x0c}},
+		// A label followed by a pointer back to the label
+		{"label", []byte{0x03, 'w', 'w', 'w', 0xc0, 0x0c}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := append(append([]byte(nil), header...), test.zone...)
+			data = append(data, 0x00, 0x06, 0x00, 0x01)
+
+			_, err := parseMessage(data)
+			if err == nil || !strings.Contains(err.Error(), "compression loop") {
+				t.Errorf("parseMessage = %v, want compression loop error", err)
+			}
+		})
+	}
+}
+
+func TestParseMessageBadPointer(t *testing.T) {
+	// A zone name pointing beyond the end of the message
+	data := []byte{0x12, 0x34, 0x28, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xc0, 0xff, 0x00, 0x06, 0x00, 0x01}
+
+	if _, err := parseMessage(data); !errors.Is(err, errTruncated) {
+		t.Errorf("parseMessage = %v, want %v", err, errTruncated)
+	}
+}
+
+func TestParseMessageCompressedRData(t *testing.T) {
+	// UPDATE adding "www.example.com. 300 IN CNAME example.com." with the target
+	// compressed to a pointer to the zone name
+	data := []byte{
+		0x12, 0x34, 0x28, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00,
+		0x07, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0x03, 'c', 'o', 'm', 0x00, 0x00, 0x06, 0x00, 0x01,
+		0x03, 'w', 'w', 'w', 0xc0, 0x0c, 0x00, 0x05, 0x00, 0x01, 0x00, 0x00, 0x01, 0x2c, 0x00, 0x02, 0xc0, 0x0c,
+	}
+
+	m, err := parseMessage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := string(appendName(nil, "example.com"))
+	if got := string(m.authority[0].RData); got != want {
+		t.Errorf("CNAME RDATA %q, want the expanded name %q", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+package rfc2136
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+// Record types that can be translated to INWX records
+const (
+	typeA     = 1
+	typeNS    = 2
+	typeCNAME = 5
+	typePTR   = 12
+	typeMX    = 15
+	typeTXT   = 16
+	typeAAAA  = 28
+	typeSRV   = 33
+	typeCAA   = 257
+)
+
+var typeNames = map[uint16]string{
+	typeA:     "A",
+	typeNS:    "NS",
+	typeCNAME: "CNAME",
+	typeSOA:   "SOA",
+	typePTR:   "PTR",
+	typeMX:    "MX",
+	typeTXT:   "TXT",
+	typeAAAA:  "AAAA",
+	typeSRV:   "SRV",
+	typeCAA:   "CAA",
+	typeANY:   "ANY",
+}
+
+// typeName returns the mnemonic of a record type, or TYPE<n> for unknown types
+func typeName(t uint16) string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return "TYPE" + strconv.Itoa(int(t))
+}
+
+// isSupported reports whether records of the type can be created through the gateway
+func isSupported(t uint16) bool {
+	switch t {
+	case typeA, typeNS, typeCNAME, typePTR, typeMX, typeTXT, typeAAAA, typeSRV, typeCAA:
+		return true
+	}
+	return false
+}
+
+// rdataToContent converts wire format RDATA to INWX record content and priority
+func rdataToContent(t uint16, rdata []byte) (string, int, error) {
+	switch t {
+	case typeA:
+		if len(rdata) != net.IPv4len {
+			return "", 0, fmt.Errorf("invalid A record length %d", len(rdata))
+		}
+		return net.IP(rdata).String(), 0, nil
+	case typeAAAA:
+		if len(rdata) != net.IPv6len {
+			return "", 0, fmt.Errorf("invalid AAAA record length %d", len(rdata))
+		}
+		return net.IP(rdata).String(), 0, nil
+	case typeNS, typeCNAME, typePTR:
+		name, next, err := readName(rdata, 0)
+		if err != nil || next != len(rdata) {
+			return "", 0, fmt.Errorf("invalid %s target", typeName(t))
+		}
+		return name, 0, nil
+	case typeMX:
+		if len(rdata) < 3 {
+			return "", 0, errTruncated
+		}
+		name, next, err := readName(rdata, 2)
+		if err != nil || next != len(rdata) {
+			return "", 0, fmt.Errorf("invalid MX exchange")
+		}
+		return name, int(binary.BigEndian.Uint16(rdata)), nil
+	case typeSRV:
+		if len(rdata) < 7 {
+			return "", 0, errTruncated
+		}
+		name, next, err := readName(rdata, 6)
+		if err != nil || next != len(rdata) {
+			return "", 0, fmt.Errorf("invalid SRV target")
+		}
+		weight := binary.BigEndian.Uint16(rdata[2:])
+		port := binary.BigEndian.Uint16(rdata[4:])
+		return fmt.Sprintf("%d %d %s", weight, port, name), int(binary.BigEndian.Uint16(rdata)), nil
+	case typeTXT:
+		// Character strings are joined, as INWX splits long values itself
+		var value strings.Builder
+		for off := 0; off < len(rdata); {
+			length := int(rdata[off])
+			if off+1+length > len(rdata) {
+				return "", 0, errTruncated
+			}
+			value.Write(rdata[off+1 : off+1+length])
+			off += 1 + length
+		}
+		return value.String(), 0, nil
+	case typeCAA:
+		if len(rdata) < 2 || 2+int(rdata[1]) > len(rdata) {
+			return "", 0, errTruncated
+		}
+		tag := string(rdata[2 : 2+int(rdata[1])])
+		value := string(rdata[2+int(rdata[1]):])
+		return fmt.Sprintf("%d %s %q", rdata[0], strings.ToLower(tag), value), 0, nil
+	default:
+		return "", 0, fmt.Errorf("unsupported record type %s", typeName(t))
+	}
+}
+
+// recordKey identifies the data of a record independent of its name and TTL, so that
+// records from the API and from UPDATE messages can be compared
+func recordKey(recordType, content string, prio int) string {
+	recordType = strings.ToUpper(recordType)
+	content = strings.TrimSpace(content)
+
+	switch recordType {
+	case "A", "AAAA":
+		if ip := net.ParseIP(content); ip != nil {
+			content = ip.String()
+		}
+	case "NS", "CNAME", "PTR":
+		content = normalizeName(content)
+	case "MX":
+		content = strconv.Itoa(prio) + " " + normalizeName(content)
+	case "SRV":
+		fields := strings.Fields(content)
+		if len(fields) > 0 {
+			fields[len(fields)-1] = normalizeName(fields[len(fields)-1])
+		}
+		content = strconv.Itoa(prio) + " " + strings.Join(fields, " ")
+	case "TXT":
+		content = unquoteTXT(content)
+	case "CAA":
+		fields := strings.Fields(content)
+		if len(fields) >= 3 {
+			value := strings.Trim(strings.Join(fields[2:], " "), `"`)
+			content = fields[0] + " " + strings.ToLower(fields[1]) + " " + value
+		}
+	}
+
+	return recordType + " " + content
+}
+
+// unquoteTXT returns the value of TXT content, which the API may return quoted and
+// split into several character strings
+func unquoteTXT(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, `"`) || !strings.HasSuffix(content, `"`) || len(content) < 2 {
+		return content
+	}
+	return strings.ReplaceAll(content[1:len(content)-1], `" "`, "")
+}
+
+// recordFQDN returns the fully qualified name of an INWX record
+func recordFQDN(record inwx.DNSRecord) string {
+	if record.Name == "" || record.Name == "@" {
+		return normalizeName(record.Domain)
+	}
+	return normalizeName(record.Name + "." + record.Domain)
+}
+
+// relativeName returns the name of a record relative to its zone as used by the API
+func relativeName(zone, name string) string {
+	if name == zone {
+		return "@"
+	}
+	return strings.TrimSuffix(name, "."+zone)
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
+
+func inZone(name, zone string) bool {
+	return name == zone || strings.HasSuffix(name, "."+zone)
+}
@@ -0,0 +1,521 @@
+// Package rfc2136 implements a DNS UPDATE (RFC 2136) gateway that verifies TSIG signed
+// updates and applies them to INWX hosted zones through the DNS service.
+package rfc2136
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+const (
+	// minTTL is the lowest TTL accepted by the INWX nameservers
+	minTTL = 300
+	// zoneCacheTTL is how long the list of zones is cached
+	zoneCacheTTL = 5 * time.Minute
+	// tcpTimeout limits how long a TCP connection may stay idle
+	tcpTimeout = 30 * time.Second
+)
+
+// ZoneLister returns the zones available in the account
+type ZoneLister func(ctx context.Context) ([]string, error)
+
+// Server accepts DNS UPDATE messages over UDP and TCP
+type Server struct {
+	dns     *inwx.DNSService
+	zones   ZoneLister
+	keys    map[string]*Key
+	allowed []string
+	dryRun  bool
+
+	// mu serialises updates, so prerequisites are checked against the state they modify
+	mu sync.Mutex
+
+	zoneMu      sync.Mutex
+	cachedZones []string
+	cachedAt    time.Time
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithKeys sets the TSIG keys accepted for updates
+func WithKeys(keys ...Key) Option {
+	return func(s *Server) {
+		for i := range keys {
+			s.keys[keys[i].Name] = &keys[i]
+		}
+	}
+}
+
+// WithZones restricts updates to the given zones
+func WithZones(zones ...string) Option {
+	return func(s *Server) {
+		for _, zone := range zones {
+			s.allowed = append(s.allowed, normalizeName(zone))
+		}
+	}
+}
+
+// WithDryRun logs changes instead of applying them
+func WithDryRun(dryRun bool) Option {
+	return func(s *Server) {
+		s.dryRun = dryRun
+	}
+}
+
+// NewServer creates an update gateway for the given DNS service
+func NewServer(dns *inwx.DNSService, zones ZoneLister, opts ...Option) *Server {
+	s := &Server{
+		dns:   dns,
+		zones: zones,
+		keys:  make(map[string]*Key),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// ListenAndServe serves UDP and TCP on addr until ctx is cancelled
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	if len(s.keys) == 0 {
+		return errors.New("at least one TSIG key is required")
+	}
+
+	packetConn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		packetConn.Close()
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		packetConn.Close()
+		listener.Close()
+	}()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		errs <- s.serveUDP(ctx, packetConn)
+	}()
+	go func() {
+		defer wg.Done()
+		errs <- s.serveTCP(ctx, listener)
+	}()
+
+	err = <-errs
+	packetConn.Close()
+	listener.Close()
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) serveUDP(ctx context.Context, conn net.PacketConn) error {
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("udp: %w", err)
+		}
+
+		request := append([]byte(nil), buf[:n]...)
+		go func() {
+			if response := s.Handle(ctx, request, addr); response != nil {
+				if _, err := conn.WriteTo(response, addr); err != nil {
+					log.Warn().Err(err).Str("client", addr.String()).Msg("Failed to send response")
+				}
+			}
+		}()
+	}
+}
+
+func (s *Server) serveTCP(ctx context.Context, listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("tcp: %w", err)
+		}
+		go s.serveConn(ctx, conn)
+	}
+}
+
+// serveConn handles the length prefixed messages of a TCP connection
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		if err := conn.SetDeadline(time.Now().Add(tcpTimeout)); err != nil {
+			return
+		}
+
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return
+		}
+		request := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, request); err != nil {
+			return
+		}
+
+		response := s.Handle(ctx, request, conn.RemoteAddr())
+		if response == nil {
+			continue
+		}
+		if _, err := conn.Write(appendUint16(nil, uint16(len(response)))); err != nil {
+			return
+		}
+		if _, err := conn.Write(response); err != nil {
+			return
+		}
+	}
+}
+
+// Handle processes a DNS message and returns the response, or nil if no response
+// should be sent
+func (s *Server) Handle(ctx context.Context, data []byte, client net.Addr) []byte {
+	logger := log.With().Str("client", client.String()).Logger()
+
+	m, err := parseMessage(data)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Malformed message")
+		return packFormErr(data)
+	}
+	if m.header.Flags&flagQR != 0 {
+		return nil
+	}
+
+	now := time.Now()
+	sig, err := verifyTSIG(data, m, s.keys, now)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Malformed TSIG record")
+		return packResponse(m, RcodeFormErr)
+	}
+	if sig == nil {
+		logger.Warn().Msg("Refused unsigned message")
+		return packResponse(m, RcodeRefused)
+	}
+	if sig.err != 0 {
+		logger.Warn().Str("key", sig.keyName).Str("error", tsigErrorName(sig.err)).Msg("TSIG verification failed")
+		return sig.sign(packResponse(m, RcodeNotAuth), now)
+	}
+
+	rcode := RcodeNotImp
+	if m.header.opcode() == opcodeUpdate {
+		rcode = s.update(ctx, m, logger.With().Str("key", sig.keyName).Logger())
+	}
+
+	return sig.sign(packResponse(m, rcode), time.Now())
+}
+
+// update processes an UPDATE message as described in RFC 2136 section 3
+func (s *Server) update(ctx context.Context, m *message, logger zerolog.Logger) int {
+	if len(m.question) != 1 || m.question[0].Type != typeSOA {
+		return RcodeFormErr
+	}
+	zone := m.question[0].Name
+	prerequisites, updates := m.answer, m.authority
+
+	allowed, err := s.zoneAllowed(ctx, zone)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to list zones")
+		return RcodeServFail
+	}
+	if !allowed {
+		logger.Warn().Str("zone", zone).Msg("Refused update for unmanaged zone")
+		return RcodeNotAuth
+	}
+
+	if rcode := prescanPrerequisites(zone, prerequisites); rcode != RcodeSuccess {
+		return rcode
+	}
+	if rcode := prescanUpdates(zone, updates); rcode != RcodeSuccess {
+		return rcode
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.dns.ListRecords(ctx, inwx.WithDomainFilter(zone))
+	if err != nil {
+		logger.Error().Err(err).Str("zone", zone).Msg("Failed to list records")
+		return RcodeServFail
+	}
+
+	if rcode := checkPrerequisites(records, prerequisites); rcode != RcodeSuccess {
+		logger.Info().Str("zone", zone).Str("rcode", rcodeName(rcode)).Msg("Prerequisites not satisfied")
+		return rcode
+	}
+
+	// All updates are planned and validated before the first change is made
+	u := &updater{server: s, zone: zone, records: records, logger: logger}
+	for _, record := range updates {
+		if err := u.apply(record); err != nil {
+			logger.Warn().Err(err).Str("zone", zone).Msg("Rejected update")
+			return RcodeFormErr
+		}
+	}
+	if err := u.commit(ctx); err != nil {
+		logger.Error().Err(err).Str("zone", zone).Msg("Failed to apply update")
+		return RcodeServFail
+	}
+
+	return RcodeSuccess
+}
+
+// prescanPrerequisites checks the form of the prerequisite section (RFC 2136 3.2)
+func prescanPrerequisites(zone string, prerequisites []rr) int {
+	for _, record := range prerequisites {
+		if record.TTL != 0 {
+			return RcodeFormErr
+		}
+		if !inZone(record.Name, zone) {
+			return RcodeNotZone
+		}
+		switch record.Class {
+		case classANY, classNONE:
+			if len(record.RData) != 0 {
+				return RcodeFormErr
+			}
+		case classIN:
+			if record.Type == typeANY {
+				return RcodeFormErr
+			}
+		default:
+			return RcodeFormErr
+		}
+	}
+	return RcodeSuccess
+}
+
+// prescanUpdates checks the form of the update section (RFC 2136 3.4.1)
+func prescanUpdates(zone string, updates []rr) int {
+	for _, record := range updates {
+		if !inZone(record.Name, zone) {
+			return RcodeNotZone
+		}
+		switch record.Class {
+		case classIN:
+			if record.Type == typeANY || record.Type == typeTSIG {
+				return RcodeFormErr
+			}
+			if record.Type != typeSOA && !isSupported(record.Type) {
+				return RcodeNotImp
+			}
+			if record.Type != typeSOA {
+				if _, _, err := rdataToContent(record.Type, record.RData); err != nil {
+					return RcodeFormErr
+				}
+			}
+		case classANY:
+			if record.TTL != 0 || len(record.RData) != 0 {
+				return RcodeFormErr
+			}
+		case classNONE:
+			if record.TTL != 0 || record.Type == typeANY {
+				return RcodeFormErr
+			}
+			if record.Type != typeSOA && isSupported(record.Type) {
+				if _, _, err := rdataToContent(record.Type, record.RData); err != nil {
+					return RcodeFormErr
+				}
+			}
+		default:
+			return RcodeFormErr
+		}
+	}
+	return RcodeSuccess
+}
+
+// checkPrerequisites evaluates the prerequisite section against the zone records
+// (RFC 2136 3.2.5)
+func checkPrerequisites(records []inwx.DNSRecord, prerequisites []rr) int {
+	// Value dependent prerequisites are collected per RRset and compared as a whole
+	expected := make(map[string]map[string]bool)
+
+	for _, prerequisite := range prerequisites {
+		name := prerequisite.Name
+		switch prerequisite.Class {
+		case classANY:
+			if prerequisite.Type == typeANY {
+				if len(recordsAt(records, name, "")) == 0 {
+					return RcodeNXDomain
+				}
+			} else if len(recordsAt(records, name, typeName(prerequisite.Type))) == 0 {
+				return RcodeNXRRSet
+			}
+		case classNONE:
+			if prerequisite.Type == typeANY {
+				if len(recordsAt(records, name, "")) > 0 {
+					return RcodeYXDomain
+				}
+			} else if len(recordsAt(records, name, typeName(prerequisite.Type))) > 0 {
+				return RcodeYXRRSet
+			}
+		case classIN:
+			content, prio, err := rdataToContent(prerequisite.Type, prerequisite.RData)
+			if err != nil {
+				return RcodeNXRRSet
+			}
+			rrset := name + " " + typeName(prerequisite.Type)
+			if expected[rrset] == nil {
+				expected[rrset] = make(map[string]bool)
+			}
+			expected[rrset][recordKey(typeName(prerequisite.Type), content, prio)] = true
+		}
+	}
+
+	for rrset, keys := range expected {
+		name, recordType, _ := strings.Cut(rrset, " ")
+		actual := make(map[string]bool)
+		for _, record := range recordsAt(records, name, recordType) {
+			actual[recordKey(record.Type, record.Content, record.Prio)] = true
+		}
+		if len(actual) != len(keys) {
+			return RcodeNXRRSet
+		}
+		for key := range keys {
+			if !actual[key] {
+				return RcodeNXRRSet
+			}
+		}
+	}
+
+	return RcodeSuccess
+}
+
+// recordsAt returns the records with the given name and, if set, type
+func recordsAt(records []inwx.DNSRecord, name, recordType string) []inwx.DNSRecord {
+	var matching []inwx.DNSRecord
+	for _, record := range records {
+		if recordFQDN(record) != name {
+			continue
+		}
+		if recordType != "" && !strings.EqualFold(record.Type, recordType) {
+			continue
+		}
+		matching = append(matching, record)
+	}
+	return matching
+}
+
+// zoneAllowed reports whether zone is an account zone that may be updated
+func (s *Server) zoneAllowed(ctx context.Context, zone string) (bool, error) {
+	if len(s.allowed) > 0 && !containsName(s.allowed, zone) {
+		return false, nil
+	}
+
+	zones, err := s.managedZones(ctx)
+	if err != nil {
+		return false, err
+	}
+	return containsName(zones, zone), nil
+}
+
+// managedZones returns the zones of the account
+func (s *Server) managedZones(ctx context.Context) ([]string, error) {
+	s.zoneMu.Lock()
+	defer s.zoneMu.Unlock()
+
+	if s.cachedZones != nil && time.Since(s.cachedAt) < zoneCacheTTL {
+		return s.cachedZones, nil
+	}
+
+	all, err := s.zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := make([]string, 0, len(all))
+	for _, zone := range all {
+		zones = append(zones, normalizeName(zone))
+	}
+	sort.Strings(zones)
+
+	s.cachedZones = zones
+	s.cachedAt = time.Now()
+	return zones, nil
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func rcodeName(rcode int) string {
+	switch rcode {
+	case RcodeSuccess:
+		return "NOERROR"
+	case RcodeFormErr:
+		return "FORMERR"
+	case RcodeServFail:
+		return "SERVFAIL"
+	case RcodeNXDomain:
+		return "NXDOMAIN"
+	case RcodeNotImp:
+		return "NOTIMP"
+	case RcodeRefused:
+		return "REFUSED"
+	case RcodeYXDomain:
+		return "YXDOMAIN"
+	case RcodeYXRRSet:
+		return "YXRRSET"
+	case RcodeNXRRSet:
+		return "NXRRSET"
+	case RcodeNotAuth:
+		return "NOTAUTH"
+	case RcodeNotZone:
+		return "NOTZONE"
+	default:
+		return fmt.Sprintf("RCODE%d", rcode)
+	}
+}
+
+func tsigErrorName(code uint16) string {
+	switch code {
+	case RcodeBadSig:
+		return "BADSIG"
+	case RcodeBadKey:
+		return "BADKEY"
+	case RcodeBadTime:
+		return "BADTIME"
+	default:
+		return rcodeName(int(code))
+	}
+}
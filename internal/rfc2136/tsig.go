@@ -0,0 +1,253 @@
+package rfc2136
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+)
+
+// tsigFudge is the permitted clock skew in seconds
+const tsigFudge = 300
+
+// tsigAlgorithms maps the TSIG algorithm names to their hash functions
+var tsigAlgorithms = map[string]func() hash.Hash{
+	"hmac-md5.sig-alg.reg.int": md5.New,
+	"hmac-sha1":                sha1.New,
+	"hmac-sha224":              sha256.New224,
+	"hmac-sha256":              sha256.New,
+	"hmac-sha384":              sha512.New384,
+	"hmac-sha512":              sha512.New,
+}
+
+// Key is a TSIG key shared with the clients
+type Key struct {
+	Name      string
+	Algorithm string
+	Secret    []byte
+}
+
+// ParseKey parses a key in the form [algorithm:]name:secret as used by nsupdate -y.
+// The secret is base64 encoded and the algorithm defaults to hmac-sha256.
+func ParseKey(spec string) (Key, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+
+	var key Key
+	var secret string
+	switch len(parts) {
+	case 2:
+		key.Algorithm, key.Name, secret = "hmac-sha256", parts[0], parts[1]
+	case 3:
+		key.Algorithm, key.Name, secret = parts[0], parts[1], parts[2]
+	default:
+		return Key{}, fmt.Errorf("invalid TSIG key %q: expected [algorithm:]name:secret", spec)
+	}
+
+	key.Name = normalizeName(key.Name)
+	if key.Name == "" {
+		return Key{}, fmt.Errorf("TSIG key name must not be empty")
+	}
+
+	key.Algorithm = normalizeName(key.Algorithm)
+	if key.Algorithm == "hmac-md5" {
+		key.Algorithm = "hmac-md5.sig-alg.reg.int"
+	}
+	if _, ok := tsigAlgorithms[key.Algorithm]; !ok {
+		return Key{}, fmt.Errorf("unsupported TSIG algorithm %s", key.Algorithm)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return Key{}, fmt.Errorf("invalid secret of TSIG key %s: %w", key.Name, err)
+	}
+	if len(decoded) == 0 {
+		return Key{}, fmt.Errorf("secret of TSIG key %s must not be empty", key.Name)
+	}
+	key.Secret = decoded
+
+	return key, nil
+}
+
+// tsig is the RDATA of a TSIG record
+type tsig struct {
+	Algorithm  string
+	TimeSigned uint64
+	Fudge      uint16
+	MAC        []byte
+	OriginalID uint16
+	Error      uint16
+	OtherData  []byte
+}
+
+func parseTSIG(rdata []byte) (*tsig, error) {
+	algorithm, off, err := readName(rdata, 0)
+	if err != nil {
+		return nil, err
+	}
+	if off+10 > len(rdata) {
+		return nil, errTruncated
+	}
+
+	t := &tsig{Algorithm: algorithm}
+	t.TimeSigned = uint64(binary.BigEndian.Uint16(rdata[off:]))<<32 | uint64(binary.BigEndian.Uint32(rdata[off+2:]))
+	t.Fudge = binary.BigEndian.Uint16(rdata[off+6:])
+	macSize := int(binary.BigEndian.Uint16(rdata[off+8:]))
+	off += 10
+
+	if off+macSize+6 > len(rdata) {
+		return nil, errTruncated
+	}
+	t.MAC = rdata[off : off+macSize]
+	off += macSize
+
+	t.OriginalID = binary.BigEndian.Uint16(rdata[off:])
+	t.Error = binary.BigEndian.Uint16(rdata[off+2:])
+	otherLen := int(binary.BigEndian.Uint16(rdata[off+4:]))
+	off += 6
+
+	if off+otherLen != len(rdata) {
+		return nil, errTruncated
+	}
+	t.OtherData = rdata[off:]
+
+	return t, nil
+}
+
+func (t *tsig) pack() []byte {
+	b := appendName(nil, t.Algorithm)
+	b = appendUint48(b, t.TimeSigned)
+	b = appendUint16(b, t.Fudge)
+	b = appendUint16(b, uint16(len(t.MAC)))
+	b = append(b, t.MAC...)
+	b = appendUint16(b, t.OriginalID)
+	b = appendUint16(b, t.Error)
+	b = appendUint16(b, uint16(len(t.OtherData)))
+	return append(b, t.OtherData...)
+}
+
+// variables returns the TSIG variables covered by the MAC
+func (t *tsig) variables(keyName string) []byte {
+	b := appendName(nil, keyName)
+	b = appendUint16(b, classANY)
+	b = appendUint32(b, 0)
+	b = appendName(b, t.Algorithm)
+	b = appendUint48(b, t.TimeSigned)
+	b = appendUint16(b, t.Fudge)
+	b = appendUint16(b, t.Error)
+	b = appendUint16(b, uint16(len(t.OtherData)))
+	return append(b, t.OtherData...)
+}
+
+// signature is the outcome of the TSIG verification of a request, needed to sign the
+// response
+type signature struct {
+	key     *Key
+	keyName string
+	request *tsig
+	// err is the TSIG error to report, the response is only signed for BADTIME
+	err uint16
+}
+
+// verifyTSIG checks the TSIG record of a request. It returns nil if the request is not
+// signed and an error if the TSIG record is malformed.
+func verifyTSIG(data []byte, m *message, keys map[string]*Key, now time.Time) (*signature, error) {
+	index := -1
+	for i, record := range m.additional {
+		if record.Type == typeTSIG {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, nil
+	}
+	if index != len(m.additional)-1 {
+		return nil, fmt.Errorf("TSIG record is not the last record")
+	}
+
+	record := m.additional[index]
+	request, err := parseTSIG(record.RData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TSIG record: %w", err)
+	}
+	sig := &signature{keyName: record.Name, request: request}
+
+	key, ok := keys[record.Name]
+	if !ok || key.Algorithm != request.Algorithm {
+		sig.err = RcodeBadKey
+		return sig, nil
+	}
+	sig.key = key
+
+	newHash := tsigAlgorithms[key.Algorithm]
+	size := newHash().Size()
+	if len(request.MAC) > size || len(request.MAC) < size/2 || len(request.MAC) < 10 {
+		sig.err = RcodeBadSig
+		return sig, nil
+	}
+
+	// The MAC covers the message without the TSIG record and with the original ID
+	unsigned := append([]byte(nil), data[:record.offset]...)
+	binary.BigEndian.PutUint16(unsigned[0:], request.OriginalID)
+	binary.BigEndian.PutUint16(unsigned[10:], uint16(len(m.additional)-1))
+
+	mac := hmac.New(newHash, key.Secret)
+	mac.Write(unsigned)
+	mac.Write(request.variables(record.Name))
+	if !hmac.Equal(mac.Sum(nil)[:len(request.MAC)], request.MAC) {
+		sig.err = RcodeBadSig
+		return sig, nil
+	}
+
+	signed := int64(request.TimeSigned)
+	if diff := now.Unix() - signed; diff > int64(request.Fudge) || -diff > int64(request.Fudge) {
+		sig.err = RcodeBadTime
+	}
+
+	return sig, nil
+}
+
+// sign appends the TSIG record to a response. Responses to requests with an unknown
+// key or a bad signature carry the error without a MAC.
+func (s *signature) sign(response []byte, now time.Time) []byte {
+	t := &tsig{
+		Algorithm:  s.request.Algorithm,
+		TimeSigned: uint64(now.Unix()),
+		Fudge:      tsigFudge,
+		OriginalID: binary.BigEndian.Uint16(response),
+		Error:      s.err,
+	}
+
+	if s.err == RcodeBadTime {
+		// Report the server time so the client can see the skew
+		t.TimeSigned = s.request.TimeSigned
+		t.OtherData = appendUint48(nil, uint64(now.Unix()))
+	}
+
+	if s.key != nil && s.err != RcodeBadSig {
+		mac := hmac.New(tsigAlgorithms[s.key.Algorithm], s.key.Secret)
+		mac.Write(appendUint16(nil, uint16(len(s.request.MAC))))
+		mac.Write(s.request.MAC)
+		mac.Write(response)
+		mac.Write(t.variables(s.keyName))
+		t.MAC = mac.Sum(nil)
+	} else {
+		t.TimeSigned = s.request.TimeSigned
+	}
+
+	arCount := binary.BigEndian.Uint16(response[10:])
+	binary.BigEndian.PutUint16(response[10:], arCount+1)
+
+	return appendRR(response, rr{
+		Name:  s.keyName,
+		Type:  typeTSIG,
+		Class: classANY,
+		RData: t.pack(),
+	})
+}
@@ -0,0 +1,229 @@
+package rfc2136
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+// signedUpdate is an UPDATE for example.com adding "www.example.com. 300 IN A 192.0.2.1",
+// with compressed names, signed by github.com/miekg/dns with the hmac-sha256 key
+// update-key at 1700000000 (2023-11-14T22:13:20Z) with a fudge of 300 seconds
+const signedUpdate = "123428000001000000010001076578616d706c6503636f6d0000060001" +
+	"03777777c00c000100010000012c0004c0000201" +
+	"0a7570646174652d6b65790000fa00ff00000000003d" +
+	"0b686d61632d7368613235360000006553f100012c0020" +
+	"a44d8355c11fcf107d536d21e5e0dab75add0ee5e8ab1a3dc0d381799cbf0fb6" +
+	"123400000000"
+
+const signedUpdateKey = "hmac-sha256:update-key:c2VjcmV0LWtleS1mb3ItdGVzdGluZy0xMjM0NTY3OA=="
+
+var signedUpdateTime = time.Unix(1700000000, 0)
+
+func testKeys(t *testing.T, specs ...string) map[string]*Key {
+	t.Helper()
+
+	keys := make(map[string]*Key)
+	for _, spec := range specs {
+		key, err := ParseKey(spec)
+		if err != nil {
+			t.Fatalf("ParseKey(%q): %v", spec, err)
+		}
+		keys[key.Name] = &key
+	}
+	return keys
+}
+
+// verify parses a message and verifies its TSIG record
+func verify(t *testing.T, data []byte, keys map[string]*Key, now time.Time) *signature {
+	t.Helper()
+
+	m, err := parseMessage(data)
+	if err != nil {
+		t.Fatalf("parseMessage: %v", err)
+	}
+	sig, err := verifyTSIG(data, m, keys, now)
+	if err != nil {
+		t.Fatalf("verifyTSIG: %v", err)
+	}
+	if sig == nil {
+		t.Fatal("verifyTSIG returned no signature for a signed message")
+	}
+	return sig
+}
+
+func signedUpdateBytes(t *testing.T) []byte {
+	t.Helper()
+
+	data, err := hex.DecodeString(signedUpdate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestVerifyTSIG(t *testing.T) {
+	keys := testKeys(t, signedUpdateKey)
+
+	for _, skew := range []time.Duration{0, 299 * time.Second, -299 * time.Second} {
+		sig := verify(t, signedUpdateBytes(t), keys, signedUpdateTime.Add(skew))
+		if sig.err != RcodeSuccess {
+			t.Errorf("skew %v: TSIG error %d, want none", skew, sig.err)
+		}
+		if sig.key == nil || sig.keyName != "update-key" {
+			t.Errorf("skew %v: key %v (%s), want update-key", skew, sig.key, sig.keyName)
+		}
+	}
+}
+
+func TestVerifyTSIGUnsigned(t *testing.T) {
+	data := signedUpdateBytes(t)
+
+	// Cut off the TSIG record and fix the additional count
+	m, err := parseMessage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := append([]byte(nil), data[:m.additional[0].offset]...)
+	unsigned[11] = 0
+
+	m, err = parseMessage(unsigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := verifyTSIG(unsigned, m, testKeys(t, signedUpdateKey), signedUpdateTime)
+	if err != nil || sig != nil {
+		t.Errorf("verifyTSIG = %v, %v, want no signature", sig, err)
+	}
+}
+
+func TestVerifyTSIGTampered(t *testing.T) {
+	keys := testKeys(t, signedUpdateKey)
+
+	tests := []struct {
+		name   string
+		tamper func([]byte)
+	}{
+		// The MAC is followed by the original ID, the error and the other length
+		{"mac", func(data []byte) { data[len(data)-7] ^= 0x01 }},
+		// The last byte of the A record, 192.0.2.1
+		{"rdata", func(data []byte) { data[48] = 2 }},
+		{"id", func(data []byte) { data[len(data)-6] ^= 0xff }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := signedUpdateBytes(t)
+			test.tamper(data)
+
+			sig := verify(t, data, keys, signedUpdateTime)
+			if sig.err != RcodeBadSig {
+				t.Errorf("TSIG error %d, want BADSIG (%d)", sig.err, RcodeBadSig)
+			}
+		})
+	}
+}
+
+func TestVerifyTSIGBadTime(t *testing.T) {
+	keys := testKeys(t, signedUpdateKey)
+
+	for _, skew := range []time.Duration{301 * time.Second, -301 * time.Second, 24 * time.Hour} {
+		sig := verify(t, signedUpdateBytes(t), keys, signedUpdateTime.Add(skew))
+		if sig.err != RcodeBadTime {
+			t.Errorf("skew %v: TSIG error %d, want BADTIME (%d)", skew, sig.err, RcodeBadTime)
+		}
+	}
+}
+
+func TestVerifyTSIGBadKey(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+	}{
+		{"unknown name", []string{"hmac-sha256:other-key:c2VjcmV0LWtleS1mb3ItdGVzdGluZy0xMjM0NTY3OA=="}},
+		{"other algorithm", []string{"hmac-sha512:update-key:c2VjcmV0LWtleS1mb3ItdGVzdGluZy0xMjM0NTY3OA=="}},
+		{"no keys", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sig := verify(t, signedUpdateBytes(t), testKeys(t, test.keys...), signedUpdateTime)
+			if sig.err != RcodeBadKey {
+				t.Errorf("TSIG error %d, want BADKEY (%d)", sig.err, RcodeBadKey)
+			}
+			if sig.key != nil {
+				t.Errorf("key %v, want none", sig.key)
+			}
+		})
+	}
+}
+
+func TestVerifyTSIGWrongSecret(t *testing.T) {
+	keys := testKeys(t, "hmac-sha256:update-key:b3RoZXItc2VjcmV0")
+
+	sig := verify(t, signedUpdateBytes(t), keys, signedUpdateTime)
+	if sig.err != RcodeBadSig {
+		t.Errorf("TSIG error %d, want BADSIG (%d)", sig.err, RcodeBadSig)
+	}
+}
+
+func TestSignResponse(t *testing.T) {
+	keys := testKeys(t, signedUpdateKey)
+	data := signedUpdateBytes(t)
+
+	m, err := parseMessage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := verify(t, data, keys, signedUpdateTime)
+
+	response := sig.sign(packResponse(m, RcodeSuccess), signedUpdateTime)
+	signed, err := parseMessage(response)
+	if err != nil {
+		t.Fatalf("parseMessage(response): %v", err)
+	}
+	if len(signed.additional) != 1 || signed.additional[0].Type != typeTSIG {
+		t.Fatalf("response additional section %+v, want one TSIG record", signed.additional)
+	}
+	record, err := parseTSIG(signed.additional[0].RData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Error != RcodeSuccess || len(record.MAC) != 32 || record.OriginalID != 0x1234 {
+		t.Errorf("response TSIG %+v, want a MAC without error for ID 0x1234", record)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		spec      string
+		name      string
+		algorithm string
+		wantErr   bool
+	}{
+		{"update-key:c2VjcmV0", "update-key", "hmac-sha256", false},
+		{"hmac-md5:Update-Key.:c2VjcmV0", "update-key", "hmac-md5.sig-alg.reg.int", false},
+		{"hmac-sha512:key:c2VjcmV0", "key", "hmac-sha512", false},
+		{"hmac-foo:key:c2VjcmV0", "", "", true},
+		{"key:not base64!", "", "", true},
+		{"key:", "", "", true},
+		{"c2VjcmV0", "", "", true},
+	}
+
+	for _, test := range tests {
+		key, err := ParseKey(test.spec)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("ParseKey(%q) succeeded, want error", test.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseKey(%q): %v", test.spec, err)
+			continue
+		}
+		if key.Name != test.name || key.Algorithm != test.algorithm {
+			t.Errorf("ParseKey(%q) = %s %s, want %s %s", test.spec, key.Algorithm, key.Name, test.algorithm, test.name)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package rfc2136
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+// Kinds of planned changes
+const (
+	opCreate = iota
+	opUpdate
+	opDelete
+)
+
+// operation is a change planned by the updater. previous holds the state of updated and
+// deleted records, used to roll the change back.
+type operation struct {
+	kind     int
+	record   inwx.DNSRecord
+	previous inwx.DNSRecord
+}
+
+// updater plans the changes of the update section of a message. The records of the zone
+// are kept in sync with the planned changes, so later updates in the same message see
+// them. Nothing is changed until commit, which applies the plan as a whole.
+type updater struct {
+	server  *Server
+	zone    string
+	records []inwx.DNSRecord
+	logger  zerolog.Logger
+
+	ops []operation
+	// nextID numbers records planned for creation, negative to tell them from INWX IDs
+	nextID int
+}
+
+// apply plans a single update (RFC 2136 3.4.2)
+func (u *updater) apply(update rr) error {
+	name := update.Name
+	recordType := typeName(update.Type)
+
+	switch update.Class {
+	case classIN:
+		if update.Type == typeSOA {
+			// The SOA is maintained by INWX
+			u.logger.Info().Str("name", name).Msg("Ignored SOA update")
+			return nil
+		}
+		content, prio, err := rdataToContent(update.Type, update.RData)
+		if err != nil {
+			return err
+		}
+		return u.add(name, recordType, content, prio, int(update.TTL))
+
+	case classANY:
+		for _, record := range recordsAt(u.records, name, anyType(update.Type)) {
+			if !u.protected(record) {
+				u.delete(record)
+			}
+		}
+		return nil
+
+	case classNONE:
+		if !isSupported(update.Type) {
+			return nil
+		}
+		content, prio, err := rdataToContent(update.Type, update.RData)
+		if err != nil {
+			return err
+		}
+		key := recordKey(recordType, content, prio)
+		for _, record := range recordsAt(u.records, name, recordType) {
+			if recordKey(record.Type, record.Content, record.Prio) == key && !u.protected(record) {
+				u.delete(record)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unexpected class %d", update.Class)
+}
+
+// add plans the creation of a record unless it exists. CNAME records replace an
+// existing CNAME and are not mixed with other data at the same name.
+func (u *updater) add(name, recordType, content string, prio, ttl int) error {
+	if ttl < minTTL {
+		ttl = minTTL
+	}
+	// Content the API would reject fails the message before anything is changed
+	if _, err := inwx.ParseRecordData(recordType, content, prio); err != nil {
+		return fmt.Errorf("invalid %s record content for %s: %w", recordType, name, err)
+	}
+
+	existing := recordsAt(u.records, name, "")
+	for _, record := range existing {
+		isCNAME := strings.EqualFold(record.Type, "CNAME")
+		switch {
+		case recordType == "CNAME" && isCNAME:
+			u.modify(record, func(r *inwx.DNSRecord) {
+				r.Content = content
+				r.TTL = ttl
+			})
+			return nil
+		case recordType == "CNAME" || isCNAME:
+			u.logger.Info().Str("name", name).Str("type", recordType).Msg("Ignored update conflicting with CNAME")
+			return nil
+		}
+	}
+
+	key := recordKey(recordType, content, prio)
+	for _, record := range existing {
+		if strings.EqualFold(record.Type, recordType) && recordKey(record.Type, record.Content, record.Prio) == key {
+			u.modify(record, func(r *inwx.DNSRecord) { r.TTL = ttl })
+			return nil
+		}
+	}
+
+	u.nextID--
+	record := inwx.DNSRecord{
+		ID:      u.nextID,
+		Domain:  u.zone,
+		Name:    relativeName(u.zone, name),
+		Type:    recordType,
+		Content: content,
+		TTL:     ttl,
+		Prio:    prio,
+	}
+	u.records = append(u.records, record)
+	u.ops = append(u.ops, operation{kind: opCreate, record: record})
+	return nil
+}
+
+// modify plans a change of the content or TTL of a record
+func (u *updater) modify(record inwx.DNSRecord, change func(*inwx.DNSRecord)) {
+	updated := record
+	change(&updated)
+	if updated == record {
+		return
+	}
+
+	for i := range u.records {
+		if u.records[i].ID == record.ID {
+			u.records[i] = updated
+		}
+	}
+
+	// Records created or updated earlier in the message are changed in place
+	for i := range u.ops {
+		if u.ops[i].kind != opDelete && u.ops[i].record.ID == record.ID {
+			u.ops[i].record = updated
+			return
+		}
+	}
+	u.ops = append(u.ops, operation{kind: opUpdate, record: updated, previous: record})
+}
+
+// delete plans the deletion of a record
+func (u *updater) delete(record inwx.DNSRecord) {
+	for i := range u.records {
+		if u.records[i].ID == record.ID {
+			u.records = append(u.records[:i], u.records[i+1:]...)
+			break
+		}
+	}
+
+	for i := range u.ops {
+		if u.ops[i].kind == opDelete || u.ops[i].record.ID != record.ID {
+			continue
+		}
+		if u.ops[i].kind == opCreate {
+			// A record created earlier in the message is simply not created
+			u.ops = append(u.ops[:i], u.ops[i+1:]...)
+			return
+		}
+		u.ops[i] = operation{kind: opDelete, record: u.ops[i].previous, previous: u.ops[i].previous}
+		return
+	}
+	u.ops = append(u.ops, operation{kind: opDelete, record: record, previous: record})
+}
+
+// commit applies the planned changes. If a change fails, the changes made before it
+// are rolled back, so the update takes effect as a whole or not at all (RFC 2136 3.4.2).
+func (u *updater) commit(ctx context.Context) error {
+	for i, op := range u.ops {
+		if u.server.dryRun {
+			u.event(u.logger.Info(), op).Msg(dryRunMessages[op.kind])
+			continue
+		}
+
+		record, err := u.execute(ctx, op)
+		if err != nil {
+			u.rollback(ctx, u.ops[:i])
+			return fmt.Errorf("failed to %s %s %s: %w", opVerbs[op.kind], recordFQDN(op.record), op.record.Type, err)
+		}
+		u.ops[i].record = record
+		u.event(u.logger.Info(), op).Msg(doneMessages[op.kind])
+	}
+	return nil
+}
+
+// execute makes a planned change through the API and returns the resulting record
+func (u *updater) execute(ctx context.Context, op operation) (inwx.DNSRecord, error) {
+	dns := u.server.dns
+	switch op.kind {
+	case opCreate:
+		record := op.record
+		record.ID = 0
+		created, err := dns.CreateRecord(ctx, record)
+		if err != nil {
+			return op.record, err
+		}
+		return *created, nil
+	case opUpdate:
+		updated, err := dns.ReplaceRecord(ctx, op.record.ID, op.record)
+		if err != nil {
+			return op.record, err
+		}
+		return *updated, nil
+	default:
+		return op.record, dns.DeleteRecord(ctx, op.record.ID)
+	}
+}
+
+// rollback reverts applied changes in reverse order. Deleted records are recreated
+// with a new ID.
+func (u *updater) rollback(ctx context.Context, applied []operation) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		op := applied[i]
+		undo := operation{kind: opUpdate, record: op.previous}
+		switch op.kind {
+		case opCreate:
+			undo = operation{kind: opDelete, record: op.record}
+		case opDelete:
+			undo = operation{kind: opCreate, record: op.previous}
+		}
+
+		if _, err := u.execute(ctx, undo); err != nil {
+			u.event(u.logger.Error().Err(err), undo).Msg("Failed to roll back change")
+			continue
+		}
+		u.event(u.logger.Info(), undo).Msg("Rolled back change")
+	}
+}
+
+// event adds the record of a change to a log event
+func (u *updater) event(event *zerolog.Event, op operation) *zerolog.Event {
+	return event.
+		Str("name", recordFQDN(op.record)).
+		Str("type", op.record.Type).
+		Str("content", op.record.Content).
+		Int("ttl", op.record.TTL)
+}
+
+var (
+	opVerbs        = map[int]string{opCreate: "create", opUpdate: "update", opDelete: "delete"}
+	doneMessages   = map[int]string{opCreate: "Created record", opUpdate: "Updated record", opDelete: "Deleted record"}
+	dryRunMessages = map[int]string{opCreate: "Would create record", opUpdate: "Would update record", opDelete: "Would delete record"}
+)
+
+// protected reports whether a record must not be deleted through the gateway: the SOA
+// and the NS records at the apex are maintained with the domain
+func (u *updater) protected(record inwx.DNSRecord) bool {
+	switch strings.ToUpper(record.Type) {
+	case "SOA":
+		return true
+	case "NS":
+		return recordFQDN(record) == u.zone
+	}
+	return false
+}
+
+// anyType returns the type filter for recordsAt, matching every type for ANY
+func anyType(t uint16) string {
+	if t == typeANY {
+		return ""
+	}
+	return typeName(t)
+}
@@ -0,0 +1,211 @@
+package templates
+
+import (
+	"strings"
+
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+// Action is the kind of change planned for a record
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionDelete    Action = "delete"
+	ActionUnchanged Action = "unchanged"
+)
+
+// Change is a planned change of a record. Record is the desired state, or the record
+// to delete; Current is the existing record that is updated or kept.
+type Change struct {
+	Action  Action
+	Record  inwx.DNSRecord
+	Current *inwx.DNSRecord
+}
+
+// Plan compares the rendered template records with the existing records of a domain
+// and returns the changes needed to apply the template
+func Plan(domain string, records []Record, existing []inwx.DNSRecord) []Change {
+	var changes []Change
+	used := make(map[int]bool)
+
+	// Exclusive records replace their whole RRset and are planned together
+	sets := make(map[string][]inwx.DNSRecord)
+	for _, r := range records {
+		if r.Exclusive {
+			desired := r.DNSRecord(domain)
+			key := recordSetKey(desired.Name, desired.Type)
+			sets[key] = append(sets[key], desired)
+		}
+	}
+
+	for _, r := range records {
+		desired := r.DNSRecord(domain)
+		if r.Exclusive {
+			key := recordSetKey(desired.Name, desired.Type)
+			if sets[key] == nil {
+				continue
+			}
+			current := matchingRecords(existing, desired.Name, desired.Type, used)
+			for _, c := range current {
+				used[c.ID] = true
+			}
+			changes = append(changes, planRecordSet(sets[key], current)...)
+			delete(sets, key)
+			continue
+		}
+
+		candidates := matchingRecords(existing, desired.Name, desired.Type, used)
+
+		// An identical record is kept, only adjusting the TTL
+		if current := findRecord(candidates, func(e inwx.DNSRecord) bool {
+			return inwx.SameRecordData(e, desired)
+		}); current != nil {
+			used[current.ID] = true
+			action := ActionUnchanged
+			if current.TTL != desired.TTL {
+				action = ActionUpdate
+			}
+			changes = append(changes, Change{Action: action, Record: withID(desired, current.ID), Current: current})
+			continue
+		}
+
+		// CNAMEs are unique per name, other records are replaced by content prefix
+		if current := findRecord(candidates, func(e inwx.DNSRecord) bool {
+			if desired.Type == "CNAME" {
+				return true
+			}
+			return r.Replaces != "" && strings.HasPrefix(strings.ToLower(inwx.TXTValue(e.Content)), strings.ToLower(r.Replaces))
+		}); current != nil {
+			used[current.ID] = true
+			changes = append(changes, Change{Action: ActionUpdate, Record: withID(desired, current.ID), Current: current})
+			continue
+		}
+
+		changes = append(changes, Change{Action: ActionCreate, Record: desired})
+	}
+
+	return changes
+}
+
+// planRecordSet plans an exclusive RRset with inwx.PlanRecordSet. Current records that
+// are neither updated nor deleted are unchanged.
+func planRecordSet(desired, current []inwx.DNSRecord) []Change {
+	planned := inwx.PlanRecordSet(desired, current)
+
+	touched := make(map[int]bool)
+	for _, p := range planned {
+		if p.Current != nil {
+			touched[p.Current.ID] = true
+		}
+		if p.Action == inwx.RRSetDelete {
+			touched[p.Record.ID] = true
+		}
+	}
+
+	var changes []Change
+	for i := range current {
+		if !touched[current[i].ID] {
+			changes = append(changes, Change{Action: ActionUnchanged, Record: current[i], Current: &current[i]})
+		}
+	}
+	for _, p := range planned {
+		changes = append(changes, Change{Action: rrsetActions[p.Action], Record: p.Record, Current: p.Current})
+	}
+	return changes
+}
+
+// rrsetActions maps the actions of inwx.RRSetChange to template actions
+var rrsetActions = map[string]Action{
+	inwx.RRSetCreate: ActionCreate,
+	inwx.RRSetUpdate: ActionUpdate,
+	inwx.RRSetDelete: ActionDelete,
+}
+
+// Result returns the records of the domain after applying the changes
+func Result(existing []inwx.DNSRecord, changes []Change) []inwx.DNSRecord {
+	replaced := make(map[int]bool)
+	var added []inwx.DNSRecord
+	for _, change := range changes {
+		switch change.Action {
+		case ActionDelete:
+			replaced[change.Record.ID] = true
+		case ActionUpdate:
+			replaced[change.Current.ID] = true
+			added = append(added, change.Record)
+		case ActionCreate:
+			added = append(added, change.Record)
+		}
+	}
+
+	var result []inwx.DNSRecord
+	for _, e := range existing {
+		if !replaced[e.ID] {
+			result = append(result, e)
+		}
+	}
+	return append(result, added...)
+}
+
+// NewIssues returns the issues of after that are not present in before, i.e. the
+// issues caused by the planned changes
+func NewIssues(before, after *inwx.ValidationResult) []inwx.ValidationIssue {
+	seen := make(map[string]bool)
+	for _, issue := range before.Issues {
+		seen[issue.Type+"|"+issue.Message] = true
+	}
+
+	var issues []inwx.ValidationIssue
+	for _, issue := range after.Issues {
+		if !seen[issue.Type+"|"+issue.Message] {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// Matches reports whether an existing record carries the data of a template record
+func Matches(existing inwx.DNSRecord, r Record, domain string) bool {
+	desired := r.DNSRecord(domain)
+	return normalizeName(existing.Name) == desired.Name &&
+		strings.EqualFold(existing.Type, desired.Type) &&
+		inwx.SameRecordData(existing, desired)
+}
+
+func matchingRecords(existing []inwx.DNSRecord, name, recordType string, used map[int]bool) []inwx.DNSRecord {
+	var matching []inwx.DNSRecord
+	for _, e := range existing {
+		if used[e.ID] || normalizeName(e.Name) != name || !strings.EqualFold(e.Type, recordType) {
+			continue
+		}
+		matching = append(matching, e)
+	}
+	return matching
+}
+
+func findRecord(records []inwx.DNSRecord, match func(inwx.DNSRecord) bool) *inwx.DNSRecord {
+	for i := range records {
+		if match(records[i]) {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+func withID(record inwx.DNSRecord, id int) inwx.DNSRecord {
+	record.ID = id
+	return record
+}
+
+func recordSetKey(name, recordType string) string {
+	return name + "|" + recordType
+}
+
+func normalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if name == "" {
+		return "@"
+	}
+	return name
+}
@@ -0,0 +1,173 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/adrg/xdg"
+
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+// Applied records which records a template created or updated in a domain, so the
+// template can be removed as a unit. Previous holds the updated records as they were
+// before, so removing the template restores them instead of deleting them. Deleted
+// holds the records an exclusive record set replaced, so removing the template
+// recreates them.
+type Applied struct {
+	Template  string            `json:"template"`
+	Domain    string            `json:"domain"`
+	Vars      map[string]string `json:"vars,omitempty"`
+	Records   []inwx.DNSRecord  `json:"records"`
+	Previous  []inwx.DNSRecord  `json:"previous,omitempty"`
+	Deleted   []inwx.DNSRecord  `json:"deleted,omitempty"`
+	AppliedAt time.Time         `json:"applied_at"`
+}
+
+// PreviousRecord returns the state of a record before the template updated it, or nil
+// if the template created the record
+func (a *Applied) PreviousRecord(id int) *inwx.DNSRecord {
+	for i := range a.Previous {
+		if a.Previous[i].ID == id {
+			return &a.Previous[i]
+		}
+	}
+	return nil
+}
+
+// State holds the templates applied to domains
+type State struct {
+	path    string
+	Applied []Applied `json:"applied"`
+}
+
+// statePath returns the location of the state file in the XDG data directory
+func statePath() string {
+	return filepath.Join(xdg.DataHome, "inwx", "templates.json")
+}
+
+// LoadState reads the applied templates. A missing state file is not an error.
+func LoadState() (*State, error) {
+	s := &State{path: statePath()}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse template state %s: %w", s.path, err)
+	}
+	return s, nil
+}
+
+// Get returns the application of a template to a domain
+func (s *State) Get(domain, template string) *Applied {
+	for i := range s.Applied {
+		if s.Applied[i].Domain == domain && s.Applied[i].Template == template {
+			return &s.Applied[i]
+		}
+	}
+	return nil
+}
+
+// ForDomain returns the templates applied to a domain
+func (s *State) ForDomain(domain string) []Applied {
+	var applied []Applied
+	for _, a := range s.Applied {
+		if a.Domain == domain {
+			applied = append(applied, a)
+		}
+	}
+	return applied
+}
+
+// Set stores the application of a template, merging the records with a previous
+// application of the same template. Records the template created or updated before
+// keep their original state, so it is restored rather than an intermediate one, and
+// records it deleted before are kept for recreation.
+func (s *State) Set(applied Applied) {
+	if previous := s.Get(applied.Domain, applied.Template); previous != nil {
+		seen := make(map[int]bool)
+		for _, record := range applied.Records {
+			seen[record.ID] = true
+		}
+		for _, record := range previous.Records {
+			if !seen[record.ID] {
+				applied.Records = append(applied.Records, record)
+			}
+		}
+
+		known := make(map[int]bool)
+		for _, record := range previous.Records {
+			known[record.ID] = true
+		}
+		original := previous.Previous
+		for _, record := range applied.Previous {
+			if !known[record.ID] {
+				original = append(original, record)
+			}
+		}
+		applied.Previous = original
+
+		// Records the template created itself are not recreated on removal
+		deleted := previous.Deleted
+		for _, record := range applied.Deleted {
+			if !known[record.ID] {
+				deleted = append(deleted, record)
+			}
+		}
+		applied.Deleted = deleted
+
+		*previous = applied
+		return
+	}
+
+	s.Applied = append(s.Applied, applied)
+	sort.Slice(s.Applied, func(i, j int) bool {
+		if s.Applied[i].Domain != s.Applied[j].Domain {
+			return s.Applied[i].Domain < s.Applied[j].Domain
+		}
+		return s.Applied[i].Template < s.Applied[j].Template
+	})
+}
+
+// Remove forgets the application of a template to a domain
+func (s *State) Remove(domain, template string) {
+	kept := s.Applied[:0]
+	for _, a := range s.Applied {
+		if a.Domain != domain || a.Template != template {
+			kept = append(kept, a)
+		}
+	}
+	s.Applied = kept
+}
+
+// Save writes the state atomically
+func (s *State) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write template state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write template state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+// Package templates provides record templates for common services. Templates are
+// built in or loaded from the user's configuration directory and are rendered into
+// DNS records for a domain.
+package templates
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nmeilick/inwx-cli/internal/utils"
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
+)
+
+//go:embed builtin/*.yaml
+var builtinFS embed.FS
+
+// SourceBuiltin marks templates shipped with the CLI
+const SourceBuiltin = "built-in"
+
+// placeholderPattern matches {{ variable }} placeholders
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_-]+)\s*\}\}`)
+
+// Template describes a set of records for a service
+type Template struct {
+	Name        string     `yaml:"name" json:"name"`
+	Description string     `yaml:"description" json:"description"`
+	Variables   []Variable `yaml:"variables,omitempty" json:"variables,omitempty"`
+	Records     []Record   `yaml:"records" json:"records"`
+
+	// Source is the file the template was loaded from, or SourceBuiltin
+	Source string `yaml:"-" json:"source"`
+}
+
+// Variable is a value that is substituted into the records of a template
+type Variable struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Default     string `yaml:"default,omitempty" json:"default,omitempty"`
+	Required    bool   `yaml:"required,omitempty" json:"required,omitempty"`
+}
+
+// Record is a record of a template. Name, content and the condition may contain
+// placeholders.
+type Record struct {
+	Name    string `yaml:"name" json:"name"`
+	Type    string `yaml:"type" json:"type"`
+	Content string `yaml:"content" json:"content"`
+	TTL     int    `yaml:"ttl,omitempty" json:"ttl,omitempty"`
+	Prio    int    `yaml:"prio,omitempty" json:"prio,omitempty"`
+
+	// Exclusive removes other records of the same name and type, e.g. the MX records
+	// of a previous mail provider
+	Exclusive bool `yaml:"exclusive,omitempty" json:"exclusive,omitempty"`
+	// Replaces updates an existing record whose content starts with this prefix
+	// instead of adding a second one, e.g. "v=spf1" for SPF records
+	Replaces string `yaml:"replaces,omitempty" json:"replaces,omitempty"`
+	// When only includes the record if the named variable is set
+	When string `yaml:"when,omitempty" json:"when,omitempty"`
+}
+
+// Dir returns the directory user templates are loaded from
+func Dir() string {
+	return filepath.Join(xdg.ConfigHome, "inwx", "templates")
+}
+
+// Parse parses and checks a template in YAML format
+func Parse(data []byte, source string) (*Template, error) {
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", source, err)
+	}
+	t.Source = source
+
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	}
+	if len(t.Records) == 0 {
+		return nil, fmt.Errorf("template %s has no records", t.Name)
+	}
+
+	declared := map[string]bool{"domain": true, "domain_dashed": true}
+	for _, v := range t.Variables {
+		if v.Name == "" {
+			return nil, fmt.Errorf("template %s has a variable without name", t.Name)
+		}
+		declared[v.Name] = true
+	}
+
+	for i, r := range t.Records {
+		r.Type = strings.ToUpper(r.Type)
+		t.Records[i].Type = r.Type
+		if err := utils.ValidateRecordType(r.Type); err != nil {
+			return nil, fmt.Errorf("template %s record %d: %w", t.Name, i+1, err)
+		}
+		if r.When != "" && !declared[r.When] {
+			return nil, fmt.Errorf("template %s record %d depends on undeclared variable %s", t.Name, i+1, r.When)
+		}
+		for _, value := range []string{r.Name, r.Content} {
+			for _, match := range placeholderPattern.FindAllStringSubmatch(value, -1) {
+				if !declared[match[1]] {
+					return nil, fmt.Errorf("template %s record %d uses undeclared variable %s", t.Name, i+1, match[1])
+				}
+			}
+		}
+	}
+
+	return &t, nil
+}
+
+// List returns the built-in and user templates sorted by name. User templates override
+// built-in templates with the same name.
+func List() ([]*Template, error) {
+	byName := make(map[string]*Template)
+
+	entries, err := builtinFS.ReadDir("builtin")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		data, err := builtinFS.ReadFile("builtin/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		t, err := Parse(data, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		t.Source = SourceBuiltin
+		byName[t.Name] = t
+	}
+
+	paths, err := filepath.Glob(filepath.Join(Dir(), "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		t, err := Parse(data, path)
+		if err != nil {
+			return nil, err
+		}
+		byName[t.Name] = t
+	}
+
+	templates := make([]*Template, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// Load returns the template with the given name
+func Load(name string) (*Template, error) {
+	all, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range all {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+
+	names := make([]string, 0, len(all))
+	for _, t := range all {
+		names = append(names, t.Name)
+	}
+	return nil, fmt.Errorf("template %s not found (available: %s)", name, strings.Join(names, ", "))
+}
+
+// ParseVars parses variable assignments in the form name=value
+func ParseVars(assignments []string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, assignment := range assignments {
+		name, value, ok := strings.Cut(assignment, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid variable %q: expected name=value", assignment)
+		}
+		vars[strings.TrimSpace(name)] = value
+	}
+	return vars, nil
+}
+
+// Render substitutes the variables into the records of the template for a domain.
+// Records whose condition is not met are left out.
+func (t *Template) Render(domain string, vars map[string]string) ([]Record, error) {
+	values := map[string]string{
+		"domain":        domain,
+		"domain_dashed": strings.ReplaceAll(domain, ".", "-"),
+	}
+
+	declared := make(map[string]bool)
+	var missing []string
+	for _, v := range t.Variables {
+		declared[v.Name] = true
+		value, ok := vars[v.Name]
+		if !ok {
+			value = v.Default
+		}
+		if value == "" && v.Required {
+			missing = append(missing, v.Name)
+		}
+		values[v.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %s requires the variables %s (use --var name=value)", t.Name, strings.Join(missing, ", "))
+	}
+	for name := range vars {
+		if !declared[name] {
+			return nil, fmt.Errorf("template %s has no variable %s", t.Name, name)
+		}
+	}
+
+	substitute := func(value string) string {
+		return placeholderPattern.ReplaceAllStringFunc(value, func(match string) string {
+			return values[placeholderPattern.FindStringSubmatch(match)[1]]
+		})
+	}
+
+	var records []Record
+	for _, r := range t.Records {
+		if r.When != "" && values[r.When] == "" {
+			continue
+		}
+
+		rendered := r
+		rendered.Name = strings.Trim(strings.ToLower(substitute(r.Name)), ".")
+		if rendered.Name == "" {
+			rendered.Name = "@"
+		}
+		rendered.Content = substitute(r.Content)
+		rendered.Replaces = substitute(r.Replaces)
+		if rendered.TTL == 0 {
+			rendered.TTL = 3600
+		}
+		records = append(records, rendered)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("no records to apply with the given variables")
+	}
+	return records, nil
+}
+
+// DNSRecord converts a rendered template record to a DNS record of the domain
+func (r Record) DNSRecord(domain string) inwx.DNSRecord {
+	return inwx.DNSRecord{
+		Domain:  domain,
+		Name:    r.Name,
+		Type:    r.Type,
+		Content: r.Content,
+		TTL:     r.TTL,
+		Prio:    r.Prio,
+	}
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+const (
+	passwordLower   = "abcdefghijkmnopqrstuvwxyz"
+	passwordUpper   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	passwordDigits  = "23456789"
+	passwordSymbols = "!#%+-=?@_"
+)
+
+// GeneratePassword returns a random password of the given length using crypto/rand.
+// It contains at least one lowercase letter, uppercase letter, digit and symbol and
+// avoids easily confused characters.
+func GeneratePassword(length int) (string, error) {
+	classes := []string{passwordLower, passwordUpper, passwordDigits, passwordSymbols}
+	if length < len(classes) {
+		return "", fmt.Errorf("password length must be at least %d", len(classes))
+	}
+
+	all := passwordLower + passwordUpper + passwordDigits + passwordSymbols
+	password := make([]byte, length)
+	for i := range password {
+		charset := all
+		if i < len(classes) {
+			charset = classes[i]
+		}
+		c, err := randomChar(charset)
+		if err != nil {
+			return "", err
+		}
+		password[i] = c
+	}
+
+	// Shuffle so the guaranteed characters are not at fixed positions
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+// randomChar returns a uniformly chosen character of charset
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate password: %w", err)
+	}
+	return charset[n.Int64()], nil
+}
@@ -4,13 +4,15 @@ import (
 	"fmt"
 	"net"
 	"regexp"
-	"strconv"
 	"strings"
+
+	"github.com/nmeilick/inwx-cli/pkg/inwx"
 )
 
 var (
 	domainRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])?)*$`)
 	emailRegex  = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	phoneRegex  = regexp.MustCompile(`^\+[0-9]{1,3}\.[0-9][0-9\-]{3,20}$`)
 )
 
 func ValidateDomain(domain string) error {
@@ -63,11 +65,19 @@ func ValidateRecordContent(recordType, content string) error {
 		return ValidateDomain(content)
 	case "TXT":
 		return validateTXT(content)
-	case "SRV":
-		return validateSRV(content)
 	}
 
-	return nil
+	// Typed records such as SRV, CAA and TLSA
+	_, err := inwx.ParseRecordData(recordType, content, 0)
+	return err
+}
+
+// ValidateIP validates an IPv4 or IPv6 address
+func ValidateIP(ip string) error {
+	if strings.Contains(ip, ":") {
+		return validateIPv6(ip)
+	}
+	return validateIPv4(ip)
 }
 
 func validateIPv4(ip string) error {
@@ -104,36 +114,6 @@ func validateTXT(content string) error {
 	return nil
 }
 
-func validateSRV(content string) error {
-	parts := strings.Fields(content)
-	if len(parts) != 4 {
-		return fmt.Errorf("SRV record must have format: priority weight port target")
-	}
-
-	// Validate priority
-	if _, err := strconv.Atoi(parts[0]); err != nil {
-		return fmt.Errorf("invalid SRV priority: %s", parts[0])
-	}
-
-	// Validate weight
-	if _, err := strconv.Atoi(parts[1]); err != nil {
-		return fmt.Errorf("invalid SRV weight: %s", parts[1])
-	}
-
-	// Validate port
-	port, err := strconv.Atoi(parts[2])
-	if err != nil || port < 0 || port > 65535 {
-		return fmt.Errorf("invalid SRV port: %s", parts[2])
-	}
-
-	// Validate target (should be a domain name)
-	if err := ValidateDomain(parts[3]); err != nil {
-		return fmt.Errorf("invalid SRV target: %w", err)
-	}
-
-	return nil
-}
-
 func ValidateTTL(ttl int) error {
 	if ttl < 1 || ttl > 2147483647 {
 		return fmt.Errorf("TTL must be between 1 and 2147483647")
@@ -148,6 +128,27 @@ func ValidateEmail(email string) error {
 	return nil
 }
 
+// ValidatePhone checks that a phone number uses the international API format (e.g., +49.30123456)
+func ValidatePhone(phone string) error {
+	if !phoneRegex.MatchString(phone) {
+		return fmt.Errorf("invalid phone number format (expected +CC.NUMBER, e.g., +49.30123456)")
+	}
+	return nil
+}
+
+// ValidateCountryCode checks for a two-letter ISO 3166-1 country code
+func ValidateCountryCode(code string) error {
+	if len(code) != 2 {
+		return fmt.Errorf("invalid country code %q (expected two letters, e.g., DE)", code)
+	}
+	for _, r := range strings.ToUpper(code) {
+		if r < 'A' || r > 'Z' {
+			return fmt.Errorf("invalid country code %q (expected two letters, e.g., DE)", code)
+		}
+	}
+	return nil
+}
+
 func ValidateHostname(hostname string) error {
 	if hostname == "" {
 		return fmt.Errorf("hostname cannot be empty")
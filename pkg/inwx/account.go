@@ -2,24 +2,121 @@ package inwx
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 )
 
+const (
+	// RoleStandard grants full access to all functions
+	RoleStandard = 20000
+	// RoleAccounting grants access to balances, invoices and payments
+	RoleAccounting = 20001
+	// RoleDomain grants access to domain management
+	RoleDomain = 20002
+	// RoleHosting grants access to hosting products
+	RoleHosting = 20003
+	// RoleDNS grants access to nameserver and DNS record management
+	RoleDNS = 20004
+	// RoleAuthcodes grants access to domain auth codes
+	RoleAuthcodes = 20005
+)
+
+// RoleNames maps the role IDs accepted by the API to their names
+var RoleNames = map[int]string{
+	RoleStandard:   "standard",
+	RoleAccounting: "accounting",
+	RoleDomain:     "domain",
+	RoleHosting:    "hosting",
+	RoleDNS:        "dns",
+	RoleAuthcodes:  "authcodes",
+}
+
 type AccountService struct {
-	client *Client
+	client  *Client
+	testing bool
+}
+
+type AccountOption func(*AccountService)
+
+// WithAccountTesting executes password and profile changes in the API testing mode
+func WithAccountTesting(testing bool) AccountOption {
+	return func(s *AccountService) {
+		s.testing = testing
+	}
 }
 
 type AccountInfo struct {
-	AccountID  int    `json:"accountId"`
-	CustomerID int    `json:"customerId"`
-	Username   string `json:"username"`
-	Email      string `json:"email"`
+	AccountID   int    `json:"accountId"`
+	CustomerID  int    `json:"customerId"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	Title       string `json:"title,omitempty"`
+	Firstname   string `json:"firstname,omitempty"`
+	Lastname    string `json:"lastname,omitempty"`
+	Org         string `json:"org,omitempty"`
+	Street      string `json:"street,omitempty"`
+	PostalCode  string `json:"pc,omitempty"`
+	City        string `json:"city,omitempty"`
+	CountryCode string `json:"cc,omitempty"`
+	Language    string `json:"language,omitempty"`
+}
+
+// SubAccount is an additional user account below the main account of a customer
+type SubAccount struct {
+	ID          int    `json:"id" yaml:"id"`
+	Username    string `json:"username" yaml:"username"`
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+	Firstname   string `json:"firstname" yaml:"firstname"`
+	Lastname    string `json:"lastname" yaml:"lastname"`
+	Org         string `json:"org,omitempty" yaml:"org,omitempty"`
+	Street      string `json:"street,omitempty" yaml:"street,omitempty"`
+	PostalCode  string `json:"pc,omitempty" yaml:"pc,omitempty"`
+	City        string `json:"city,omitempty" yaml:"city,omitempty"`
+	CountryCode string `json:"cc,omitempty" yaml:"cc,omitempty"`
+	Phone       string `json:"voice,omitempty" yaml:"voice,omitempty"`
+	Fax         string `json:"fax,omitempty" yaml:"fax,omitempty"`
+	Email       string `json:"email" yaml:"email"`
+	Language    string `json:"language,omitempty" yaml:"language,omitempty"`
+	TFAEnabled  bool   `json:"tfaEnabled" yaml:"tfaEnabled"`
+}
+
+// Role is a permission role that can be granted to a sub-account
+type Role struct {
+	ID   int    `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
 }
 
 // Account creates a new account service instance for managing account information
-func (c *Client) Account() *AccountService {
-	return &AccountService{
+func (c *Client) Account(opts ...AccountOption) *AccountService {
+	service := &AccountService{
 		client: c,
 	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// ParseRole converts a role name (e.g. "dns") or numeric role ID into a role ID
+func ParseRole(role string) (int, error) {
+	role = strings.TrimSpace(role)
+	if id, err := strconv.Atoi(role); err == nil {
+		if _, ok := RoleNames[id]; !ok {
+			return 0, fmt.Errorf("unknown role ID: %d", id)
+		}
+		return id, nil
+	}
+
+	for id, name := range RoleNames {
+		if strings.EqualFold(name, role) {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown role %q", role)
 }
 
 func (s *AccountService) Info(ctx context.Context) (*AccountInfo, error) {
@@ -48,7 +145,203 @@ func (s *AccountService) Info(ctx context.Context) (*AccountInfo, error) {
 		if email, ok := resData["email"].(string); ok {
 			info.Email = email
 		}
+		info.Title = getString(resData, "title")
+		info.Firstname = getString(resData, "firstname")
+		info.Lastname = getString(resData, "lastname")
+		info.Org = getString(resData, "org")
+		info.Street = getString(resData, "street")
+		info.PostalCode = getString(resData, "pc")
+		info.City = getString(resData, "city")
+		info.CountryCode = getString(resData, "cc")
+		info.Language = getString(resData, "language")
 	}
 
 	return info, nil
 }
+
+// List returns all sub-accounts of the main account
+func (s *AccountService) List(ctx context.Context) ([]SubAccount, error) {
+	response, err := s.client.transport.Call(ctx, "account.list", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []SubAccount
+	if resData := getResData(response); resData != nil {
+		for _, item := range getMaps(resData, "accounts") {
+			accounts = append(accounts, SubAccount{
+				ID:          getInt(item, "id"),
+				Username:    getString(item, "username"),
+				Firstname:   getString(item, "firstname"),
+				Lastname:    getString(item, "lastname"),
+				Org:         getString(item, "org"),
+				Street:      getString(item, "street"),
+				PostalCode:  getString(item, "pc"),
+				City:        getString(item, "city"),
+				CountryCode: getString(item, "cc"),
+				Phone:       getString(item, "voice"),
+				Fax:         getString(item, "fax"),
+				Email:       getString(item, "email"),
+				TFAEnabled:  getBool(item, "tfaEnabled"),
+			})
+		}
+	}
+
+	return accounts, nil
+}
+
+// Create creates a sub-account and returns its ID. account.create takes no password,
+// so the password of the new account has to be set outside the API.
+func (s *AccountService) Create(ctx context.Context, account SubAccount) (int, error) {
+	params := map[string]interface{}{
+		"username":  account.Username,
+		"title":     strings.ToUpper(account.Title),
+		"firstname": account.Firstname,
+		"lastname":  account.Lastname,
+		"street":    account.Street,
+		"pc":        account.PostalCode,
+		"city":      account.City,
+		"cc":        strings.ToUpper(account.CountryCode),
+		"email":     account.Email,
+	}
+
+	optional := map[string]string{
+		"org":      account.Org,
+		"voice":    account.Phone,
+		"fax":      account.Fax,
+		"language": account.Language,
+	}
+	for key, value := range optional {
+		if value != "" {
+			params[key] = value
+		}
+	}
+
+	response, err := s.client.transport.Call(ctx, "account.create", params)
+	if err != nil {
+		return 0, err
+	}
+
+	if resData := getResData(response); resData != nil {
+		if id := getInt(resData, "id"); id > 0 {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("account.create returned no account ID")
+}
+
+// Delete deletes sub-accounts and returns the number of deleted accounts
+func (s *AccountService) Delete(ctx context.Context, ids ...int) (int, error) {
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("no account IDs specified")
+	}
+
+	response, err := s.client.transport.Call(ctx, "account.delete", map[string]interface{}{
+		"ids": ids,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if resData := getResData(response); resData != nil {
+		return getInt(resData, "countDeleted"), nil
+	}
+
+	return 0, nil
+}
+
+// Roles returns the roles of an account, or of the current account if accountID is 0
+func (s *AccountService) Roles(ctx context.Context, accountID int) ([]Role, error) {
+	params := map[string]interface{}{}
+	if accountID > 0 {
+		params["accountId"] = accountID
+	}
+
+	response, err := s.client.transport.Call(ctx, "account.getroles", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []Role
+	resData := getResData(response)
+	if resData == nil {
+		return roles, nil
+	}
+
+	// Roles are returned either as plain IDs or as objects
+	list, _ := resData["roles"].([]interface{})
+	for _, item := range list {
+		var role Role
+		switch v := item.(type) {
+		case float64:
+			role.ID = int(v)
+		case string:
+			role.ID, _ = strconv.Atoi(v)
+		case map[string]interface{}:
+			role.ID = getInt(v, "id")
+			if role.ID == 0 {
+				role.ID = getInt(v, "roleId")
+			}
+			role.Name = getString(v, "name")
+		}
+		if role.Name == "" {
+			role.Name = RoleNames[role.ID]
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
+
+// AddRole grants a role to an account
+func (s *AccountService) AddRole(ctx context.Context, accountID, roleID int) error {
+	_, err := s.client.transport.Call(ctx, "account.addrole", map[string]interface{}{
+		"accountId": accountID,
+		"roleId":    roleID,
+	})
+	return err
+}
+
+// RemoveRole revokes a role from an account
+func (s *AccountService) RemoveRole(ctx context.Context, accountID, roleID int) error {
+	_, err := s.client.transport.Call(ctx, "account.removerole", map[string]interface{}{
+		"accountId": accountID,
+		"roleId":    roleID,
+	})
+	return err
+}
+
+// ChangePassword changes the password of the logged in account
+func (s *AccountService) ChangePassword(ctx context.Context, username, currentPassword, newPassword string) error {
+	_, err := s.client.transport.Call(ctx, "account.changepassword", s.withTesting(map[string]interface{}{
+		"username":        username,
+		"currentpassword": currentPassword,
+		"password":        newPassword,
+	}))
+	return err
+}
+
+// Update changes account profile settings. The keys of fields are the account.update
+// parameter names (e.g. "email", "lowBalance", "defaultRenewalMode").
+func (s *AccountService) Update(ctx context.Context, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("no account fields to update")
+	}
+
+	params := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		params[key] = value
+	}
+
+	_, err := s.client.transport.Call(ctx, "account.update", s.withTesting(params))
+	return err
+}
+
+// withTesting adds the testing flag to params if the service runs in testing mode
+func (s *AccountService) withTesting(params map[string]interface{}) map[string]interface{} {
+	if s.testing {
+		params["testing"] = true
+	}
+	return params
+}
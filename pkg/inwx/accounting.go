@@ -0,0 +1,438 @@
+package inwx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// accountingPageLimit is the page size used when fetching paged accounting data
+const accountingPageLimit = 100
+
+type AccountingService struct {
+	client *Client
+}
+
+// AccountBalance contains the prepaid deposit of the account
+type AccountBalance struct {
+	Total       float64 `json:"total" yaml:"total"`
+	Available   float64 `json:"available" yaml:"available"`
+	Locked      float64 `json:"locked" yaml:"locked"`
+	CreditLimit float64 `json:"creditLimit" yaml:"creditLimit"`
+}
+
+// Invoice is an entry of the invoice list
+type Invoice struct {
+	ID       string    `json:"invoiceId" yaml:"invoiceId"`
+	Date     time.Time `json:"date" yaml:"date"`
+	AfterTax float64   `json:"afterTax" yaml:"afterTax"`
+	PreTax   float64   `json:"preTax" yaml:"preTax"`
+	Type     string    `json:"type" yaml:"type"`
+}
+
+// AccountingLogEntry is a single transaction of the account
+type AccountingLogEntry struct {
+	Date       time.Time `json:"date" yaml:"date"`
+	Amount     float64   `json:"amount" yaml:"amount"`
+	Type       string    `json:"type" yaml:"type"`
+	Details    string    `json:"details,omitempty" yaml:"details,omitempty"`
+	Refundable bool      `json:"refundable" yaml:"refundable"`
+}
+
+// AccountingLog is a page of account transactions
+type AccountingLog struct {
+	Count   int                  `json:"count" yaml:"count"`
+	Sum     float64              `json:"sum" yaml:"sum"`
+	Page    int                  `json:"page" yaml:"page"`
+	Entries []AccountingLogEntry `json:"entries" yaml:"entries"`
+}
+
+// LockedDomainFund is a deposit amount locked for a pending domain order
+type LockedDomainFund struct {
+	Date   time.Time `json:"date" yaml:"date"`
+	Domain string    `json:"domain" yaml:"domain"`
+	Amount float64   `json:"amount" yaml:"amount"`
+	Status string    `json:"status" yaml:"status"`
+}
+
+// LockedCertificateFund is a deposit amount locked for a pending certificate order
+type LockedCertificateFund struct {
+	CertificateID int       `json:"certificateId" yaml:"certificateId"`
+	CreatedAt     time.Time `json:"creationDate" yaml:"creationDate"`
+	CommonName    string    `json:"commonName,omitempty" yaml:"commonName,omitempty"`
+	Net           float64   `json:"netto" yaml:"netto"`
+	VAT           float64   `json:"vat" yaml:"vat"`
+	ProductID     int       `json:"productId" yaml:"productId"`
+	ProductName   string    `json:"productName" yaml:"productName"`
+	Status        string    `json:"status" yaml:"status"`
+}
+
+// LockedFunds lists all deposit amounts locked for transactions in process
+type LockedFunds struct {
+	Domains      []LockedDomainFund      `json:"funds" yaml:"funds"`
+	Certificates []LockedCertificateFund `json:"sslFunds" yaml:"sslFunds"`
+}
+
+// CreditLog contains the details of a deposit payment
+type CreditLog struct {
+	ID                 int       `json:"id" yaml:"id"`
+	Amount             float64   `json:"amount" yaml:"amount"`
+	CreditTime         time.Time `json:"creditTime" yaml:"creditTime"`
+	CustomerID         int       `json:"customerId" yaml:"customerId"`
+	CreditType         string    `json:"creditType" yaml:"creditType"`
+	TransactionID      string    `json:"transactionId,omitempty" yaml:"transactionId,omitempty"`
+	RefundID           int       `json:"refundId,omitempty" yaml:"refundId,omitempty"`
+	TransactionDetails string    `json:"transactionDetails,omitempty" yaml:"transactionDetails,omitempty"`
+	Currency           string    `json:"currency" yaml:"currency"`
+	Last4              string    `json:"last4,omitempty" yaml:"last4,omitempty"`
+}
+
+// UpcomingRenewal is a domain that will be renewed automatically within the forecast period
+type UpcomingRenewal struct {
+	Domain       string    `json:"domain" yaml:"domain"`
+	RenewalDate  time.Time `json:"renewalDate" yaml:"renewalDate"`
+	Price        float64   `json:"price" yaml:"price"`
+	Currency     string    `json:"currency" yaml:"currency"`
+	PriceUnknown bool      `json:"priceUnknown,omitempty" yaml:"priceUnknown,omitempty"`
+}
+
+// FundsForecast compares the available deposit with the costs of upcoming automatic renewals
+type FundsForecast struct {
+	Balance      float64           `json:"balance" yaml:"balance"`
+	Locked       float64           `json:"locked" yaml:"locked"`
+	Available    float64           `json:"available" yaml:"available"`
+	CreditLimit  float64           `json:"creditLimit" yaml:"creditLimit"`
+	Days         int               `json:"days" yaml:"days"`
+	Renewals     []UpcomingRenewal `json:"renewals" yaml:"renewals"`
+	RenewalCosts float64           `json:"renewalCosts" yaml:"renewalCosts"`
+	UnknownCosts int               `json:"unknownCosts" yaml:"unknownCosts"`
+	Remaining    float64           `json:"remaining" yaml:"remaining"`
+	MinBalance   float64           `json:"minBalance" yaml:"minBalance"`
+	Sufficient   bool              `json:"sufficient" yaml:"sufficient"`
+}
+
+// Accounting creates a new accounting service instance for balances, invoices and transactions
+func (c *Client) Accounting() *AccountingService {
+	return &AccountingService{
+		client: c,
+	}
+}
+
+// Total returns the sum of all locked domain and certificate amounts
+func (f *LockedFunds) Total() float64 {
+	var total float64
+	for _, fund := range f.Domains {
+		total += fund.Amount
+	}
+	for _, fund := range f.Certificates {
+		total += fund.Net + fund.VAT
+	}
+	return total
+}
+
+// Check records the required minimum balance and reports whether the funds remaining
+// after all upcoming renewals stay at or above it. Renewals without a known price fail
+// the check, as their costs may not be covered.
+func (f *FundsForecast) Check(minBalance float64) bool {
+	f.MinBalance = minBalance
+	f.Sufficient = f.Remaining >= minBalance && f.UnknownCosts == 0
+	return f.Sufficient
+}
+
+// Balance returns the account balance
+func (s *AccountingService) Balance(ctx context.Context) (*AccountBalance, error) {
+	response, err := s.client.transport.Call(ctx, "accounting.accountBalance", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &AccountBalance{}
+	if resData := getResData(response); resData != nil {
+		balance.Total = getFloat(resData, "total")
+		balance.Available = getFloat(resData, "available")
+		balance.Locked = getFloat(resData, "locked")
+		balance.CreditLimit = getFloat(resData, "creditLimit")
+	}
+
+	return balance, nil
+}
+
+// ListInvoices returns all invoices of the account
+func (s *AccountingService) ListInvoices(ctx context.Context) ([]Invoice, error) {
+	response, err := s.client.transport.Call(ctx, "accounting.listInvoices", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var invoices []Invoice
+	if resData := getResData(response); resData != nil {
+		for _, item := range getMaps(resData, "invoice") {
+			invoices = append(invoices, Invoice{
+				ID:       getString(item, "invoiceId"),
+				Date:     getTime(item, "date"),
+				AfterTax: getFloat(item, "afterTax"),
+				PreTax:   getFloat(item, "preTax"),
+				Type:     getString(item, "type"),
+			})
+		}
+	}
+
+	return invoices, nil
+}
+
+// GetInvoice returns the PDF document of an invoice
+func (s *AccountingService) GetInvoice(ctx context.Context, invoiceID string) ([]byte, error) {
+	response, err := s.client.transport.Call(ctx, "accounting.getInvoice", map[string]interface{}{
+		"invoiceId": invoiceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("no document returned for invoice %s", invoiceID)
+	}
+
+	return getBase64(resData, "pdf")
+}
+
+// GetReceipt returns the receipt PDF document of a payment
+func (s *AccountingService) GetReceipt(ctx context.Context, paymentID int, amount, details, date string) ([]byte, error) {
+	response, err := s.client.transport.Call(ctx, "accounting.getReceipt", map[string]interface{}{
+		"id":      paymentID,
+		"amount":  amount,
+		"details": details,
+		"date":    date,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("no receipt returned for payment %d", paymentID)
+	}
+
+	return getBase64(resData, "pdf")
+}
+
+// GetStatement returns the statement of all transactions between from and to
+// as document in the given format (e.g. "pdf"; empty uses the API default)
+func (s *AccountingService) GetStatement(ctx context.Context, from, to time.Time, format string) ([]byte, error) {
+	params := map[string]interface{}{
+		"dateFrom": from.Format(time.RFC3339),
+		"dateTo":   to.Format(time.RFC3339),
+	}
+	if format != "" {
+		params["format"] = format
+	}
+
+	response, err := s.client.transport.Call(ctx, "accounting.getstatement", params)
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("no statement returned")
+	}
+
+	// Non-PDF formats are returned as raw text
+	if raw := getString(resData, "raw"); raw != "" {
+		return []byte(raw), nil
+	}
+	return getBase64(resData, "pdf")
+}
+
+// Log returns a page of account transactions between from and to; zero times are ignored
+func (s *AccountingService) Log(ctx context.Context, from, to time.Time, page, limit int) (*AccountingLog, error) {
+	params := map[string]interface{}{
+		"page": page,
+	}
+	if limit > 0 {
+		params["pagelimit"] = limit
+	}
+	if !from.IsZero() {
+		params["dateFrom"] = from.Format(time.RFC3339)
+	}
+	if !to.IsZero() {
+		params["dateTo"] = to.Format(time.RFC3339)
+	}
+
+	response, err := s.client.transport.Call(ctx, "accounting.log", params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AccountingLog{Page: page}
+	if resData := getResData(response); resData != nil {
+		result.Count = getInt(resData, "count")
+		result.Sum = getFloat(resData, "sum")
+		for _, item := range getMaps(resData, "log") {
+			result.Entries = append(result.Entries, AccountingLogEntry{
+				Date:       getTime(item, "date"),
+				Amount:     getFloat(item, "amount"),
+				Type:       getString(item, "type"),
+				Details:    getString(item, "details"),
+				Refundable: getBool(item, "refundable"),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// LockedFunds returns all deposit amounts currently locked for pending orders
+func (s *AccountingService) LockedFunds(ctx context.Context) (*LockedFunds, error) {
+	funds := &LockedFunds{}
+
+	for page := 1; ; page++ {
+		response, err := s.client.transport.Call(ctx, "accounting.lockedFunds", map[string]interface{}{
+			"page":      page,
+			"pagelimit": accountingPageLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resData := getResData(response)
+		if resData == nil {
+			break
+		}
+
+		domains := getMaps(resData, "funds")
+		for _, item := range domains {
+			funds.Domains = append(funds.Domains, LockedDomainFund{
+				Date:   getTime(item, "date"),
+				Domain: getString(item, "domain"),
+				Amount: getFloat(item, "amount"),
+				Status: getString(item, "status"),
+			})
+		}
+
+		certificates := getMaps(resData, "sslFunds")
+		for _, item := range certificates {
+			funds.Certificates = append(funds.Certificates, LockedCertificateFund{
+				CertificateID: getInt(item, "certificateId"),
+				CreatedAt:     getTime(item, "creationDate"),
+				CommonName:    getString(item, "commonName"),
+				Net:           getFloat(item, "netto"),
+				VAT:           getFloat(item, "vat"),
+				ProductID:     getInt(item, "productId"),
+				ProductName:   getString(item, "productName"),
+				Status:        getString(item, "status"),
+			})
+		}
+
+		if len(domains) < accountingPageLimit && len(certificates) < accountingPageLimit {
+			break
+		}
+	}
+
+	return funds, nil
+}
+
+// CreditLog returns the details of a deposit payment
+func (s *AccountingService) CreditLog(ctx context.Context, id int) (*CreditLog, error) {
+	response, err := s.client.transport.Call(ctx, "accounting.creditLogById", map[string]interface{}{
+		"creditLogId": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("credit log %d not found", id)
+	}
+
+	data, ok := resData["log"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("credit log %d not found", id)
+	}
+
+	return &CreditLog{
+		ID:                 getInt(data, "id"),
+		Amount:             getFloat(data, "amount"),
+		CreditTime:         getTime(data, "creditTime"),
+		CustomerID:         getInt(data, "customerId"),
+		CreditType:         getString(data, "creditType"),
+		TransactionID:      getString(data, "transactionId"),
+		RefundID:           getInt(data, "refundId"),
+		TransactionDetails: getString(data, "transactionDetails"),
+		Currency:           getString(data, "currency"),
+		Last4:              getString(data, "last4"),
+	}, nil
+}
+
+// Forecast computes the available funds and the costs of all domains with renewal mode
+// AUTORENEW that are due for renewal within the given number of days. Renewals whose
+// price is unknown are counted in UnknownCosts.
+func (s *AccountingService) Forecast(ctx context.Context, days int) (*FundsForecast, error) {
+	balance, err := s.Balance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	// The total of the balance is the sum of all payments received; the available
+	// deposit already excludes the funds locked for pending transactions
+	forecast := &FundsForecast{
+		Balance:     balance.Available + balance.Locked,
+		Locked:      balance.Locked,
+		Available:   balance.Available,
+		CreditLimit: balance.CreditLimit,
+		Days:        days,
+	}
+
+	domainService := s.client.Domain()
+	domains, err := domainService.List(ctx, WithRenewalMode(RenewalModeAutoRenew))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list domains: %w", err)
+	}
+
+	deadline := time.Now().AddDate(0, 0, days)
+	due := make(map[string]time.Time)
+	var names []string
+	for _, domain := range domains {
+		// Domains are renewed at the renewal date, which is usually before the expiration
+		renewalDate := domain.RenewalDate
+		if renewalDate.IsZero() {
+			renewalDate = domain.ExpiresAt
+		}
+		if renewalDate.IsZero() || renewalDate.After(deadline) {
+			continue
+		}
+		due[domain.Name] = renewalDate
+		names = append(names, domain.Name)
+	}
+
+	if len(names) > 0 {
+		prices, err := domainService.Prices(ctx, "renewal", names)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get renewal prices: %w", err)
+		}
+
+		for _, name := range names {
+			renewal := UpcomingRenewal{Domain: name, RenewalDate: due[name]}
+			if price, ok := prices[strings.ToLower(name)]; ok {
+				renewal.Price = price.Price
+				renewal.Currency = price.Currency
+			} else {
+				renewal.PriceUnknown = true
+				forecast.UnknownCosts++
+			}
+			forecast.Renewals = append(forecast.Renewals, renewal)
+			forecast.RenewalCosts += renewal.Price
+		}
+
+		sort.Slice(forecast.Renewals, func(i, j int) bool {
+			return forecast.Renewals[i].RenewalDate.Before(forecast.Renewals[j].RenewalDate)
+		})
+	}
+
+	forecast.Remaining = forecast.Available - forecast.RenewalCosts
+	return forecast, nil
+}
@@ -0,0 +1,562 @@
+package inwx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// CertificateValidationDNS proves domain control with a DNS record
+	CertificateValidationDNS = "DNS"
+	// CertificateValidationEmail proves domain control with an approval email
+	CertificateValidationEmail = "EMAIL"
+	// CertificateValidationFile proves domain control with a file on the web server
+	CertificateValidationFile = "FILE"
+)
+
+// CertificateValidationMethods lists all domain validation methods accepted by the API
+var CertificateValidationMethods = []string{CertificateValidationDNS, CertificateValidationEmail, CertificateValidationFile}
+
+type CertificateService struct {
+	client  *Client
+	testing bool
+}
+
+type CertificateOption func(*CertificateService)
+
+// WithCertificateTesting executes certificate orders and renewals in the API testing mode
+func WithCertificateTesting(testing bool) CertificateOption {
+	return func(s *CertificateService) {
+		s.testing = testing
+	}
+}
+
+// Certificate is an SSL certificate ordered through the account
+type Certificate struct {
+	ID               int       `json:"id" yaml:"id"`
+	ProductID        int       `json:"productId" yaml:"productId"`
+	Name             string    `json:"name,omitempty" yaml:"name,omitempty"`
+	Brand            string    `json:"brand,omitempty" yaml:"brand,omitempty"`
+	Type             string    `json:"type,omitempty" yaml:"type,omitempty"`
+	CommonName       string    `json:"commonName" yaml:"commonName"`
+	SAN              []string  `json:"san,omitempty" yaml:"san,omitempty"`
+	NumberOfSAN      int       `json:"numberOfSan,omitempty" yaml:"numberOfSan,omitempty"`
+	Status           string    `json:"status" yaml:"status"`
+	Active           bool      `json:"active" yaml:"active"`
+	AutoRenew        bool      `json:"autorenew" yaml:"autorenew"`
+	Period           int       `json:"period,omitempty" yaml:"period,omitempty"`
+	CreatedAt        time.Time `json:"creationDate" yaml:"creationDate"`
+	UpdatedAt        time.Time `json:"updatedAt,omitempty" yaml:"updatedAt,omitempty"`
+	ExpiresAt        time.Time `json:"expirationDate,omitempty" yaml:"expirationDate,omitempty"`
+	ValidationMethod string    `json:"validationMethod,omitempty" yaml:"validationMethod,omitempty"`
+	ValidationEmail  string    `json:"validationEmail,omitempty" yaml:"validationEmail,omitempty"`
+	ValidationToken  string    `json:"validationToken,omitempty" yaml:"validationToken,omitempty"`
+	Owner            int       `json:"ownerc,omitempty" yaml:"ownerc,omitempty"`
+	Admin            int       `json:"adminc,omitempty" yaml:"adminc,omitempty"`
+	Tech             int       `json:"techc,omitempty" yaml:"techc,omitempty"`
+	RenewedFrom      int       `json:"renewedFrom,omitempty" yaml:"renewedFrom,omitempty"`
+	CSR              string    `json:"csr,omitempty" yaml:"csr,omitempty"`
+	Certificate      string    `json:"certificate,omitempty" yaml:"certificate,omitempty"`
+	CAChain          string    `json:"caChain,omitempty" yaml:"caChain,omitempty"`
+}
+
+// CertificateProduct is a certificate type that can be ordered
+type CertificateProduct struct {
+	ID               int     `json:"id" yaml:"id"`
+	Name             string  `json:"name" yaml:"name"`
+	Brand            string  `json:"brand" yaml:"brand"`
+	Description      string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Type             string  `json:"type" yaml:"type"`
+	SAN              int     `json:"san" yaml:"san"`
+	FreeSANIncluded  int     `json:"freeSanIncluded" yaml:"freeSanIncluded"`
+	Wildcard         bool    `json:"wildcard" yaml:"wildcard"`
+	IssuanceTime     string  `json:"issuanceTime,omitempty" yaml:"issuanceTime,omitempty"`
+	IssuanceTimeUnit string  `json:"issuanceTimeUnit,omitempty" yaml:"issuanceTimeUnit,omitempty"`
+	FreeReissue      bool    `json:"freeReissue" yaml:"freeReissue"`
+	Price1Year       float64 `json:"price1year" yaml:"price1year"`
+	PricePerSAN1Year float64 `json:"pricePerSan1Year" yaml:"pricePerSan1Year"`
+	Currency         string  `json:"currency" yaml:"currency"`
+}
+
+// CertificateOrder holds the parameters of a certificate order, renewal or order update.
+// Zero values are not sent to the API.
+type CertificateOrder struct {
+	ProductID        int
+	CSR              string
+	CommonName       string
+	Period           int
+	ValidationMethod string
+	ValidationEmail  string
+	Owner            int
+	Admin            int
+	Tech             int
+	NumberOfSAN      int
+	AutoRenew        *bool
+}
+
+// CertificateOrderResult is the outcome of an order, renewal or reissue
+type CertificateOrderResult struct {
+	CertificateID int      `json:"certificateId" yaml:"certificateId"`
+	Status        string   `json:"status" yaml:"status"`
+	Price         float64  `json:"price,omitempty" yaml:"price,omitempty"`
+	Missing       []string `json:"missing,omitempty" yaml:"missing,omitempty"`
+}
+
+// CertificateValidationRecord is a DNS record that proves control over a certificate domain
+type CertificateValidationRecord struct {
+	Name    string `json:"name" yaml:"name"`
+	Type    string `json:"type" yaml:"type"`
+	Content string `json:"content" yaml:"content"`
+}
+
+// Certificate creates a new certificate service instance
+func (c *Client) Certificate(opts ...CertificateOption) *CertificateService {
+	service := &CertificateService{
+		client: c,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// IsValidCertificateValidationMethod reports whether method is a validation method accepted by the API
+func IsValidCertificateValidationMethod(method string) bool {
+	for _, m := range CertificateValidationMethods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *CertificateService) withTesting(params map[string]interface{}) map[string]interface{} {
+	if s.testing {
+		params["testing"] = true
+	}
+	return params
+}
+
+// List returns the certificates of the account, including inactive ones if requested
+func (s *CertificateService) List(ctx context.Context, showInactive bool) ([]Certificate, error) {
+	response, err := s.client.transport.Call(ctx, "certificate.list", map[string]interface{}{
+		"showInactive": showInactive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []Certificate
+	if resData := getResData(response); resData != nil {
+		for _, item := range getListMaps(resData, "certificate", "certificates", "data") {
+			certs = append(certs, parseCertificate(item))
+		}
+	}
+
+	return certs, nil
+}
+
+// Info retrieves the details of a certificate including the CSR, certificate and CA chain
+func (s *CertificateService) Info(ctx context.Context, id int) (*Certificate, error) {
+	response, err := s.client.transport.Call(ctx, "certificate.info", map[string]interface{}{
+		"certificateId": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("certificate %d not found", id)
+	}
+
+	cert := parseCertificate(resData)
+	if cert.ID == 0 {
+		cert.ID = id
+	}
+	return &cert, nil
+}
+
+// Products returns all certificate products with their prices
+func (s *CertificateService) Products(ctx context.Context) ([]CertificateProduct, error) {
+	response, err := s.client.transport.Call(ctx, "certificate.listProducts", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var products []CertificateProduct
+	if resData := getResData(response); resData != nil {
+		for _, item := range getListMaps(resData, "product", "products", "data") {
+			products = append(products, parseCertificateProduct(item))
+		}
+	}
+
+	return products, nil
+}
+
+// Product retrieves a certificate product by ID
+func (s *CertificateService) Product(ctx context.Context, id int) (*CertificateProduct, error) {
+	response, err := s.client.transport.Call(ctx, "certificate.getProduct", map[string]interface{}{
+		"productId": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("certificate product %d not found", id)
+	}
+
+	product := parseCertificateProduct(resData)
+	if product.ID == 0 {
+		product.ID = id
+	}
+	return &product, nil
+}
+
+// ResolveProduct looks up a certificate product by ID or by its (case-insensitive) name
+func (s *CertificateService) ResolveProduct(ctx context.Context, ref string) (*CertificateProduct, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil {
+		return s.Product(ctx, id)
+	}
+
+	products, err := s.Products(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		if strings.EqualFold(products[i].Name, ref) {
+			return &products[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("certificate product %q not found", ref)
+}
+
+// Order orders a new certificate
+func (s *CertificateService) Order(ctx context.Context, order CertificateOrder) (*CertificateOrderResult, error) {
+	if order.ProductID == 0 {
+		return nil, fmt.Errorf("product ID is required")
+	}
+
+	params := s.withTesting(certificateOrderParams(order))
+	params["productId"] = order.ProductID
+
+	response, err := s.client.transport.Call(ctx, "certificate.create", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCertificateOrderResult(response), nil
+}
+
+// Renew renews a certificate; the returned ID is that of the new certificate
+func (s *CertificateService) Renew(ctx context.Context, id int, order CertificateOrder) (*CertificateOrderResult, error) {
+	params := s.withTesting(certificateOrderParams(order))
+	params["certificateId"] = id
+
+	response, err := s.client.transport.Call(ctx, "certificate.renew", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCertificateOrderResult(response), nil
+}
+
+// Reissue requests a new certificate for a new CSR without changing the term
+func (s *CertificateService) Reissue(ctx context.Context, id int, csr string) (*CertificateOrderResult, error) {
+	if strings.TrimSpace(csr) == "" {
+		return nil, fmt.Errorf("CSR is required")
+	}
+
+	response, err := s.client.transport.Call(ctx, "certificate.reissue", map[string]interface{}{
+		"certificateId": id,
+		"csr":           csr,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCertificateOrderResult(response), nil
+}
+
+// UpdateOrder submits missing information of a pending certificate order
+func (s *CertificateService) UpdateOrder(ctx context.Context, id int, order CertificateOrder) (*CertificateOrderResult, error) {
+	params := certificateOrderParams(order)
+	params["certificateId"] = id
+
+	response, err := s.client.transport.Call(ctx, "certificate.updateOrder", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCertificateOrderResult(response), nil
+}
+
+// RemainingData returns the values still needed to complete a certificate order
+func (s *CertificateService) RemainingData(ctx context.Context, id int) ([]string, error) {
+	response, err := s.client.transport.Call(ctx, "certificate.listRemainingNeededData", map[string]interface{}{
+		"certificateId": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resData := getResData(response); resData != nil {
+		return getStrings(resData, "missing"), nil
+	}
+	return nil, nil
+}
+
+// Cancel cancels a pending certificate request
+func (s *CertificateService) Cancel(ctx context.Context, id int) error {
+	_, err := s.client.transport.Call(ctx, "certificate.cancel", map[string]interface{}{
+		"certificateId": id,
+	})
+	return err
+}
+
+// SetAutoRenew enables or disables the automatic renewal of a certificate
+func (s *CertificateService) SetAutoRenew(ctx context.Context, id int, autoRenew bool) error {
+	_, err := s.client.transport.Call(ctx, "certificate.setAutorenew", map[string]interface{}{
+		"certificateId": id,
+		"autorenew":     autoRenew,
+	})
+	return err
+}
+
+// Domains returns the common name and all SANs of the certificate without duplicates
+func (cert *Certificate) Domains() []string {
+	var domains []string
+	seen := make(map[string]bool)
+	for _, name := range append([]string{cert.CommonName}, cert.SAN...) {
+		name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		domains = append(domains, name)
+	}
+	return domains
+}
+
+// IsIssued reports whether the certificate has been issued
+func (cert *Certificate) IsIssued() bool {
+	return cert.Active || strings.TrimSpace(cert.Certificate) != ""
+}
+
+// IsFailed reports whether the certificate request has been cancelled or rejected
+func (cert *Certificate) IsFailed() bool {
+	status := strings.ToUpper(cert.Status)
+	for _, s := range []string{"CANCEL", "FAIL", "REJECT", "REVOKE"} {
+		if strings.Contains(status, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returned by DNSValidationRecords
+var (
+	// ErrNoValidationToken means the API has not provided the validation token yet
+	ErrNoValidationToken = errors.New("no DNS validation token yet")
+	// ErrUnknownValidationToken means the validation token does not spell out the DNS
+	// records to create, which then have to be set up as described by the CA
+	ErrUnknownValidationToken = errors.New("validation token does not name the DNS records to create")
+)
+
+// DNSValidationRecords returns the DNS records that prove control over the certificate
+// domains, taken from a validation token of the form "<name> <type> <value>". Records
+// are never derived otherwise, as the naming scheme differs between CAs and products.
+func (cert *Certificate) DNSValidationRecords() ([]CertificateValidationRecord, error) {
+	token := strings.TrimSpace(cert.ValidationToken)
+	if token == "" {
+		return nil, fmt.Errorf("certificate %d: %w", cert.ID, ErrNoValidationToken)
+	}
+	records := parseValidationToken(token)
+	if len(records) == 0 {
+		return nil, fmt.Errorf("certificate %d: %w", cert.ID, ErrUnknownValidationToken)
+	}
+	return dedupeValidationRecords(records), nil
+}
+
+// parseValidationToken parses tokens that spell out the records to create, one per line
+func parseValidationToken(token string) []CertificateValidationRecord {
+	var records []CertificateValidationRecord
+	for _, line := range strings.Split(token, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		// Accept zone file notation with optional TTL and class
+		recordType := ""
+		idx := 1
+		for ; idx < len(fields)-1; idx++ {
+			upper := strings.ToUpper(fields[idx])
+			if upper == "TXT" || upper == "CNAME" {
+				recordType = upper
+				break
+			}
+		}
+		if recordType == "" {
+			continue
+		}
+
+		content := strings.Trim(strings.Join(fields[idx+1:], " "), "\"")
+		if recordType == "CNAME" {
+			content = strings.TrimSuffix(content, ".")
+		}
+		records = append(records, CertificateValidationRecord{
+			Name:    strings.ToLower(strings.TrimSuffix(fields[0], ".")),
+			Type:    recordType,
+			Content: content,
+		})
+	}
+	return records
+}
+
+func dedupeValidationRecords(records []CertificateValidationRecord) []CertificateValidationRecord {
+	var result []CertificateValidationRecord
+	seen := make(map[string]bool)
+	for _, record := range records {
+		key := record.Name + "|" + record.Type + "|" + record.Content
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+// certificateOrderParams converts the non-zero fields of an order to API parameters
+func certificateOrderParams(order CertificateOrder) map[string]interface{} {
+	params := map[string]interface{}{}
+
+	if order.CSR != "" {
+		params["csr"] = order.CSR
+	}
+	if order.CommonName != "" {
+		params["commonName"] = order.CommonName
+	}
+	if order.Period > 0 {
+		params["period"] = order.Period
+	}
+	if order.ValidationMethod != "" {
+		params["validationMethod"] = strings.ToUpper(order.ValidationMethod)
+	}
+	if order.ValidationEmail != "" {
+		params["validationEmail"] = order.ValidationEmail
+	}
+	if order.Owner > 0 {
+		params["ownerc"] = order.Owner
+	}
+	if order.Admin > 0 {
+		params["adminc"] = order.Admin
+	}
+	if order.Tech > 0 {
+		params["techc"] = order.Tech
+	}
+	if order.NumberOfSAN > 0 {
+		params["numberOfSan"] = order.NumberOfSAN
+	}
+	if order.AutoRenew != nil {
+		params["autorenew"] = *order.AutoRenew
+	}
+
+	return params
+}
+
+// getListMaps returns the objects of the first list found under one of keys. The wrapper
+// key of some list responses is undocumented, so any other list of objects is used as a
+// fallback.
+func getListMaps(m map[string]interface{}, keys ...string) []map[string]interface{} {
+	for _, key := range keys {
+		if items := getMaps(m, key); len(items) > 0 {
+			return items
+		}
+	}
+	for key := range m {
+		if items := getMaps(m, key); len(items) > 0 {
+			return items
+		}
+	}
+	return nil
+}
+
+func parseCertificate(item map[string]interface{}) Certificate {
+	cert := Certificate{
+		ID:               getInt(item, "id"),
+		ProductID:        getInt(item, "productId"),
+		Name:             getString(item, "name"),
+		Brand:            getString(item, "brand"),
+		Type:             getString(item, "type"),
+		SAN:              getStrings(item, "san"),
+		NumberOfSAN:      getInt(item, "numberOfSan"),
+		Status:           getString(item, "status"),
+		Active:           getBool(item, "active"),
+		AutoRenew:        getBool(item, "autorenew"),
+		Period:           getInt(item, "period"),
+		CreatedAt:        getTime(item, "creationDate"),
+		UpdatedAt:        getTime(item, "updatedAt"),
+		ExpiresAt:        getTime(item, "expirationDate"),
+		ValidationMethod: getString(item, "validationMethod"),
+		ValidationEmail:  getString(item, "validationEmail"),
+		ValidationToken:  getString(item, "validationToken"),
+		Owner:            getInt(item, "ownerc"),
+		Admin:            getInt(item, "adminc"),
+		Tech:             getInt(item, "techc"),
+		RenewedFrom:      getInt(item, "renewedFrom"),
+		CSR:              getString(item, "csr"),
+		Certificate:      getString(item, "certificate"),
+		CAChain:          getString(item, "caChain"),
+	}
+
+	// The common name is documented as an array but may also be sent as a string
+	if names := getStrings(item, "commonName"); len(names) > 0 {
+		cert.CommonName = names[0]
+		cert.SAN = append(cert.SAN, names[1:]...)
+	} else {
+		cert.CommonName = getString(item, "commonName")
+	}
+
+	return cert
+}
+
+func parseCertificateProduct(item map[string]interface{}) CertificateProduct {
+	return CertificateProduct{
+		ID:               getInt(item, "id"),
+		Name:             getString(item, "name"),
+		Brand:            getString(item, "brand"),
+		Description:      getString(item, "description"),
+		Type:             getString(item, "type"),
+		SAN:              getInt(item, "san"),
+		FreeSANIncluded:  getInt(item, "freeSanIncluded"),
+		Wildcard:         getBool(item, "wildcard"),
+		IssuanceTime:     getString(item, "issuanceTime"),
+		IssuanceTimeUnit: getString(item, "issuanceTimeUnit"),
+		FreeReissue:      getBool(item, "freeReissue"),
+		Price1Year:       getFloat(item, "price1year"),
+		PricePerSAN1Year: getFloat(item, "pricePerSan1Year"),
+		Currency:         getString(item, "currency"),
+	}
+}
+
+func parseCertificateOrderResult(response map[string]interface{}) *CertificateOrderResult {
+	result := &CertificateOrderResult{}
+	if resData := getResData(response); resData != nil {
+		result.CertificateID = getInt(resData, "certificateId")
+		result.Status = getString(resData, "status")
+		result.Price = getFloat(resData, "price")
+		result.Missing = getStrings(resData, "missing")
+	}
+	return result
+}
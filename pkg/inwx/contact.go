@@ -0,0 +1,324 @@
+package inwx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// ContactTypePerson is a contact handle for a natural person
+	ContactTypePerson = "PERSON"
+	// ContactTypeOrganization is a contact handle for a company or organization
+	ContactTypeOrganization = "ORG"
+	// ContactTypeRole is a contact handle for a role (e.g. hostmaster)
+	ContactTypeRole = "ROLE"
+
+	// contactPageLimit is the page size used when listing contact handles
+	contactPageLimit = 100
+)
+
+// ContactTypes lists all contact handle types accepted by the API
+var ContactTypes = []string{ContactTypePerson, ContactTypeOrganization, ContactTypeRole}
+
+type ContactService struct {
+	client  *Client
+	testing bool
+}
+
+type ContactOption func(*ContactService)
+
+// WithContactTesting executes modifying contact calls in the API testing mode
+func WithContactTesting(testing bool) ContactOption {
+	return func(s *ContactService) {
+		s.testing = testing
+	}
+}
+
+// Contact is a contact handle used as registrant, admin, tech or billing contact of domains.
+// Field names follow the API so that input files can use the documented parameter names.
+type Contact struct {
+	ID                 int    `json:"id,omitempty" yaml:"id,omitempty"`
+	Type               string `json:"type" yaml:"type"`
+	Name               string `json:"name" yaml:"name"`
+	Org                string `json:"org,omitempty" yaml:"org,omitempty"`
+	Street             string `json:"street" yaml:"street"`
+	City               string `json:"city" yaml:"city"`
+	PostalCode         string `json:"pc" yaml:"pc"`
+	State              string `json:"sp,omitempty" yaml:"sp,omitempty"`
+	CountryCode        string `json:"cc" yaml:"cc"`
+	Phone              string `json:"voice" yaml:"voice"`
+	Fax                string `json:"fax,omitempty" yaml:"fax,omitempty"`
+	Email              string `json:"email" yaml:"email"`
+	Remarks            string `json:"remarks,omitempty" yaml:"remarks,omitempty"`
+	ReadOnly           bool   `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+	UsedCount          int    `json:"usedCount,omitempty" yaml:"usedCount,omitempty"`
+	VerificationStatus string `json:"verificationStatus,omitempty" yaml:"verificationStatus,omitempty"`
+}
+
+// ContactLogEntry is a single change of a contact handle
+type ContactLogEntry struct {
+	LogID     int       `json:"logId"`
+	Date      time.Time `json:"date"`
+	ContactID int       `json:"id"`
+	Status    string    `json:"status"`
+}
+
+// Contact creates a new contact service instance for managing contact handles
+func (c *Client) Contact(opts ...ContactOption) *ContactService {
+	service := &ContactService{
+		client: c,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// IsValidContactType reports whether contactType is a contact type accepted by the API
+func IsValidContactType(contactType string) bool {
+	for _, t := range ContactTypes {
+		if strings.EqualFold(t, contactType) {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns all contact handles, optionally filtered by a search string
+func (s *ContactService) List(ctx context.Context, search string) ([]Contact, error) {
+	var contacts []Contact
+
+	for page := 1; ; page++ {
+		params := map[string]interface{}{
+			"page":      page,
+			"pagelimit": contactPageLimit,
+		}
+		if search != "" {
+			params["search"] = search
+		}
+
+		response, err := s.client.transport.Call(ctx, "contact.list", params)
+		if err != nil {
+			return nil, err
+		}
+
+		resData := getResData(response)
+		if resData == nil {
+			break
+		}
+
+		items := getMaps(resData, "contact")
+		for _, item := range items {
+			contacts = append(contacts, parseContact(item))
+		}
+
+		if len(items) < contactPageLimit || len(contacts) >= getInt(resData, "count") {
+			break
+		}
+	}
+
+	return contacts, nil
+}
+
+// Info retrieves the details of a contact handle
+func (s *ContactService) Info(ctx context.Context, id int) (*Contact, error) {
+	response, err := s.client.transport.Call(ctx, "contact.info", map[string]interface{}{
+		"id":   id,
+		"wide": 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("contact %d not found", id)
+	}
+
+	data, ok := resData["contact"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("contact %d not found", id)
+	}
+
+	contact := parseContact(data)
+	return &contact, nil
+}
+
+// Create creates a new contact handle and returns its ID
+func (s *ContactService) Create(ctx context.Context, contact Contact) (int, error) {
+	if contact.Type == "" {
+		return 0, fmt.Errorf("contact type is required")
+	}
+
+	params := s.withTesting(contactParams(contact))
+	params["type"] = strings.ToUpper(contact.Type)
+
+	response, err := s.client.transport.Call(ctx, "contact.create", params)
+	if err != nil {
+		return 0, err
+	}
+
+	if resData := getResData(response); resData != nil {
+		return getInt(resData, "id"), nil
+	}
+
+	return 0, nil
+}
+
+// ContactClearableFields are the optional contact fields, by API parameter name, that an
+// update can clear
+var ContactClearableFields = []string{"org", "sp", "fax", "remarks"}
+
+// IsClearableContactField reports whether an update can clear the contact field
+func IsClearableContactField(field string) bool {
+	for _, f := range ContactClearableFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Update changes the non-empty fields of an existing contact handle and clears the
+// fields named in clearFields (see ContactClearableFields)
+func (s *ContactService) Update(ctx context.Context, id int, contact Contact, clearFields ...string) error {
+	params := s.withTesting(contactParams(contact))
+	params["id"] = id
+	for _, field := range clearFields {
+		if !IsClearableContactField(field) {
+			return fmt.Errorf("contact field %q cannot be cleared", field)
+		}
+		if _, ok := params[field]; ok {
+			return fmt.Errorf("contact field %q cannot be set and cleared at once", field)
+		}
+		params[field] = ""
+	}
+
+	_, err := s.client.transport.Call(ctx, "contact.update", params)
+	return err
+}
+
+// Delete deletes a contact handle; handles still in use by domains cannot be deleted
+func (s *ContactService) Delete(ctx context.Context, id int) error {
+	_, err := s.client.transport.Call(ctx, "contact.delete", s.withTesting(map[string]interface{}{
+		"id": id,
+	}))
+	return err
+}
+
+// Log returns the change history of a contact handle, or of all handles if id is 0
+func (s *ContactService) Log(ctx context.Context, id int) ([]ContactLogEntry, error) {
+	params := map[string]interface{}{}
+	if id > 0 {
+		params["id"] = id
+	}
+
+	response, err := s.client.transport.Call(ctx, "contact.log", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ContactLogEntry
+	if resData := getResData(response); resData != nil {
+		for _, item := range getMaps(resData, "contact") {
+			entries = append(entries, ContactLogEntry{
+				LogID:     getInt(item, "logId"),
+				Date:      getTime(item, "date"),
+				ContactID: getInt(item, "id"),
+				Status:    getString(item, "status"),
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// SendVerification triggers the verification email for a single contact handle
+func (s *ContactService) SendVerification(ctx context.Context, id int) error {
+	_, err := s.client.transport.Call(ctx, "contact.sendcontactverification", map[string]interface{}{
+		"id": id,
+	})
+	return err
+}
+
+// SendBulkVerification triggers verification emails for all contact handles matching
+// the search string and verification statuses (e.g. NONE, TO_NOTIFY)
+func (s *ContactService) SendBulkVerification(ctx context.Context, search string, statuses []string) error {
+	params := map[string]interface{}{}
+	if search != "" {
+		params["search"] = search
+	}
+	if len(statuses) > 0 {
+		params["verificationStatus"] = statuses
+	}
+
+	_, err := s.client.transport.Call(ctx, "contact.sendbulkverification", params)
+	return err
+}
+
+// withTesting adds the testing flag to params if the service runs in testing mode
+func (s *ContactService) withTesting(params map[string]interface{}) map[string]interface{} {
+	if s.testing {
+		params["testing"] = true
+	}
+	return params
+}
+
+// contactParams converts the non-empty fields of a contact into API parameters
+func contactParams(contact Contact) map[string]interface{} {
+	params := make(map[string]interface{})
+
+	fields := map[string]string{
+		"name":    contact.Name,
+		"org":     contact.Org,
+		"street":  contact.Street,
+		"city":    contact.City,
+		"pc":      contact.PostalCode,
+		"sp":      contact.State,
+		"cc":      strings.ToUpper(contact.CountryCode),
+		"voice":   contact.Phone,
+		"fax":     contact.Fax,
+		"email":   contact.Email,
+		"remarks": contact.Remarks,
+	}
+	for key, value := range fields {
+		if value != "" {
+			params[key] = value
+		}
+	}
+
+	return params
+}
+
+// parseContact converts a contact object from contact.info or contact.list
+func parseContact(data map[string]interface{}) Contact {
+	contact := Contact{
+		ID:                 getInt(data, "id"),
+		Type:               getString(data, "type"),
+		Name:               getString(data, "name"),
+		Org:                getString(data, "org"),
+		Street:             getString(data, "street"),
+		City:               getString(data, "city"),
+		PostalCode:         getString(data, "pc"),
+		State:              getString(data, "sp"),
+		CountryCode:        getString(data, "cc"),
+		Phone:              getString(data, "voice"),
+		Fax:                getString(data, "fax"),
+		Email:              getString(data, "email"),
+		Remarks:            getString(data, "remarks"),
+		ReadOnly:           getBool(data, "readOnly"),
+		UsedCount:          getInt(data, "usedCount"),
+		VerificationStatus: getString(data, "verificationStatus"),
+	}
+
+	// contact.info returns the numeric handle as roId
+	if contact.ID == 0 {
+		contact.ID = getInt(data, "roId")
+	}
+
+	return contact
+}
@@ -0,0 +1,232 @@
+package inwx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CustomerDocuments lists the generic documents available from pdf.document in the
+// "dsgvo" (GDPR) category
+var CustomerDocuments = []string{"contract", "contract_empty", "appendix1", "appendix2"}
+
+type CustomerService struct {
+	client  *Client
+	testing bool
+}
+
+type CustomerOption func(*CustomerService)
+
+// WithCustomerTesting executes customer updates in the API testing mode
+func WithCustomerTesting(testing bool) CustomerOption {
+	return func(s *CustomerService) {
+		s.testing = testing
+	}
+}
+
+// Customer holds the customer data shared by all accounts of a customer
+type Customer struct {
+	CustomerID         int       `json:"customerId" yaml:"customerId"`
+	CustomerNo         int       `json:"customerNo" yaml:"customerNo"`
+	AccountID          int       `json:"accountId" yaml:"accountId"`
+	Username           string    `json:"username" yaml:"username"`
+	Title              string    `json:"title,omitempty" yaml:"title,omitempty"`
+	Firstname          string    `json:"firstname,omitempty" yaml:"firstname,omitempty"`
+	Lastname           string    `json:"lastname,omitempty" yaml:"lastname,omitempty"`
+	Org                string    `json:"org,omitempty" yaml:"org,omitempty"`
+	Street             string    `json:"street,omitempty" yaml:"street,omitempty"`
+	PostalCode         string    `json:"pc,omitempty" yaml:"pc,omitempty"`
+	City               string    `json:"city,omitempty" yaml:"city,omitempty"`
+	CountryCode        string    `json:"cc,omitempty" yaml:"cc,omitempty"`
+	Phone              string    `json:"voice,omitempty" yaml:"voice,omitempty"`
+	Fax                string    `json:"fax,omitempty" yaml:"fax,omitempty"`
+	Web                string    `json:"www,omitempty" yaml:"www,omitempty"`
+	Email              string    `json:"email" yaml:"email"`
+	EmailBilling       string    `json:"emailBilling,omitempty" yaml:"emailBilling,omitempty"`
+	EmailAutomated     string    `json:"emailAutomated,omitempty" yaml:"emailAutomated,omitempty"`
+	WDRPEmail          string    `json:"wdrpEmail,omitempty" yaml:"wdrpEmail,omitempty"`
+	Language           string    `json:"language,omitempty" yaml:"language,omitempty"`
+	Currency           string    `json:"currency,omitempty" yaml:"currency,omitempty"`
+	VATNo              string    `json:"vatNo,omitempty" yaml:"vatNo,omitempty"`
+	PaymentType        string    `json:"paymentType,omitempty" yaml:"paymentType,omitempty"`
+	SecureMode         bool      `json:"secureMode" yaml:"secureMode"`
+	SummaryInvoice     bool      `json:"summaryInvoice" yaml:"summaryInvoice"`
+	InvoicePDF         bool      `json:"invoicePdf" yaml:"invoicePdf"`
+	InvoiceXML         bool      `json:"invoiceXml" yaml:"invoiceXml"`
+	DisablePremium     bool      `json:"disablepremium" yaml:"disablepremium"`
+	EnableAdvertising  bool      `json:"enableAdvertising" yaml:"enableAdvertising"`
+	IsReseller         bool      `json:"isReseller" yaml:"isReseller"`
+	TFA                string    `json:"tfa,omitempty" yaml:"tfa,omitempty"`
+	DefaultRenewalMode string    `json:"defaultRenewalMode,omitempty" yaml:"defaultRenewalMode,omitempty"`
+	CreatedAt          time.Time `json:"crDate" yaml:"crDate"`
+	LastLogin          time.Time `json:"lastLogin,omitempty" yaml:"lastLogin,omitempty"`
+	LastIP             string    `json:"lastIP,omitempty" yaml:"lastIP,omitempty"`
+}
+
+// CustomerDownload is a file provided for download, such as a GDPR data export
+type CustomerDownload struct {
+	Token     string    `json:"token" yaml:"token"`
+	Status    string    `json:"status" yaml:"status"`
+	Created   time.Time `json:"created" yaml:"created"`
+	Expires   time.Time `json:"expires" yaml:"expires"`
+	Downloads int       `json:"downloads" yaml:"downloads"`
+}
+
+// Customer creates a new customer service instance
+func (c *Client) Customer(opts ...CustomerOption) *CustomerService {
+	service := &CustomerService{
+		client: c,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+func (s *CustomerService) withTesting(params map[string]interface{}) map[string]interface{} {
+	if s.testing {
+		params["testing"] = true
+	}
+	return params
+}
+
+// Info retrieves the customer data
+func (s *CustomerService) Info(ctx context.Context) (*Customer, error) {
+	response, err := s.client.transport.Call(ctx, "customer.info", map[string]interface{}{
+		"wide": 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("no customer data returned")
+	}
+
+	return &Customer{
+		CustomerID:         getInt(resData, "customerId"),
+		CustomerNo:         getInt(resData, "customerNo"),
+		AccountID:          getInt(resData, "accountId"),
+		Username:           getString(resData, "username"),
+		Title:              getString(resData, "title"),
+		Firstname:          getString(resData, "firstname"),
+		Lastname:           getString(resData, "lastname"),
+		Org:                getString(resData, "org"),
+		Street:             getString(resData, "street"),
+		PostalCode:         getString(resData, "pc"),
+		City:               getString(resData, "city"),
+		CountryCode:        getString(resData, "cc"),
+		Phone:              getString(resData, "voice"),
+		Fax:                getString(resData, "fax"),
+		Web:                getString(resData, "www"),
+		Email:              getString(resData, "email"),
+		EmailBilling:       getString(resData, "emailBilling"),
+		EmailAutomated:     getString(resData, "emailAutomated"),
+		WDRPEmail:          getString(resData, "wdrpEmail"),
+		Language:           getString(resData, "language"),
+		Currency:           getString(resData, "currency"),
+		VATNo:              getString(resData, "vatNo"),
+		PaymentType:        getString(resData, "paymentType"),
+		SecureMode:         getBool(resData, "secureMode"),
+		SummaryInvoice:     getBool(resData, "summaryInvoice"),
+		InvoicePDF:         getBool(resData, "invoicePdf"),
+		InvoiceXML:         getBool(resData, "invoiceXml"),
+		DisablePremium:     getBool(resData, "disablepremium"),
+		EnableAdvertising:  getBool(resData, "enableAdvertising"),
+		IsReseller:         getBool(resData, "isReseller"),
+		TFA:                getString(resData, "tfa"),
+		DefaultRenewalMode: getString(resData, "defaultRenewalMode"),
+		CreatedAt:          getTime(resData, "crDate"),
+		LastLogin:          getTime(resData, "lastLogin"),
+		LastIP:             getString(resData, "lastIP"),
+	}, nil
+}
+
+// Update changes customer data. The keys of fields are the customer.update parameter
+// names (e.g. "org", "vatNo", "invoicePdf").
+func (s *CustomerService) Update(ctx context.Context, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("no customer fields to update")
+	}
+
+	params := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		params[key] = value
+	}
+
+	_, err := s.client.transport.Call(ctx, "customer.update", s.withTesting(params))
+	return err
+}
+
+// RequestDataExport requests an export of all customer data. The export is listed by
+// ListDownloads once it has been prepared.
+func (s *CustomerService) RequestDataExport(ctx context.Context) error {
+	_, err := s.client.transport.Call(ctx, "customer.requestdataexport", map[string]interface{}{})
+	return err
+}
+
+// ListDownloads returns the files provided for download, optionally limited to a type
+// such as "gdpr"
+func (s *CustomerService) ListDownloads(ctx context.Context, downloadType string) ([]CustomerDownload, error) {
+	params := map[string]interface{}{}
+	if downloadType != "" {
+		params["type"] = downloadType
+	}
+
+	response, err := s.client.transport.Call(ctx, "customer.listdownloads", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var downloads []CustomerDownload
+	if resData := getResData(response); resData != nil {
+		for _, item := range getMaps(resData, "downloads") {
+			downloads = append(downloads, CustomerDownload{
+				Token:     getString(item, "token"),
+				Status:    getString(item, "status"),
+				Created:   getTime(item, "created"),
+				Expires:   getTime(item, "expires"),
+				Downloads: getInt(item, "downloads"),
+			})
+		}
+	}
+
+	return downloads, nil
+}
+
+// DomainDocument returns the PDF document required by the registry for a domain
+func (s *CustomerService) DomainDocument(ctx context.Context, domain string) ([]byte, error) {
+	response, err := s.client.transport.Call(ctx, "pdf.get", map[string]interface{}{
+		"domain": domain,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("no document returned for %s", domain)
+	}
+	return getBase64(resData, "pdf")
+}
+
+// Document returns a generic PDF document of the GDPR category, such as the data
+// processing contract (see CustomerDocuments)
+func (s *CustomerService) Document(ctx context.Context, name string) ([]byte, error) {
+	response, err := s.client.transport.Call(ctx, "pdf.document", map[string]interface{}{
+		"category": "dsgvo",
+		"name":     name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("document %s not found", name)
+	}
+	return getBase64(resData, "pdf")
+}
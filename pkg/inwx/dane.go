@@ -0,0 +1,244 @@
+package inwx
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TLSAName returns the owner name of the TLSA records of a service, e.g.
+// _25._tcp.mail.example.com
+func TLSAName(port int, protocol, host string) string {
+	return fmt.Sprintf("_%d._%s.%s", port, strings.ToLower(protocol), host)
+}
+
+// NewTLSAData computes a TLSA record for a certificate or public key. data is the DER
+// encoded certificate for selector 0 and the DER encoded SubjectPublicKeyInfo for
+// selector 1.
+func NewTLSAData(usage, selector, matchingType int, data []byte) (*TLSAData, error) {
+	var digest []byte
+	switch matchingType {
+	case 0:
+		digest = data
+	case 1:
+		sum := sha256.Sum256(data)
+		digest = sum[:]
+	case 2:
+		sum := sha512.Sum512(data)
+		digest = sum[:]
+	default:
+		return nil, fmt.Errorf("invalid TLSA matching type %d (0 = full, 1 = SHA-256, 2 = SHA-512)", matchingType)
+	}
+
+	d := &TLSAData{Usage: usage, Selector: selector, MatchingType: matchingType, Data: hex.EncodeToString(digest)}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// TLSAFromPEM computes a TLSA record from PEM data. Certificate chains such as
+// fullchain.pem use the first certificate for the end entity usages 1 and 3 and its
+// issuer for the CA usages 0 and 2. A public or private key is accepted with
+// selector 1, e.g. to publish the next key before the certificate is issued.
+func TLSAFromPEM(data []byte, usage, selector, matchingType int) (*TLSAData, error) {
+	var certs []*x509.Certificate
+	var spki []byte
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type == "CERTIFICATE" {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse certificate: %w", err)
+			}
+			certs = append(certs, cert)
+			continue
+		}
+		if spki != nil {
+			continue
+		}
+		pub, err := publicKeyFromPEM(block)
+		if err != nil {
+			return nil, err
+		}
+		if spki, err = x509.MarshalPKIXPublicKey(pub); err != nil {
+			return nil, fmt.Errorf("failed to encode public key: %w", err)
+		}
+	}
+
+	if len(certs) == 0 {
+		switch {
+		case spki == nil:
+			return nil, errors.New("no certificate or key found in PEM data")
+		case selector != 1:
+			return nil, errors.New("a key requires selector 1, selector 0 needs a certificate")
+		}
+		return NewTLSAData(usage, selector, matchingType, spki)
+	}
+
+	cert := certs[0]
+	if usage == 0 || usage == 2 {
+		if len(certs) < 2 {
+			return nil, fmt.Errorf("usage %d needs the issuing CA certificate in the chain", usage)
+		}
+		cert = certs[1]
+	}
+	if selector == 0 {
+		return NewTLSAData(usage, selector, matchingType, cert.Raw)
+	}
+	return NewTLSAData(usage, selector, matchingType, cert.RawSubjectPublicKeyInfo)
+}
+
+// publicKeyFromPEM returns the public key of a PEM encoded public or private key
+func publicKeyFromPEM(block *pem.Block) (interface{}, error) {
+	var priv interface{}
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		priv, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		priv, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		priv, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key: %w", err)
+	}
+
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey, nil
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey, nil
+	case ed25519.PrivateKey:
+		return k.Public(), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", priv)
+	}
+}
+
+// sshfpAlgorithms maps OpenSSH key types to SSHFP algorithm numbers
+var sshfpAlgorithms = map[string]int{
+	"ssh-rsa":             1,
+	"ssh-dss":             2,
+	"ecdsa-sha2-nistp256": 3,
+	"ecdsa-sha2-nistp384": 3,
+	"ecdsa-sha2-nistp521": 3,
+	"ssh-ed25519":         4,
+	"ssh-ed448":           6,
+}
+
+// NewSSHFPData computes the SSHFP record of an SSH public key in wire format
+func NewSSHFPData(algorithm, fingerprintType int, key []byte) (*SSHFPData, error) {
+	var digest []byte
+	switch fingerprintType {
+	case 1:
+		sum := sha1.Sum(key)
+		digest = sum[:]
+	case 2:
+		sum := sha256.Sum256(key)
+		digest = sum[:]
+	default:
+		return nil, fmt.Errorf("invalid SSHFP fingerprint type %d (1 = SHA-1, 2 = SHA-256)", fingerprintType)
+	}
+
+	d := &SSHFPData{Algorithm: algorithm, FingerprintType: fingerprintType, Fingerprint: hex.EncodeToString(digest)}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// SSHFPFromPublicKeys computes SSHFP records from OpenSSH public keys, one per line as
+// in ssh_host_*_key.pub files or the output of ssh-keyscan. A record is returned per
+// key and fingerprint type; keys that appear more than once are included once.
+func SSHFPFromPublicKeys(data []byte, fingerprintTypes ...int) ([]*SSHFPData, error) {
+	var records []*SSHFPData
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// ssh-keyscan prefixes the key with the host name
+		fields := strings.Fields(line)
+		algorithm, i := 0, 0
+		for ; i < len(fields)-1; i++ {
+			if algorithm = sshfpAlgorithms[fields[i]]; algorithm != 0 {
+				break
+			}
+		}
+		if algorithm == 0 {
+			return nil, fmt.Errorf("line %d: no supported SSH public key found", lineNo)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(fields[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid key data: %w", lineNo, err)
+		}
+		if keyType, ok := sshKeyType(key); !ok || keyType != fields[i] {
+			return nil, fmt.Errorf("line %d: key data does not match key type %s", lineNo, fields[i])
+		}
+		if seen[string(key)] {
+			continue
+		}
+		seen[string(key)] = true
+
+		for _, fingerprintType := range fingerprintTypes {
+			record, err := NewSSHFPData(algorithm, fingerprintType, key)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("no SSH public keys found")
+	}
+	return records, nil
+}
+
+// sshKeyType returns the key type stored at the start of an SSH public key in wire
+// format
+func sshKeyType(key []byte) (string, bool) {
+	if len(key) < 4 {
+		return "", false
+	}
+	n := binary.BigEndian.Uint32(key)
+	if uint64(n) > uint64(len(key)-4) {
+		return "", false
+	}
+	return string(key[4 : 4+n]), true
+}
@@ -23,6 +23,9 @@ const (
 	OperationCreate OperationType = "create"
 	OperationUpdate OperationType = "update"
 	OperationDelete OperationType = "delete"
+	// OperationDomainContacts records a change of the contact handles of a domain.
+	// The record only carries the domain name; the previous handles are kept in the context.
+	OperationDomainContacts OperationType = "domain-contacts"
 )
 
 type BackupEntry struct {
@@ -98,6 +101,14 @@ type DNSRecord struct {
 	TTL     int    `json:"ttl"`
 	Prio    int    `json:"prio,omitempty"`
 	Domain  string `json:"domain,omitempty"`
+
+	// Redirect parameters of URL records
+	URLRedirectType        string `json:"url_redirect_type,omitempty"`
+	URLRedirectTitle       string `json:"url_redirect_title,omitempty"`
+	URLRedirectDescription string `json:"url_redirect_description,omitempty"`
+	URLRedirectFavIcon     string `json:"url_redirect_favicon,omitempty"`
+	URLRedirectKeywords    string `json:"url_redirect_keywords,omitempty"`
+	URLAppend              bool   `json:"url_append,omitempty"`
 }
 
 type RecordFilter func(*RecordQuery)
@@ -263,58 +274,16 @@ func (s *DNSService) listRecordsMultipleTypes(ctx context.Context, query *Record
 
 func (s *DNSService) listAllRecords(ctx context.Context, query *RecordQuery) ([]DNSRecord, error) {
 	// First, get list of all domains
-	domainResponse, err := s.client.transport.Call(ctx, "domain.list", map[string]interface{}{})
+	domainList, err := s.client.Domain().List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list domains: %w", err)
 	}
 
 	var allRecords []DNSRecord
 
-	// Extract domains from response
-	var domains []string
-	if resData, ok := domainResponse["resData"].(map[string]interface{}); ok {
-		log.Debug().
-			Interface("resData", resData).
-			Msg("Processing domain list resData")
-
-		if domainList, ok := resData["domain"].([]interface{}); ok {
-			log.Debug().
-				Int("domain_list_length", len(domainList)).
-				Msg("Found domain list")
-
-			for i, d := range domainList {
-				if domain, ok := d.(map[string]interface{}); ok {
-					log.Debug().
-						Int("index", i).
-						Interface("domain_object", domain).
-						Msg("Processing domain object")
-
-					if domainName, ok := domain["domain"].(string); ok {
-						log.Debug().
-							Str("domain_name", domainName).
-							Msg("Found domain name")
-						domains = append(domains, domainName)
-					} else {
-						log.Debug().
-							Interface("domain_field", domain["domain"]).
-							Msg("Domain field is not a string or missing")
-					}
-				} else {
-					log.Debug().
-						Int("index", i).
-						Interface("domain_item", d).
-						Msg("Domain item is not a map")
-				}
-			}
-		} else {
-			log.Debug().
-				Interface("domain_field", resData["domain"]).
-				Msg("Domain field is not an array or missing")
-		}
-	} else {
-		log.Debug().
-			Interface("response", domainResponse).
-			Msg("resData is not a map or missing")
+	domains := make([]string, 0, len(domainList))
+	for _, domain := range domainList {
+		domains = append(domains, domain.Name)
 	}
 
 	log.Debug().
@@ -492,6 +461,7 @@ func (s *DNSService) parseRecordsFromResponse(response map[string]interface{}, d
 				if prio, ok := record["prio"].(float64); ok {
 					dnsRecord.Prio = int(prio)
 				}
+				parseURLRedirectFields(&dnsRecord, record)
 				if recordDomain, ok := record["domain"].(string); ok {
 					dnsRecord.Domain = recordDomain
 				} else {
@@ -512,6 +482,14 @@ func (s *DNSService) parseRecordsFromResponse(response map[string]interface{}, d
 }
 
 func (s *DNSService) CreateRecord(ctx context.Context, record DNSRecord) (*DNSRecord, error) {
+	// Reject malformed content of typed records before the API call
+	if _, err := ParseRecordData(record.Type, record.Content, record.Prio); err != nil {
+		return nil, fmt.Errorf("invalid %s record content: %w", record.Type, err)
+	}
+	if err := normalizeURLRecord(&record); err != nil {
+		return nil, err
+	}
+
 	params := map[string]interface{}{
 		"domain":  record.Domain,
 		"type":    record.Type,
@@ -523,6 +501,7 @@ func (s *DNSService) CreateRecord(ctx context.Context, record DNSRecord) (*DNSRe
 	if record.Prio > 0 {
 		params["prio"] = record.Prio
 	}
+	addURLRedirectParams(params, record)
 
 	// Use atomic backup if available
 	if s.backupStore != nil {
@@ -575,6 +554,25 @@ func (s *DNSService) CreateRecord(ctx context.Context, record DNSRecord) (*DNSRe
 }
 
 func (s *DNSService) UpdateRecord(ctx context.Context, id int, updates DNSRecord) (*DNSRecord, error) {
+	// Reject malformed content, URL targets and redirect parameters of the record the
+	// update ends up with before the API call
+	if updates.HasURLRedirect() || updates.Type != "" || updates.Content != "" {
+		current, err := s.GetRecord(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get record: %w", err)
+		}
+		merged := mergeRecordUpdates(*current, updates)
+		if _, err := ParseRecordData(merged.Type, merged.Content, merged.Prio); err != nil {
+			return nil, fmt.Errorf("invalid %s record content: %w", merged.Type, err)
+		}
+		if err := normalizeURLRecord(&merged); err != nil {
+			return nil, err
+		}
+		if updates.URLRedirectType != "" {
+			updates.URLRedirectType = merged.URLRedirectType
+		}
+	}
+
 	params := map[string]interface{}{
 		"id": id,
 	}
@@ -594,13 +592,86 @@ func (s *DNSService) UpdateRecord(ctx context.Context, id int, updates DNSRecord
 	if updates.Prio > 0 {
 		params["prio"] = updates.Prio
 	}
+	addURLRedirectParams(params, updates)
 
+	if err := s.updateRecord(ctx, id, params); err != nil {
+		return nil, err
+	}
+	return &updates, nil
+}
+
+// mergeRecordUpdates returns the record after a partial update, in which empty
+// values keep the current ones
+func mergeRecordUpdates(current, updates DNSRecord) DNSRecord {
+	merged := current
+	for _, field := range []struct {
+		value  string
+		target *string
+	}{
+		{updates.Name, &merged.Name},
+		{updates.Type, &merged.Type},
+		{updates.Content, &merged.Content},
+		{updates.URLRedirectType, &merged.URLRedirectType},
+		{updates.URLRedirectTitle, &merged.URLRedirectTitle},
+		{updates.URLRedirectDescription, &merged.URLRedirectDescription},
+		{updates.URLRedirectFavIcon, &merged.URLRedirectFavIcon},
+		{updates.URLRedirectKeywords, &merged.URLRedirectKeywords},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+	if updates.TTL > 0 {
+		merged.TTL = updates.TTL
+	}
+	if updates.Prio > 0 {
+		merged.Prio = updates.Prio
+	}
+	merged.URLAppend = merged.URLAppend || updates.URLAppend
+	return merged
+}
+
+// ReplaceRecord sets the content, TTL and priority of a record, and the redirect
+// parameters of URL records, to those of record. Unlike UpdateRecord, zero and empty
+// values are sent as well, e.g. to lower a priority to 0 or to clear a redirect title.
+// The type of record must be set.
+func (s *DNSService) ReplaceRecord(ctx context.Context, id int, record DNSRecord) (*DNSRecord, error) {
+	if _, err := ParseRecordData(record.Type, record.Content, record.Prio); err != nil {
+		return nil, fmt.Errorf("invalid %s record content: %w", record.Type, err)
+	}
+	if err := normalizeURLRecord(&record); err != nil {
+		return nil, err
+	}
+	if record.TTL == 0 {
+		record.TTL = s.defaultTTL
+	}
+
+	params := map[string]interface{}{
+		"id":      id,
+		"content": record.Content,
+		"ttl":     record.TTL,
+		"prio":    record.Prio,
+	}
+	if strings.EqualFold(record.Type, "URL") {
+		setURLRedirectParams(params, record)
+	}
+
+	if err := s.updateRecord(ctx, id, params); err != nil {
+		return nil, err
+	}
+	record.ID = id
+	return &record, nil
+}
+
+// updateRecord calls nameserver.updateRecord, recording the previous state of the
+// record in the backup store if available
+func (s *DNSService) updateRecord(ctx context.Context, id int, params map[string]interface{}) error {
 	// Use atomic backup if available
 	if s.backupStore != nil {
 		// Get the current record state before making changes
 		originalRecord, err := s.GetRecord(ctx, id)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get original record for backup: %w", err)
+			return fmt.Errorf("failed to get original record for backup: %w", err)
 		}
 
 		context := map[string]interface{}{
@@ -613,21 +684,12 @@ func (s *DNSService) UpdateRecord(ctx context.Context, id int, updates DNSRecord
 			_, err := s.client.transport.Call(ctx, "nameserver.updateRecord", params)
 			return err
 		})
-
-		if err != nil {
-			return nil, err
-		}
-
-		return &updates, nil
+		return err
 	}
 
 	// Fallback to non-atomic operation
 	_, err := s.client.transport.Call(ctx, "nameserver.updateRecord", params)
-	if err != nil {
-		return nil, err
-	}
-
-	return &updates, nil
+	return err
 }
 
 // UpdateRecords updates multiple DNS records at once using the API's batch capability.
@@ -657,6 +719,7 @@ func (s *DNSService) UpdateRecords(ctx context.Context, ids []int, updates DNSRe
 	if updates.Prio > 0 {
 		params["prio"] = updates.Prio
 	}
+	addURLRedirectParams(params, updates)
 
 	// Use atomic backup if available
 	if s.backupStore != nil {
@@ -2,53 +2,812 @@ package inwx
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 )
 
+const (
+	// RenewalModeAutoRenew renews the domain automatically at expiration
+	RenewalModeAutoRenew = "AUTORENEW"
+	// RenewalModeAutoDelete deletes the domain at expiration
+	RenewalModeAutoDelete = "AUTODELETE"
+	// RenewalModeAutoExpire lets the domain expire without renewal
+	RenewalModeAutoExpire = "AUTOEXPIRE"
+)
+
+const (
+	// domainPageLimit is the page size used when listing domains
+	domainPageLimit = 250
+	// domainPriceBatchSize is the number of domains per price request
+	domainPriceBatchSize = 50
+)
+
+// RenewalModes lists all renewal modes accepted by the API
+var RenewalModes = []string{RenewalModeAutoRenew, RenewalModeAutoDelete, RenewalModeAutoExpire}
+
 type DomainService struct {
-	client *Client
+	client  *Client
+	testing bool
+}
+
+type DomainOption func(*DomainService)
+
+// WithDomainTesting executes modifying domain calls in the API testing mode,
+// which validates the request without performing it
+func WithDomainTesting(testing bool) DomainOption {
+	return func(s *DomainService) {
+		s.testing = testing
+	}
 }
 
+// Domain is a domain of the account as returned by domain.list
 type Domain struct {
-	Name   string `json:"name"`
-	Status string `json:"status"`
+	RoID         int       `json:"roId"`
+	Name         string    `json:"name"`
+	Status       string    `json:"status"`
+	Period       string    `json:"period,omitempty"`
+	CreatedAt    time.Time `json:"crDate"`
+	ExpiresAt    time.Time `json:"exDate"`
+	RenewalDate  time.Time `json:"reDate"`
+	UpdatedAt    time.Time `json:"upDate"`
+	RenewalMode  string    `json:"renewalMode,omitempty"`
+	TransferLock bool      `json:"transferLock"`
+	Registrant   int       `json:"registrant,omitempty"`
+	Admin        int       `json:"admin,omitempty"`
+	Tech         int       `json:"tech,omitempty"`
+	Billing      int       `json:"billing,omitempty"`
+	Nameservers  []string  `json:"ns,omitempty"`
+}
+
+type DomainFilter func(*DomainQuery)
+
+type DomainQuery struct {
+	Search      []string
+	TLDs        []string
+	Statuses    []string
+	RenewalMode string
+	TagIDs      []int
+}
+
+// WithDomainSearch filters domains by name search strings (wildcards allowed, e.g. "shop*")
+func WithDomainSearch(patterns ...string) DomainFilter {
+	return func(q *DomainQuery) {
+		q.Search = append(q.Search, patterns...)
+	}
+}
+
+// WithTLD filters domains by top-level domain (e.g. "de")
+func WithTLD(tlds ...string) DomainFilter {
+	return func(q *DomainQuery) {
+		q.TLDs = append(q.TLDs, tlds...)
+	}
+}
+
+// WithDomainStatus filters domains by status (e.g. "OK")
+func WithDomainStatus(statuses ...string) DomainFilter {
+	return func(q *DomainQuery) {
+		q.Statuses = append(q.Statuses, statuses...)
+	}
+}
+
+// WithRenewalMode filters domains by renewal mode
+func WithRenewalMode(mode string) DomainFilter {
+	return func(q *DomainQuery) {
+		q.RenewalMode = mode
+	}
+}
+
+// WithTagIDs filters domains by tag IDs
+func WithTagIDs(ids ...int) DomainFilter {
+	return func(q *DomainQuery) {
+		q.TagIDs = append(q.TagIDs, ids...)
+	}
+}
+
+// DomainStats summarizes the domains of an account
+type DomainStats struct {
+	Total         int            `json:"total" yaml:"total"`
+	ByTLD         map[string]int `json:"byTld" yaml:"byTld"`
+	ByStatus      map[string]int `json:"byStatus" yaml:"byStatus"`
+	ByExpiryMonth map[string]int `json:"byExpiryMonth" yaml:"byExpiryMonth"`
+}
+
+// DomainInfo contains the registration details of a domain
+type DomainInfo struct {
+	RoID         int       `json:"roId"`
+	Name         string    `json:"domain"`
+	Status       string    `json:"status"`
+	Period       string    `json:"period,omitempty"`
+	CreatedAt    time.Time `json:"crDate"`
+	ExpiresAt    time.Time `json:"exDate"`
+	RenewalDate  time.Time `json:"reDate"`
+	UpdatedAt    time.Time `json:"upDate"`
+	RenewalMode  string    `json:"renewalMode,omitempty"`
+	TransferLock bool      `json:"transferLock"`
+	Registrant   int       `json:"registrant,omitempty"`
+	Admin        int       `json:"admin,omitempty"`
+	Tech         int       `json:"tech,omitempty"`
+	Billing      int       `json:"billing,omitempty"`
+	Nameservers  []string  `json:"ns,omitempty"`
+}
+
+// DomainContacts holds the contact handle IDs assigned to a domain; zero means unset or unchanged
+type DomainContacts struct {
+	Registrant int `json:"registrant,omitempty"`
+	Admin      int `json:"admin,omitempty"`
+	Tech       int `json:"tech,omitempty"`
+	Billing    int `json:"billing,omitempty"`
+}
+
+// tradeTLDs lists TLDs where a change of the registrant is executed as a chargeable
+// owner change (domain.trade) by the registry instead of a plain update
+var tradeTLDs = map[string]bool{
+	"at": true, "be": true, "eu": true, "fr": true, "it": true, "nl": true,
+	"pm": true, "re": true, "tf": true, "wf": true, "yt": true,
+}
+
+// DomainWhois contains the raw whois output of a domain
+type DomainWhois struct {
+	Domain string `json:"domain" yaml:"domain"`
+	Whois  string `json:"whois" yaml:"whois"`
+}
+
+// DomainLogEntry is a single action logged for a domain
+type DomainLogEntry struct {
+	LogID      int       `json:"logId" yaml:"logId"`
+	Date       time.Time `json:"date" yaml:"date"`
+	Domain     string    `json:"domain" yaml:"domain"`
+	RoID       int       `json:"roId" yaml:"roId"`
+	Status     string    `json:"status" yaml:"status"`
+	Price      float64   `json:"price" yaml:"price"`
+	Invoice    string    `json:"invoice,omitempty" yaml:"invoice,omitempty"`
+	Account    string    `json:"account,omitempty" yaml:"account,omitempty"`
+	CustomerID int       `json:"customerId,omitempty" yaml:"customerId,omitempty"`
+	RemoteAddr string    `json:"remoteAddr,omitempty" yaml:"remoteAddr,omitempty"`
+	UserText   string    `json:"userText,omitempty" yaml:"userText,omitempty"`
+}
+
+// DomainLog is a page of domain log entries
+type DomainLog struct {
+	Count   int              `json:"count" yaml:"count"`
+	Sum     float64          `json:"sum" yaml:"sum"`
+	Page    int              `json:"page" yaml:"page"`
+	Entries []DomainLogEntry `json:"entries" yaml:"entries"`
+}
+
+type DomainLogFilter func(*DomainLogQuery)
+
+type DomainLogQuery struct {
+	Status    string
+	Invoice   string
+	From      time.Time
+	To        time.Time
+	Page      int
+	PageLimit int
+}
+
+// WithLogStatus only returns log entries with the given domain status
+func WithLogStatus(status string) DomainLogFilter {
+	return func(q *DomainLogQuery) {
+		q.Status = status
+	}
+}
+
+// WithLogInvoice only returns log entries belonging to the given invoice
+func WithLogInvoice(invoice string) DomainLogFilter {
+	return func(q *DomainLogQuery) {
+		q.Invoice = invoice
+	}
+}
+
+// WithLogDateRange limits the log to entries between from and to; zero times are ignored
+func WithLogDateRange(from, to time.Time) DomainLogFilter {
+	return func(q *DomainLogQuery) {
+		q.From = from
+		q.To = to
+	}
+}
+
+// WithLogPage selects a page of the log; a limit of 0 uses the API default
+func WithLogPage(page, limit int) DomainLogFilter {
+	return func(q *DomainLogQuery) {
+		q.Page = page
+		q.PageLimit = limit
+	}
+}
+
+// DomainPrice is the price of an action (e.g. renewal) for a specific domain
+type DomainPrice struct {
+	Domain   string  `json:"domain" yaml:"domain"`
+	Type     string  `json:"type" yaml:"type"`
+	Period   string  `json:"period" yaml:"period"`
+	Price    float64 `json:"price" yaml:"price"`
+	Currency string  `json:"currency" yaml:"currency"`
+	Promo    bool    `json:"promo" yaml:"promo"`
+	Premium  bool    `json:"premium" yaml:"premium"`
+}
+
+// DomainRegistration holds the parameters for registering a new domain
+type DomainRegistration struct {
+	Domain      string
+	Period      string
+	Contacts    DomainContacts
+	Nameservers []string
+	RenewalMode string
+}
+
+// DomainRegisterResult is the outcome of a domain registration
+type DomainRegisterResult struct {
+	Domain   string  `json:"domain"`
+	RoID     int     `json:"roId"`
+	Price    float64 `json:"price"`
+	Currency string  `json:"currency"`
+}
+
+// DomainRenewResult contains the outcome of a manual domain renewal
+type DomainRenewResult struct {
+	Domain      string    `json:"domain"`
+	Price       float64   `json:"price"`
+	Currency    string    `json:"currency"`
+	ExpiresAt   time.Time `json:"exDate"`
+	RenewalDate time.Time `json:"reDate"`
 }
 
 // Domain creates a new domain service instance for managing domains
-func (c *Client) Domain() *DomainService {
-	return &DomainService{
+func (c *Client) Domain(opts ...DomainOption) *DomainService {
+	service := &DomainService{
 		client: c,
 	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// IsValidRenewalMode reports whether mode is a renewal mode accepted by the API
+func IsValidRenewalMode(mode string) bool {
+	for _, m := range RenewalModes {
+		if strings.EqualFold(m, mode) {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *DomainService) List(ctx context.Context) ([]Domain, error) {
-	response, err := s.client.transport.Call(ctx, "domain.list", map[string]interface{}{})
+// List returns all domains of the account matching the filters, fetching all pages
+func (s *DomainService) List(ctx context.Context, filters ...DomainFilter) ([]Domain, error) {
+	var domains []Domain
+
+	for page := 1; ; page++ {
+		items, count, err := s.ListPage(ctx, page, domainPageLimit, filters...)
+		if err != nil {
+			return nil, err
+		}
+
+		domains = append(domains, items...)
+		if len(items) < domainPageLimit || len(domains) >= count {
+			break
+		}
+	}
+
+	return domains, nil
+}
+
+// ListPage returns a single page of domains matching the filters and the total number of matches
+func (s *DomainService) ListPage(ctx context.Context, page, limit int, filters ...DomainFilter) ([]Domain, int, error) {
+	query := &DomainQuery{}
+	for _, filter := range filters {
+		filter(query)
+	}
+
+	params := map[string]interface{}{
+		"page":      page,
+		"pagelimit": limit,
+	}
+
+	// The API ORs all patterns in the domain parameter, so the TLDs have to be
+	// folded into the search patterns to restrict the search to those TLDs
+	search := domainSearchPatterns(query.Search, query.TLDs)
+	if len(search) > 0 {
+		params["domain"] = search
+	} else if len(query.Search) > 0 {
+		// No search pattern can match any of the requested TLDs
+		return nil, 0, nil
+	}
+	if len(query.Statuses) > 0 {
+		params["status"] = query.Statuses
+	}
+	if query.RenewalMode != "" {
+		params["renewalMode"] = strings.ToUpper(query.RenewalMode)
+	}
+	if len(query.TagIDs) > 0 {
+		params["tag"] = query.TagIDs
+	}
+
+	response, err := s.client.transport.Call(ctx, "domain.list", params)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	var domains []Domain
+	resData := getResData(response)
+	if resData == nil {
+		return domains, 0, nil
+	}
 
-	// Safety check: ensure response is not nil
-	if response == nil {
-		return domains, nil
-	}
-
-	if resData, ok := response["resData"].(map[string]interface{}); ok && resData != nil {
-		if domainList, ok := resData["domain"].([]interface{}); ok {
-			for _, d := range domainList {
-				if domain, ok := d.(map[string]interface{}); ok {
-					domainObj := Domain{}
-					if name, ok := domain["domain"].(string); ok {
-						domainObj.Name = name
-					}
-					if status, ok := domain["status"].(string); ok {
-						domainObj.Status = status
-					}
-					domains = append(domains, domainObj)
+	for _, item := range getMaps(resData, "domain") {
+		domains = append(domains, parseDomain(item))
+	}
+
+	return domains, getInt(resData, "count"), nil
+}
+
+// Summary counts the listed domains per TLD, status and expiry month. All counts are
+// taken from the same list, so they add up to the total.
+func (s *DomainService) Summary(ctx context.Context, filters ...DomainFilter) (*DomainStats, error) {
+	domains, err := s.List(ctx, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &DomainStats{
+		Total:         len(domains),
+		ByTLD:         make(map[string]int),
+		ByStatus:      make(map[string]int),
+		ByExpiryMonth: make(map[string]int),
+	}
+
+	for _, domain := range domains {
+		stats.ByTLD[domainTLD(domain.Name)]++
+		stats.ByStatus[domain.Status]++
+		if !domain.ExpiresAt.IsZero() {
+			stats.ByExpiryMonth[domain.ExpiresAt.Format("2006-01")]++
+		}
+	}
+
+	return stats, nil
+}
+
+// Info retrieves the registration details of a domain
+func (s *DomainService) Info(ctx context.Context, domain string) (*DomainInfo, error) {
+	response, err := s.client.transport.Call(ctx, "domain.info", map[string]interface{}{
+		"domain": domain,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("no domain information returned for %s", domain)
+	}
+
+	return parseDomainInfo(resData), nil
+}
+
+// Whois retrieves the whois information of a domain
+func (s *DomainService) Whois(ctx context.Context, domain string) (*DomainWhois, error) {
+	response, err := s.client.transport.Call(ctx, "domain.whois", map[string]interface{}{
+		"domain": domain,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DomainWhois{Domain: domain}
+	if resData := getResData(response); resData != nil {
+		result.Whois = getString(resData, "whois")
+	}
+
+	return result, nil
+}
+
+// Log returns a page of the action log of a domain, or of all domains if domain is empty
+func (s *DomainService) Log(ctx context.Context, domain string, filters ...DomainLogFilter) (*DomainLog, error) {
+	query := &DomainLogQuery{Page: 1}
+	for _, filter := range filters {
+		filter(query)
+	}
+
+	params := map[string]interface{}{
+		"page": query.Page,
+	}
+	if domain != "" {
+		params["domain"] = domain
+	}
+	if query.PageLimit > 0 {
+		params["pagelimit"] = query.PageLimit
+	}
+	if query.Status != "" {
+		params["status"] = query.Status
+	}
+	if query.Invoice != "" {
+		params["invoice"] = query.Invoice
+	}
+	if !query.From.IsZero() {
+		params["dateFrom"] = query.From.Format(time.RFC3339)
+	}
+	if !query.To.IsZero() {
+		params["dateTo"] = query.To.Format(time.RFC3339)
+	}
+
+	response, err := s.client.transport.Call(ctx, "domain.log", params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DomainLog{Page: query.Page}
+	if resData := getResData(response); resData != nil {
+		result.Count = getInt(resData, "count")
+		result.Sum = getFloat(resData, "sum")
+		for _, item := range getMaps(resData, "domain") {
+			result.Entries = append(result.Entries, DomainLogEntry{
+				LogID:      getInt(item, "logId"),
+				Date:       getTime(item, "date"),
+				Domain:     getString(item, "domain"),
+				RoID:       getInt(item, "roId"),
+				Status:     getString(item, "status"),
+				Price:      getFloat(item, "price"),
+				Invoice:    getString(item, "invoice"),
+				Account:    getString(item, "account"),
+				CustomerID: getInt(item, "customerId"),
+				RemoteAddr: getString(item, "remoteAddr"),
+				UserText:   getString(item, "userText"),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// Prices returns the price of an action ("reg", "renewal", "transfer", "update", "trade"
+// or "restore") for each of the given domains, keyed by domain name
+func (s *DomainService) Prices(ctx context.Context, priceType string, domains []string) (map[string]DomainPrice, error) {
+	prices := make(map[string]DomainPrice)
+
+	for start := 0; start < len(domains); start += domainPriceBatchSize {
+		end := start + domainPriceBatchSize
+		if end > len(domains) {
+			end = len(domains)
+		}
+		batch := domains[start:end]
+
+		response, err := s.client.transport.Call(ctx, "domain.getdomainprice", map[string]interface{}{
+			"domain":    batch,
+			"pricetype": priceType,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resData := getResData(response)
+		if resData == nil {
+			continue
+		}
+
+		parse := func(domain string, item map[string]interface{}) DomainPrice {
+			return DomainPrice{
+				Domain:   domain,
+				Type:     getString(item, "type"),
+				Period:   getString(item, "period"),
+				Price:    getFloat(item, "price"),
+				Currency: getString(item, "currency"),
+				Promo:    getBool(item, "promo"),
+				Premium:  getBool(item, "premium"),
+			}
+		}
+
+		// Prices are keyed by domain name; a list is returned in request order
+		switch items := resData["domain"].(type) {
+		case map[string]interface{}:
+			for domain, value := range items {
+				if item, ok := value.(map[string]interface{}); ok {
+					prices[strings.ToLower(domain)] = parse(domain, item)
 				}
 			}
+		case []interface{}:
+			for i, value := range items {
+				item, ok := value.(map[string]interface{})
+				if !ok || i >= len(batch) {
+					continue
+				}
+				domain := getString(item, "domain")
+				if domain == "" {
+					domain = batch[i]
+				}
+				prices[strings.ToLower(domain)] = parse(domain, item)
+			}
 		}
 	}
 
-	return domains, nil
+	return prices, nil
+}
+
+// Renew manually renews a domain for the given period (e.g. "1Y").
+// The current expiration date required by the API is looked up automatically.
+func (s *DomainService) Renew(ctx context.Context, domain, period string) (*DomainRenewResult, error) {
+	info, err := s.Info(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain info: %w", err)
+	}
+	if info.ExpiresAt.IsZero() {
+		return nil, fmt.Errorf("domain %s has no expiration date", domain)
+	}
+
+	params := s.withTesting(map[string]interface{}{
+		"domain":     domain,
+		"period":     period,
+		"expiration": info.ExpiresAt.Format("2006-01-02"),
+	})
+
+	response, err := s.client.transport.Call(ctx, "domain.renew", params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DomainRenewResult{Domain: domain}
+	if resData := getResData(response); resData != nil {
+		result.Price = getFloat(resData, "price")
+		result.Currency = getString(resData, "currency")
+		result.ExpiresAt = getTime(resData, "exDate")
+		result.RenewalDate = getTime(resData, "reDate")
+	}
+
+	return result, nil
+}
+
+// Restore restores an expired or deleted domain, optionally setting a new renewal mode
+func (s *DomainService) Restore(ctx context.Context, domain, renewalMode string) error {
+	params := s.withTesting(map[string]interface{}{
+		"domain": domain,
+	})
+	if renewalMode != "" {
+		params["renewalMode"] = strings.ToUpper(renewalMode)
+	}
+
+	_, err := s.client.transport.Call(ctx, "domain.restore", params)
+	return err
+}
+
+// Delete deletes a domain, either immediately or at the scheduled time if not zero
+func (s *DomainService) Delete(ctx context.Context, domain string, scheduledAt time.Time) error {
+	params := s.withTesting(map[string]interface{}{
+		"domain": domain,
+	})
+	if !scheduledAt.IsZero() {
+		params["scDate"] = scheduledAt.Format(time.RFC3339)
+	}
+
+	_, err := s.client.transport.Call(ctx, "domain.delete", params)
+	return err
+}
+
+// SetClientHold sets the clientHold status, which removes the domain from the zone
+func (s *DomainService) SetClientHold(ctx context.Context, domain string) error {
+	_, err := s.client.transport.Call(ctx, "domain.setClientHold", s.withTesting(map[string]interface{}{
+		"domain": domain,
+	}))
+	return err
+}
+
+// RemoveClientHold removes a clientHold status previously set by the account
+func (s *DomainService) RemoveClientHold(ctx context.Context, domain string) error {
+	_, err := s.client.transport.Call(ctx, "domain.removeClientHold", s.withTesting(map[string]interface{}{
+		"domain": domain,
+	}))
+	return err
+}
+
+// SetRenewalMode changes the renewal mode of a domain via domain.update
+func (s *DomainService) SetRenewalMode(ctx context.Context, domain, mode string) error {
+	if !IsValidRenewalMode(mode) {
+		return fmt.Errorf("invalid renewal mode %q (must be one of %s)", mode, strings.Join(RenewalModes, ", "))
+	}
+
+	_, err := s.client.transport.Call(ctx, "domain.update", s.withTesting(map[string]interface{}{
+		"domain":      domain,
+		"renewalMode": strings.ToUpper(mode),
+	}))
+	return err
+}
+
+// Contacts returns the contact handles currently assigned to the domain
+func (info *DomainInfo) Contacts() DomainContacts {
+	return DomainContacts{
+		Registrant: info.Registrant,
+		Admin:      info.Admin,
+		Tech:       info.Tech,
+		Billing:    info.Billing,
+	}
+}
+
+// OwnerChangeRequiresTrade reports whether changing the registrant of domain is
+// handled as an owner change (domain.trade) by the registry
+func OwnerChangeRequiresTrade(domain string) bool {
+	return tradeTLDs[domainTLD(domain)]
+}
+
+// SetContacts assigns new contact handles to a domain via domain.update.
+// Only the non-zero handles in contacts are changed.
+func (s *DomainService) SetContacts(ctx context.Context, domain string, contacts DomainContacts) error {
+	params := s.withTesting(map[string]interface{}{
+		"domain": domain,
+	})
+
+	handles := map[string]int{
+		"registrant": contacts.Registrant,
+		"admin":      contacts.Admin,
+		"tech":       contacts.Tech,
+		"billing":    contacts.Billing,
+	}
+	changed := 0
+	for key, id := range handles {
+		if id > 0 {
+			params[key] = id
+			changed++
+		}
+	}
+	if changed == 0 {
+		return fmt.Errorf("no contact handles specified")
+	}
+
+	_, err := s.client.transport.Call(ctx, "domain.update", params)
+	return err
+}
+
+// SetNameservers replaces the nameservers of a domain via domain.update
+func (s *DomainService) SetNameservers(ctx context.Context, domain string, nameservers []string) error {
+	if len(nameservers) == 0 {
+		return fmt.Errorf("no nameservers specified")
+	}
+
+	_, err := s.client.transport.Call(ctx, "domain.update", s.withTesting(map[string]interface{}{
+		"domain": domain,
+		"ns":     nameservers,
+	}))
+	return err
+}
+
+// Register registers a new domain via domain.create
+func (s *DomainService) Register(ctx context.Context, registration DomainRegistration) (*DomainRegisterResult, error) {
+	if registration.Contacts.Registrant == 0 {
+		return nil, fmt.Errorf("a registrant contact handle is required")
+	}
+	if registration.RenewalMode != "" && !IsValidRenewalMode(registration.RenewalMode) {
+		return nil, fmt.Errorf("invalid renewal mode %q (must be one of %s)", registration.RenewalMode, strings.Join(RenewalModes, ", "))
+	}
+
+	params := s.withTesting(map[string]interface{}{
+		"domain":     registration.Domain,
+		"registrant": registration.Contacts.Registrant,
+	})
+	if registration.Period != "" {
+		params["period"] = registration.Period
+	}
+	for key, id := range map[string]int{
+		"admin":   registration.Contacts.Admin,
+		"tech":    registration.Contacts.Tech,
+		"billing": registration.Contacts.Billing,
+	} {
+		if id > 0 {
+			params[key] = id
+		}
+	}
+	if len(registration.Nameservers) > 0 {
+		params["ns"] = registration.Nameservers
+	}
+	if registration.RenewalMode != "" {
+		params["renewalMode"] = strings.ToUpper(registration.RenewalMode)
+	}
+
+	response, err := s.client.transport.Call(ctx, "domain.create", params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DomainRegisterResult{Domain: registration.Domain}
+	if resData := getResData(response); resData != nil {
+		result.RoID = getInt(resData, "roId")
+		result.Price = getFloat(resData, "price")
+		result.Currency = getString(resData, "currency")
+	}
+
+	return result, nil
+}
+
+// withTesting adds the testing flag to params if the service runs in testing mode
+func (s *DomainService) withTesting(params map[string]interface{}) map[string]interface{} {
+	if s.testing {
+		params["testing"] = true
+	}
+	return params
+}
+
+// parseDomain converts a domain object from domain.list
+func parseDomain(data map[string]interface{}) Domain {
+	info := parseDomainInfo(data)
+	return Domain{
+		RoID:         info.RoID,
+		Name:         info.Name,
+		Status:       info.Status,
+		Period:       info.Period,
+		CreatedAt:    info.CreatedAt,
+		ExpiresAt:    info.ExpiresAt,
+		RenewalDate:  info.RenewalDate,
+		UpdatedAt:    info.UpdatedAt,
+		RenewalMode:  info.RenewalMode,
+		TransferLock: info.TransferLock,
+		Registrant:   info.Registrant,
+		Admin:        info.Admin,
+		Tech:         info.Tech,
+		Billing:      info.Billing,
+		Nameservers:  info.Nameservers,
+	}
+}
+
+// domainSearchPatterns combines search patterns and TLDs into domain.list patterns
+// that match a domain only if it matches a search pattern and has one of the TLDs
+func domainSearchPatterns(search, tlds []string) []string {
+	if len(tlds) == 0 {
+		return search
+	}
+
+	var suffixes []string
+	for _, tld := range tlds {
+		suffixes = append(suffixes, "."+strings.TrimPrefix(strings.ToLower(tld), "."))
+	}
+
+	if len(search) == 0 {
+		patterns := make([]string, 0, len(suffixes))
+		for _, suffix := range suffixes {
+			patterns = append(patterns, "*"+suffix)
+		}
+		return patterns
+	}
+
+	var patterns []string
+	for _, pattern := range search {
+		for _, suffix := range suffixes {
+			switch {
+			case strings.HasSuffix(strings.ToLower(pattern), suffix):
+				patterns = append(patterns, pattern)
+			case strings.HasSuffix(pattern, "*"):
+				patterns = append(patterns, pattern+suffix)
+			}
+		}
+	}
+	return patterns
+}
+
+// domainTLD returns the last label of a domain name
+func domainTLD(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if i := strings.LastIndex(domain, "."); i >= 0 {
+		return domain[i+1:]
+	}
+	return domain
+}
+
+// parseDomainInfo converts a domain object from domain.info or domain.list
+func parseDomainInfo(data map[string]interface{}) *DomainInfo {
+	return &DomainInfo{
+		RoID:         getInt(data, "roId"),
+		Name:         getString(data, "domain"),
+		Status:       getString(data, "status"),
+		Period:       getString(data, "period"),
+		CreatedAt:    getTime(data, "crDate"),
+		ExpiresAt:    getTime(data, "exDate"),
+		RenewalDate:  getTime(data, "reDate"),
+		UpdatedAt:    getTime(data, "upDate"),
+		RenewalMode:  getString(data, "renewalMode"),
+		TransferLock: getBool(data, "transferLock"),
+		Registrant:   getInt(data, "registrant"),
+		Admin:        getInt(data, "admin"),
+		Tech:         getInt(data, "tech"),
+		Billing:      getInt(data, "billing"),
+		Nameservers:  getStrings(data, "ns"),
+	}
 }
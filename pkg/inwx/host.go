@@ -0,0 +1,183 @@
+package inwx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// hostPageLimit is the page size used when listing hosts
+const hostPageLimit = 100
+
+type HostService struct {
+	client  *Client
+	testing bool
+}
+
+type HostOption func(*HostService)
+
+// WithHostTesting executes modifying host calls in the API testing mode
+func WithHostTesting(testing bool) HostOption {
+	return func(s *HostService) {
+		s.testing = testing
+	}
+}
+
+// Host is a host object registered at the registry, providing the glue records
+// for nameservers that live inside the domain they serve
+type Host struct {
+	ROID     int      `json:"roId"`
+	Hostname string   `json:"hostname"`
+	IPs      []string `json:"ip"`
+	Status   string   `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// Host creates a new host service instance for managing glue records
+func (c *Client) Host(opts ...HostOption) *HostService {
+	service := &HostService{
+		client: c,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+func (s *HostService) withTesting(params map[string]interface{}) map[string]interface{} {
+	if s.testing {
+		params["testing"] = true
+	}
+	return params
+}
+
+// Check reports whether a hostname is available for registration as host object
+func (s *HostService) Check(ctx context.Context, hostname string) (bool, error) {
+	response, err := s.client.transport.Call(ctx, "host.check", map[string]interface{}{
+		"hostname": hostname,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if resData := getResData(response); resData != nil {
+		return getBool(resData, "avail"), nil
+	}
+
+	return false, nil
+}
+
+// List returns all hosts, optionally filtered by a hostname search string with wildcards
+func (s *HostService) List(ctx context.Context, search string) ([]Host, error) {
+	var hosts []Host
+
+	for page := 1; ; page++ {
+		params := map[string]interface{}{
+			"page":      page,
+			"pagelimit": hostPageLimit,
+		}
+		if search != "" {
+			params["search"] = search
+		}
+
+		response, err := s.client.transport.Call(ctx, "host.list", params)
+		if err != nil {
+			return nil, err
+		}
+
+		resData := getResData(response)
+		if resData == nil {
+			break
+		}
+
+		items := getMaps(resData, "host")
+		for _, item := range items {
+			hosts = append(hosts, parseHost(item))
+		}
+
+		if len(items) < hostPageLimit || len(hosts) >= getInt(resData, "count") {
+			break
+		}
+	}
+
+	return hosts, nil
+}
+
+// Info retrieves the details of a host
+func (s *HostService) Info(ctx context.Context, hostname string) (*Host, error) {
+	response, err := s.client.transport.Call(ctx, "host.info", map[string]interface{}{
+		"hostname": hostname,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("host %s not found", hostname)
+	}
+
+	host := parseHost(resData)
+	return &host, nil
+}
+
+// Create registers a host with its IP addresses and returns its repository object ID
+func (s *HostService) Create(ctx context.Context, hostname string, ips []string) (int, error) {
+	if len(ips) == 0 {
+		return 0, fmt.Errorf("at least one IP address is required")
+	}
+
+	response, err := s.client.transport.Call(ctx, "host.create", s.withTesting(map[string]interface{}{
+		"hostname": hostname,
+		"ip":       ips,
+	}))
+	if err != nil {
+		return 0, err
+	}
+
+	if resData := getResData(response); resData != nil {
+		return getInt(resData, "roId"), nil
+	}
+
+	return 0, nil
+}
+
+// Update replaces the IP addresses of a host
+func (s *HostService) Update(ctx context.Context, hostname string, ips []string) error {
+	if len(ips) == 0 {
+		return fmt.Errorf("at least one IP address is required")
+	}
+
+	_, err := s.client.transport.Call(ctx, "host.update", s.withTesting(map[string]interface{}{
+		"hostname": hostname,
+		"ip":       ips,
+	}))
+	return err
+}
+
+// Delete deletes a host; hosts still used as nameserver of a domain cannot be deleted
+func (s *HostService) Delete(ctx context.Context, hostname string) error {
+	_, err := s.client.transport.Call(ctx, "host.delete", s.withTesting(map[string]interface{}{
+		"hostname": hostname,
+	}))
+	return err
+}
+
+func parseHost(item map[string]interface{}) Host {
+	host := Host{
+		ROID:     getInt(item, "roId"),
+		Hostname: getString(item, "hostname"),
+		IPs:      getStrings(item, "ip"),
+		Status:   getString(item, "status"),
+	}
+
+	// A single address may be returned as plain string
+	if len(host.IPs) == 0 {
+		host.IPs = strings.FieldsFunc(getString(item, "ip"), func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+	}
+
+	return host
+}
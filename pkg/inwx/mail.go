@@ -0,0 +1,557 @@
+package inwx
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DMARCName is the record name of the DMARC policy of a domain
+const DMARCName = "_dmarc"
+
+// DKIMSuffix is the label below which DKIM keys are published
+const DKIMSuffix = "_domainkey"
+
+// parseTags parses semicolon separated tag=value lists used by DKIM and DMARC
+func parseTags(value string) ([]string, map[string]string, error) {
+	var order []string
+	tags := make(map[string]string)
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid tag %q", part)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, exists := tags[name]; exists {
+			return nil, nil, fmt.Errorf("duplicate tag %q", name)
+		}
+		order = append(order, name)
+		tags[name] = strings.TrimSpace(val)
+	}
+	return order, tags, nil
+}
+
+// DKIMKey describes a DKIM public key published in DNS
+type DKIMKey struct {
+	Selector string `json:"selector" yaml:"selector"`
+	KeyType  string `json:"key_type" yaml:"key_type"`
+	Bits     int    `json:"bits,omitempty" yaml:"bits,omitempty"`
+	Revoked  bool   `json:"revoked,omitempty" yaml:"revoked,omitempty"`
+	Testing  bool   `json:"testing,omitempty" yaml:"testing,omitempty"`
+	// Target is set if the selector is delegated to a provider with a CNAME
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+// ParseDKIM parses a DKIM key record
+func ParseDKIM(value string) (*DKIMKey, error) {
+	order, tags, err := parseTags(value)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := tags["v"]; ok && (v != "DKIM1" || order[0] != "v") {
+		return nil, errors.New("v=DKIM1 must be the first tag")
+	}
+	p, ok := tags["p"]
+	if !ok {
+		return nil, errors.New("missing public key (p=)")
+	}
+
+	key := &DKIMKey{KeyType: "rsa"}
+	if k, ok := tags["k"]; ok {
+		key.KeyType = strings.ToLower(k)
+	}
+	for _, flag := range strings.Split(tags["t"], ":") {
+		if strings.TrimSpace(flag) == "y" {
+			key.Testing = true
+		}
+	}
+
+	p = strings.Join(strings.Fields(p), "")
+	if p == "" {
+		key.Revoked = true
+		return key, nil
+	}
+
+	der, err := base64.StdEncoding.DecodeString(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key encoding: %w", err)
+	}
+
+	switch key.KeyType {
+	case "rsa":
+		pub, err := x509.ParsePKIXPublicKey(der)
+		if err != nil {
+			// Some signers publish the bare PKCS#1 key
+			rsaPub, pkcs1Err := x509.ParsePKCS1PublicKey(der)
+			if pkcs1Err != nil {
+				return nil, fmt.Errorf("invalid RSA public key: %w", err)
+			}
+			pub = rsaPub
+		}
+		rsaPub, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("public key is not an RSA key")
+		}
+		key.Bits = rsaPub.N.BitLen()
+	case "ed25519":
+		if len(der) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 public key length %d", len(der))
+		}
+		key.Bits = 256
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", key.KeyType)
+	}
+
+	return key, nil
+}
+
+// DKIMRecordFromPEM builds the DKIM record value for a PEM encoded public key. Private
+// keys are accepted as well, only their public part is published.
+func DKIMRecordFromPEM(data []byte) (string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", errors.New("no PEM data found")
+	}
+
+	pub, err := publicKeyFromPEM(block)
+	if err != nil {
+		return "", err
+	}
+
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 1024 {
+			return "", fmt.Errorf("RSA key with %d bits is too weak for DKIM", k.N.BitLen())
+		}
+		der, err := x509.MarshalPKIXPublicKey(k)
+		if err != nil {
+			return "", err
+		}
+		return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der), nil
+	case ed25519.PublicKey:
+		return "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(k), nil
+	default:
+		return "", fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+// DMARCPolicy is a parsed DMARC record
+type DMARCPolicy struct {
+	Policy          string   `json:"policy" yaml:"policy"`
+	SubdomainPolicy string   `json:"subdomain_policy,omitempty" yaml:"subdomain_policy,omitempty"`
+	Percent         int      `json:"percent" yaml:"percent"`
+	RUA             []string `json:"rua,omitempty" yaml:"rua,omitempty"`
+	RUF             []string `json:"ruf,omitempty" yaml:"ruf,omitempty"`
+	ADKIM           string   `json:"adkim,omitempty" yaml:"adkim,omitempty"`
+	ASPF            string   `json:"aspf,omitempty" yaml:"aspf,omitempty"`
+	FailureOptions  string   `json:"fo,omitempty" yaml:"fo,omitempty"`
+}
+
+// IsDMARC reports whether a TXT value is a DMARC record
+func IsDMARC(value string) bool {
+	value = strings.TrimSpace(value)
+	return len(value) >= 8 && strings.EqualFold(strings.ReplaceAll(value[:8], " ", ""), "v=DMARC1")
+}
+
+// ParseDMARC parses a DMARC record
+func ParseDMARC(value string) (*DMARCPolicy, error) {
+	order, tags, err := parseTags(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(order) == 0 || order[0] != "v" || tags["v"] != "DMARC1" {
+		return nil, errors.New("v=DMARC1 must be the first tag")
+	}
+	if _, ok := tags["p"]; !ok {
+		return nil, errors.New("missing policy (p=)")
+	}
+
+	policy := &DMARCPolicy{
+		Policy:          strings.ToLower(tags["p"]),
+		SubdomainPolicy: strings.ToLower(tags["sp"]),
+		Percent:         100,
+		ADKIM:           strings.ToLower(tags["adkim"]),
+		ASPF:            strings.ToLower(tags["aspf"]),
+		FailureOptions:  tags["fo"],
+	}
+	if pct, ok := tags["pct"]; ok {
+		if policy.Percent, err = strconv.Atoi(pct); err != nil {
+			return nil, fmt.Errorf("invalid pct %q", pct)
+		}
+	}
+	for _, uri := range strings.Split(tags["rua"], ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			policy.RUA = append(policy.RUA, uri)
+		}
+	}
+	for _, uri := range strings.Split(tags["ruf"], ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			policy.RUF = append(policy.RUF, uri)
+		}
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Validate checks the values of the policy
+func (p *DMARCPolicy) Validate() error {
+	validPolicy := func(v string) bool {
+		return v == "none" || v == "quarantine" || v == "reject"
+	}
+	if !validPolicy(p.Policy) {
+		return fmt.Errorf("invalid policy %q (must be none, quarantine or reject)", p.Policy)
+	}
+	if p.SubdomainPolicy != "" && !validPolicy(p.SubdomainPolicy) {
+		return fmt.Errorf("invalid subdomain policy %q (must be none, quarantine or reject)", p.SubdomainPolicy)
+	}
+	if p.Percent < 0 || p.Percent > 100 {
+		return fmt.Errorf("invalid percentage %d (must be between 0 and 100)", p.Percent)
+	}
+	for name, v := range map[string]string{"adkim": p.ADKIM, "aspf": p.ASPF} {
+		if v != "" && v != "r" && v != "s" {
+			return fmt.Errorf("invalid %s %q (must be r or s)", name, v)
+		}
+	}
+	for _, uri := range append(append([]string{}, p.RUA...), p.RUF...) {
+		if !strings.HasPrefix(strings.ToLower(uri), "mailto:") || !strings.Contains(uri, "@") {
+			return fmt.Errorf("invalid report address %q (expected mailto:user@domain)", uri)
+		}
+	}
+	return nil
+}
+
+// String returns the DMARC record value. Tags with default values are left out.
+func (p *DMARCPolicy) String() string {
+	parts := []string{"v=DMARC1", "p=" + p.Policy}
+	if p.SubdomainPolicy != "" {
+		parts = append(parts, "sp="+p.SubdomainPolicy)
+	}
+	if p.Percent != 100 {
+		parts = append(parts, "pct="+strconv.Itoa(p.Percent))
+	}
+	if len(p.RUA) > 0 {
+		parts = append(parts, "rua="+strings.Join(p.RUA, ","))
+	}
+	if len(p.RUF) > 0 {
+		parts = append(parts, "ruf="+strings.Join(p.RUF, ","))
+	}
+	if p.ADKIM != "" {
+		parts = append(parts, "adkim="+p.ADKIM)
+	}
+	if p.ASPF != "" {
+		parts = append(parts, "aspf="+p.ASPF)
+	}
+	if p.FailureOptions != "" {
+		parts = append(parts, "fo="+p.FailureOptions)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// MailAudit contains the mail authentication setup of a domain and its issues
+type MailAudit struct {
+	Domain  string
+	SPF     *SPFNode
+	DKIM    []DKIMKey
+	DMARC   *DMARCPolicy
+	Issues  []ValidationIssue
+	Summary ValidationSummary
+}
+
+// AuditMail checks the SPF, DKIM and DMARC records of a domain. Includes, delegated
+// DKIM selectors and external report addresses are resolved with the resolver.
+func (s *DNSService) AuditMail(ctx context.Context, domain string, resolver MailResolver) (*MailAudit, error) {
+	log.Debug().Str("domain", domain).Msg("Auditing mail records")
+
+	records, err := s.ListRecords(ctx, WithDomainFilter(domain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records: %w", err)
+	}
+
+	audit := &MailAudit{Domain: domain}
+	s.auditSPF(ctx, audit, records, resolver)
+	s.auditDKIM(ctx, audit, records, resolver)
+	s.auditDMARC(ctx, audit, records, resolver)
+	audit.Summary = summarizeIssues(audit.Issues)
+
+	log.Debug().
+		Int("errors", audit.Summary.Errors).
+		Int("warnings", audit.Summary.Warnings).
+		Msg("Mail audit complete")
+
+	return audit, nil
+}
+
+// recordIssue returns an issue that refers to a record
+func recordIssue(record DNSRecord, issue ValidationIssue) ValidationIssue {
+	rec := record
+	issue.RecordID = record.ID
+	issue.Record = &rec
+	return issue
+}
+
+func (s *DNSService) auditSPF(ctx context.Context, audit *MailAudit, records []DNSRecord, resolver MailResolver) {
+	domain := audit.Domain
+
+	var spf []DNSRecord
+	hasMX := false
+	for _, r := range records {
+		if r.Name != "@" {
+			continue
+		}
+		switch {
+		case r.Type == "MX":
+			hasMX = true
+		case r.Type == "TXT" && IsSPF(TXTValue(r.Content)):
+			spf = append(spf, r)
+		}
+	}
+
+	switch {
+	case len(spf) == 0 && hasMX:
+		audit.Issues = append(audit.Issues, ValidationIssue{
+			Severity:   "warning",
+			Type:       "spf_missing",
+			Message:    fmt.Sprintf("No SPF record found for %s", domain),
+			Suggestion: "Publish an SPF record listing your mail servers, e.g. \"v=spf1 mx -all\"",
+		})
+		return
+	case len(spf) == 0:
+		audit.Issues = append(audit.Issues, ValidationIssue{
+			Severity:   "info",
+			Type:       "spf_missing",
+			Message:    fmt.Sprintf("No SPF record found for %s, which does not receive mail", domain),
+			Suggestion: "Publish \"v=spf1 -all\" to prevent others from sending mail in its name",
+		})
+		return
+	case len(spf) > 1:
+		for _, r := range spf[1:] {
+			audit.Issues = append(audit.Issues, recordIssue(r, ValidationIssue{
+				Severity:   "error",
+				Type:       "spf_multiple",
+				Message:    fmt.Sprintf("%s has %d SPF records, receivers treat this as permerror", domain, len(spf)),
+				Suggestion: "Merge the SPF records into a single record",
+			}))
+		}
+	}
+
+	value := TXTValue(spf[0].Content)
+	node, issues := ExpandSPF(ctx, resolver, domain, value)
+	audit.SPF = node
+	for _, issue := range issues {
+		audit.Issues = append(audit.Issues, recordIssue(spf[0], issue))
+	}
+	if node.Error == "" {
+		for _, issue := range checkSPFPolicy(domain, node.Terms) {
+			audit.Issues = append(audit.Issues, recordIssue(spf[0], issue))
+		}
+	}
+}
+
+func (s *DNSService) auditDKIM(ctx context.Context, audit *MailAudit, records []DNSRecord, resolver MailResolver) {
+	for _, r := range records {
+		selector, ok := strings.CutSuffix(r.Name, "."+DKIMSuffix)
+		if !ok || (r.Type != "TXT" && r.Type != "CNAME") {
+			continue
+		}
+
+		var value string
+		target := ""
+		if r.Type == "CNAME" {
+			target = strings.TrimSuffix(r.Content, ".")
+			values, err := resolver.LookupTXT(ctx, s.getFullName(r))
+			if err != nil || len(values) == 0 {
+				audit.DKIM = append(audit.DKIM, DKIMKey{Selector: selector, Target: target})
+				audit.Issues = append(audit.Issues, recordIssue(r, ValidationIssue{
+					Severity:   "warning",
+					Type:       "dkim_lookup_failed",
+					Message:    fmt.Sprintf("DKIM selector %s is delegated to %s, which does not resolve to a key", selector, target),
+					Suggestion: "Check the DKIM setup with your mail provider or remove the stale selector",
+				}))
+				continue
+			}
+			value = strings.Join(values, "")
+		} else {
+			value = TXTValue(r.Content)
+		}
+
+		key, err := ParseDKIM(value)
+		if err != nil {
+			audit.Issues = append(audit.Issues, recordIssue(r, ValidationIssue{
+				Severity:   "error",
+				Type:       "dkim_syntax",
+				Message:    fmt.Sprintf("DKIM key of selector %s is invalid: %v", selector, err),
+				Suggestion: "Regenerate the record, e.g. with 'inwx mail dkim add'",
+			}))
+			continue
+		}
+		key.Selector = selector
+		key.Target = target
+		audit.DKIM = append(audit.DKIM, *key)
+
+		switch {
+		case key.Revoked:
+			audit.Issues = append(audit.Issues, recordIssue(r, ValidationIssue{
+				Severity:   "info",
+				Type:       "dkim_revoked",
+				Message:    fmt.Sprintf("DKIM key of selector %s is revoked", selector),
+				Suggestion: "Remove the record once no more mail signed with it is in transit",
+			}))
+		case key.KeyType == "rsa" && key.Bits < 1024:
+			audit.Issues = append(audit.Issues, recordIssue(r, ValidationIssue{
+				Severity:   "error",
+				Type:       "dkim_weak_key",
+				Message:    fmt.Sprintf("DKIM key of selector %s has only %d bits, receivers ignore it", selector, key.Bits),
+				Suggestion: "Rotate to a 2048 bit RSA key",
+			}))
+		case key.KeyType == "rsa" && key.Bits < 2048:
+			audit.Issues = append(audit.Issues, recordIssue(r, ValidationIssue{
+				Severity:   "warning",
+				Type:       "dkim_weak_key",
+				Message:    fmt.Sprintf("DKIM key of selector %s has only %d bits", selector, key.Bits),
+				Suggestion: "Rotate to a 2048 bit RSA key",
+			}))
+		}
+		if key.Testing {
+			audit.Issues = append(audit.Issues, recordIssue(r, ValidationIssue{
+				Severity:   "info",
+				Type:       "dkim_testing",
+				Message:    fmt.Sprintf("DKIM key of selector %s is in testing mode (t=y)", selector),
+				Suggestion: "Remove t=y once signing works",
+			}))
+		}
+		if r.Type == "TXT" && len(value) > MaxTXTStringLength && !strings.HasPrefix(strings.TrimSpace(r.Content), `"`) {
+			audit.Issues = append(audit.Issues, recordIssue(r, ValidationIssue{
+				Severity:   "warning",
+				Type:       "txt_too_long",
+				Message:    fmt.Sprintf("DKIM record of selector %s is longer than %d characters and not split", selector, MaxTXTStringLength),
+				Suggestion: "Split the value into quoted strings, 'inwx mail dkim add' does this automatically",
+			}))
+		}
+	}
+
+	if len(audit.DKIM) == 0 {
+		audit.Issues = append(audit.Issues, ValidationIssue{
+			Severity:   "info",
+			Type:       "dkim_missing",
+			Message:    fmt.Sprintf("No DKIM keys found below %s.%s", DKIMSuffix, audit.Domain),
+			Suggestion: "Publish the key of your mail provider with 'inwx mail dkim add'",
+		})
+	}
+}
+
+func (s *DNSService) auditDMARC(ctx context.Context, audit *MailAudit, records []DNSRecord, resolver MailResolver) {
+	domain := audit.Domain
+
+	var dmarc []DNSRecord
+	for _, r := range records {
+		if r.Name == DMARCName && r.Type == "TXT" && IsDMARC(TXTValue(r.Content)) {
+			dmarc = append(dmarc, r)
+		}
+	}
+
+	switch {
+	case len(dmarc) == 0:
+		audit.Issues = append(audit.Issues, ValidationIssue{
+			Severity:   "warning",
+			Type:       "dmarc_missing",
+			Message:    fmt.Sprintf("No DMARC policy found for %s", domain),
+			Suggestion: fmt.Sprintf("Start monitoring with 'inwx mail dmarc set -d %s --policy none --rua mailto:dmarc@%s'", domain, domain),
+		})
+		return
+	case len(dmarc) > 1:
+		audit.Issues = append(audit.Issues, recordIssue(dmarc[1], ValidationIssue{
+			Severity:   "error",
+			Type:       "dmarc_multiple",
+			Message:    fmt.Sprintf("%s has %d DMARC records, receivers ignore all of them", domain, len(dmarc)),
+			Suggestion: "Remove all but one DMARC record",
+		}))
+		return
+	}
+
+	record := dmarc[0]
+	policy, err := ParseDMARC(TXTValue(record.Content))
+	if err != nil {
+		audit.Issues = append(audit.Issues, recordIssue(record, ValidationIssue{
+			Severity:   "error",
+			Type:       "dmarc_syntax",
+			Message:    fmt.Sprintf("DMARC record of %s is invalid: %v", domain, err),
+			Suggestion: "Regenerate the record with 'inwx mail dmarc set'",
+		}))
+		return
+	}
+	audit.DMARC = policy
+
+	if policy.Policy == "none" {
+		audit.Issues = append(audit.Issues, recordIssue(record, ValidationIssue{
+			Severity:   "warning",
+			Type:       "dmarc_policy_none",
+			Message:    fmt.Sprintf("DMARC policy of %s is none and only monitors", domain),
+			Suggestion: "Move to quarantine or reject once the reports show no legitimate failures",
+		}))
+	}
+	if policy.Percent < 100 && policy.Policy != "none" {
+		audit.Issues = append(audit.Issues, recordIssue(record, ValidationIssue{
+			Severity:   "info",
+			Type:       "dmarc_partial",
+			Message:    fmt.Sprintf("DMARC policy of %s applies to %d%% of failing mail", domain, policy.Percent),
+			Suggestion: "Raise pct to 100 once the rollout is complete",
+		}))
+	}
+	if len(policy.RUA) == 0 {
+		audit.Issues = append(audit.Issues, recordIssue(record, ValidationIssue{
+			Severity:   "info",
+			Type:       "dmarc_no_reports",
+			Message:    fmt.Sprintf("DMARC policy of %s requests no aggregate reports", domain),
+			Suggestion: "Add rua=mailto:... to receive reports about failing mail",
+		}))
+	}
+
+	for _, uri := range append(append([]string{}, policy.RUA...), policy.RUF...) {
+		_, address, _ := strings.Cut(uri, ":")
+		address, _, _ = strings.Cut(address, "!") // size limit suffix
+		_, reportDomain, _ := strings.Cut(address, "@")
+		reportDomain = strings.ToLower(reportDomain)
+		if reportDomain == domain || strings.HasSuffix(reportDomain, "."+domain) {
+			continue
+		}
+
+		// External destinations must authorize the reports (RFC 7489 section 7.1)
+		name := domain + "._report._dmarc." + reportDomain
+		values, err := resolver.LookupTXT(ctx, name)
+		var dnsErr *net.DNSError
+		if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+			continue
+		}
+		authorized := false
+		for _, v := range values {
+			if IsDMARC(v) {
+				authorized = true
+			}
+		}
+		if !authorized {
+			audit.Issues = append(audit.Issues, recordIssue(record, ValidationIssue{
+				Severity:   "warning",
+				Type:       "dmarc_rua_unauthorized",
+				Message:    fmt.Sprintf("Report address %s is not authorized to receive reports for %s", address, domain),
+				Suggestion: fmt.Sprintf("%s must publish \"v=DMARC1\" at %s", reportDomain, name),
+			}))
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package inwx
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// MessageTypeDomain is a notification about a domain (transfers, expiry, registry events)
+	MessageTypeDomain = "DOMAIN"
+	// MessageTypeCertificate is a notification about an SSL certificate
+	MessageTypeCertificate = "CERTIFICATE"
+	// MessageTypeContact is a notification about a contact handle
+	MessageTypeContact = "CONTACT"
+	// MessageTypeHost is a notification about a host object
+	MessageTypeHost = "HOST"
+)
+
+// MessageTypes lists all message types returned by the notification queue
+var MessageTypes = []string{MessageTypeDomain, MessageTypeCertificate, MessageTypeContact, MessageTypeHost}
+
+type MessageService struct {
+	client *Client
+}
+
+// Message is a notification from the account's message queue
+type Message struct {
+	ID            int       `json:"id"`
+	Type          string    `json:"type"`
+	Date          time.Time `json:"date"`
+	Object        string    `json:"object"`
+	Status        string    `json:"status"`
+	StatusDetails string    `json:"statusDetails,omitempty" yaml:"statusDetails,omitempty"`
+}
+
+// MessageQueue holds messages read from the notification queue and the number of
+// messages still unread
+type MessageQueue struct {
+	Count    int       `json:"count"`
+	Messages []Message `json:"messages"`
+}
+
+// Message creates a new message service instance for the notification queue
+func (c *Client) Message() *MessageService {
+	return &MessageService{
+		client: c,
+	}
+}
+
+// Poll returns the first message of the notification queue along with the number of
+// unread messages. The message is nil if the queue is empty. Polling does not remove
+// the message; it is returned again until it is acknowledged.
+func (s *MessageService) Poll(ctx context.Context) (*Message, int, error) {
+	response, err := s.client.transport.Call(ctx, "message.poll", map[string]interface{}{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, 0, nil
+	}
+
+	count := getInt(resData, "count")
+	item, ok := resData["msg"].(map[string]interface{})
+	if !ok || count == 0 {
+		return nil, count, nil
+	}
+
+	return &Message{
+		ID:            getInt(item, "id"),
+		Type:          getString(item, "type"),
+		Date:          getTime(item, "date"),
+		Object:        getString(item, "object"),
+		Status:        getString(item, "status"),
+		StatusDetails: getString(item, "statusDetails"),
+	}, count, nil
+}
+
+// Ack acknowledges a message and removes it from the notification queue
+func (s *MessageService) Ack(ctx context.Context, id int) error {
+	_, err := s.client.transport.Call(ctx, "message.ack", map[string]interface{}{
+		"id": id,
+	})
+	return err
+}
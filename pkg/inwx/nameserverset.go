@@ -0,0 +1,256 @@
+package inwx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// NameserverSetTypePrimary uses the INWX nameservers as primary nameservers
+	NameserverSetTypePrimary = "PRIMARY"
+	// NameserverSetTypeSecondary uses the INWX nameservers as secondaries of a master server
+	NameserverSetTypeSecondary = "SECONDARY"
+	// NameserverSetTypeExternal uses external nameservers only
+	NameserverSetTypeExternal = "EXTERNAL"
+
+	// nameserverSetPageLimit is the page size used when listing nameserver sets
+	nameserverSetPageLimit = 100
+)
+
+// NameserverSetTypes lists all nameserver set types accepted by the API
+var NameserverSetTypes = []string{NameserverSetTypePrimary, NameserverSetTypeSecondary, NameserverSetTypeExternal}
+
+type NameserverSetService struct {
+	client  *Client
+	testing bool
+}
+
+type NameserverSetOption func(*NameserverSetService)
+
+// WithNameserverSetTesting executes modifying nameserver set calls in the API testing mode
+func WithNameserverSetTesting(testing bool) NameserverSetOption {
+	return func(s *NameserverSetService) {
+		s.testing = testing
+	}
+}
+
+// NameserverSet is a named list of nameservers that can be assigned to domains
+type NameserverSet struct {
+	ID          int      `json:"id,omitempty" yaml:"id,omitempty"`
+	Name        string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Type        string   `json:"type" yaml:"type"`
+	Nameservers []string `json:"ns" yaml:"ns"`
+	Hostmaster  string   `json:"hostmaster,omitempty" yaml:"hostmaster,omitempty"`
+	Visible     bool     `json:"visible" yaml:"visible"`
+	Prio        int      `json:"prio,omitempty" yaml:"prio,omitempty"`
+	Web         string   `json:"web,omitempty" yaml:"web,omitempty"`
+	Mail        string   `json:"mail,omitempty" yaml:"mail,omitempty"`
+	MasterIP    string   `json:"masterIp,omitempty" yaml:"masterIp,omitempty"`
+	ReadOnly    bool     `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+}
+
+// NameserverSet creates a new nameserver set service instance
+func (c *Client) NameserverSet(opts ...NameserverSetOption) *NameserverSetService {
+	service := &NameserverSetService{
+		client: c,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// IsValidNameserverSetType reports whether setType is a nameserver set type accepted by the API
+func IsValidNameserverSetType(setType string) bool {
+	for _, t := range NameserverSetTypes {
+		if strings.EqualFold(t, setType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *NameserverSetService) withTesting(params map[string]interface{}) map[string]interface{} {
+	if s.testing {
+		params["testing"] = true
+	}
+	return params
+}
+
+// List returns all nameserver sets
+func (s *NameserverSetService) List(ctx context.Context) ([]NameserverSet, error) {
+	var sets []NameserverSet
+
+	for page := 1; ; page++ {
+		response, err := s.client.transport.Call(ctx, "nameserverset.list", map[string]interface{}{
+			"wide":      true,
+			"page":      page,
+			"pagelimit": nameserverSetPageLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resData := getResData(response)
+		if resData == nil {
+			break
+		}
+
+		items := getMaps(resData, "nsset")
+		for _, item := range items {
+			sets = append(sets, parseNameserverSet(item))
+		}
+
+		if len(items) < nameserverSetPageLimit || len(sets) >= getInt(resData, "count") {
+			break
+		}
+	}
+
+	return sets, nil
+}
+
+// Info retrieves the details of a nameserver set
+func (s *NameserverSetService) Info(ctx context.Context, id int) (*NameserverSet, error) {
+	response, err := s.client.transport.Call(ctx, "nameserverset.info", map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("nameserver set %d not found", id)
+	}
+
+	set := parseNameserverSet(resData)
+	if set.ID == 0 {
+		set.ID = id
+	}
+	return &set, nil
+}
+
+// Resolve looks up a nameserver set by ID or by its (case-insensitive) name
+func (s *NameserverSetService) Resolve(ctx context.Context, ref string) (*NameserverSet, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil {
+		return s.Info(ctx, id)
+	}
+
+	sets, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for i := range sets {
+		if strings.EqualFold(sets[i].Name, ref) {
+			return &sets[i], nil
+		}
+		if sets[i].Name != "" {
+			names = append(names, sets[i].Name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("nameserver set %q not found", ref)
+	}
+	return nil, fmt.Errorf("nameserver set %q not found (available: %s)", ref, strings.Join(names, ", "))
+}
+
+// Create creates a nameserver set and returns its ID
+func (s *NameserverSetService) Create(ctx context.Context, set NameserverSet) (int, error) {
+	if !IsValidNameserverSetType(set.Type) {
+		return 0, fmt.Errorf("invalid nameserver set type %q (must be one of %s)", set.Type, strings.Join(NameserverSetTypes, ", "))
+	}
+	if len(set.Nameservers) == 0 {
+		return 0, fmt.Errorf("at least one nameserver is required")
+	}
+
+	params := s.withTesting(nameserverSetParams(set))
+	params["visible"] = set.Visible
+
+	response, err := s.client.transport.Call(ctx, "nameserverset.create", params)
+	if err != nil {
+		return 0, err
+	}
+
+	if resData := getResData(response); resData != nil {
+		return getInt(resData, "id"), nil
+	}
+
+	return 0, nil
+}
+
+// Update changes the non-empty fields of an existing nameserver set
+func (s *NameserverSetService) Update(ctx context.Context, id int, set NameserverSet) error {
+	if set.Type != "" && !IsValidNameserverSetType(set.Type) {
+		return fmt.Errorf("invalid nameserver set type %q (must be one of %s)", set.Type, strings.Join(NameserverSetTypes, ", "))
+	}
+
+	params := s.withTesting(nameserverSetParams(set))
+	params["id"] = id
+
+	_, err := s.client.transport.Call(ctx, "nameserverset.update", params)
+	return err
+}
+
+// Delete deletes a nameserver set
+func (s *NameserverSetService) Delete(ctx context.Context, id int) error {
+	_, err := s.client.transport.Call(ctx, "nameserverset.delete", s.withTesting(map[string]interface{}{
+		"id": id,
+	}))
+	return err
+}
+
+// nameserverSetParams converts the non-empty fields of a nameserver set to API parameters
+func nameserverSetParams(set NameserverSet) map[string]interface{} {
+	params := map[string]interface{}{}
+
+	if set.Type != "" {
+		params["type"] = strings.ToUpper(set.Type)
+	}
+	if set.Name != "" {
+		params["name"] = set.Name
+	}
+	if len(set.Nameservers) > 0 {
+		params["ns"] = set.Nameservers
+	}
+	if set.Hostmaster != "" {
+		params["hostmaster"] = set.Hostmaster
+	}
+	if set.Prio != 0 {
+		params["prio"] = set.Prio
+	}
+	if set.Web != "" {
+		params["web"] = set.Web
+	}
+	if set.Mail != "" {
+		params["mail"] = set.Mail
+	}
+	if set.MasterIP != "" {
+		params["masterIp"] = set.MasterIP
+	}
+
+	return params
+}
+
+func parseNameserverSet(item map[string]interface{}) NameserverSet {
+	return NameserverSet{
+		ID:          getInt(item, "id"),
+		Name:        getString(item, "name"),
+		Type:        getString(item, "type"),
+		Nameservers: getStrings(item, "ns"),
+		Hostmaster:  getString(item, "hostmaster"),
+		Visible:     getBool(item, "visible"),
+		Prio:        getInt(item, "prio"),
+		Web:         getString(item, "web"),
+		Mail:        getString(item, "mail"),
+		MasterIP:    getString(item, "masterIp"),
+		ReadOnly:    getBool(item, "readOnly"),
+	}
+}
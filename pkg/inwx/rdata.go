@@ -0,0 +1,764 @@
+package inwx
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hostnamePattern matches host names in record content. Underscores are allowed for
+// service labels such as _sip._udp.
+var hostnamePattern = regexp.MustCompile(`^([A-Za-z0-9_]([A-Za-z0-9_-]{0,61}[A-Za-z0-9_])?\.)*[A-Za-z0-9_]([A-Za-z0-9_-]{0,61}[A-Za-z0-9_])?\.?$`)
+
+// RecordData is the typed content of a record
+type RecordData interface {
+	// Type returns the record type
+	Type() string
+	// Content returns the record content in the form stored by INWX
+	Content() string
+	// Validate checks the values of the fields
+	Validate() error
+}
+
+// ParseRecordData parses record content into its typed form and validates it. It
+// returns nil without error for types that have no typed form.
+func ParseRecordData(recordType, content string, prio int) (RecordData, error) {
+	var data RecordData
+	var err error
+	switch strings.ToUpper(recordType) {
+	case "SRV":
+		data, err = ParseSRV(content, prio)
+	case "CAA":
+		data, err = ParseCAA(content)
+	case "TLSA":
+		data, err = ParseTLSA(content)
+	case "SSHFP":
+		data, err = ParseSSHFP(content)
+	case "HTTPS", "SVCB":
+		data, err = ParseSVCB(strings.ToUpper(recordType), content)
+	case "NAPTR":
+		data, err = ParseNAPTR(content)
+	case "LOC":
+		data, err = ParseLOC(content)
+	case "URI":
+		data, err = ParseURI(content)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// RecordPrio returns the value of the prio field for typed record content
+func RecordPrio(data RecordData) int {
+	if srv, ok := data.(*SRVData); ok {
+		return srv.Priority
+	}
+	return 0
+}
+
+// splitRData splits record content into fields. Quoted strings form a single field
+// without the quotes.
+func splitRData(content string) ([]string, error) {
+	var fields []string
+	var b strings.Builder
+	inField, inQuotes := false, false
+	for i := 0; i < len(content); i++ {
+		ch := content[i]
+		switch {
+		case ch == '\\' && i+1 < len(content):
+			i++
+			b.WriteByte(content[i])
+			inField = true
+		case ch == '"':
+			inQuotes = !inQuotes
+			inField = true
+		case (ch == ' ' || ch == '\t') && !inQuotes:
+			if inField {
+				fields = append(fields, b.String())
+				b.Reset()
+				inField = false
+			}
+		default:
+			b.WriteByte(ch)
+			inField = true
+		}
+	}
+	if inQuotes {
+		return nil, errors.New("unterminated quoted string")
+	}
+	if inField {
+		fields = append(fields, b.String())
+	}
+	return fields, nil
+}
+
+// quoteRData returns value as a quoted character string
+func quoteRData(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// parseUint parses a numeric field within [0, max]
+func parseUint(field, name string, max int) (int, error) {
+	n, err := strconv.Atoi(field)
+	if err != nil || n < 0 || n > max {
+		return 0, fmt.Errorf("invalid %s %q (must be between 0 and %d)", name, field, max)
+	}
+	return n, nil
+}
+
+func checkRange(value int, name string, max int) error {
+	if value < 0 || value > max {
+		return fmt.Errorf("invalid %s %d (must be between 0 and %d)", name, value, max)
+	}
+	return nil
+}
+
+func checkHostname(value, name string) error {
+	if value == "." {
+		return nil
+	}
+	if len(value) > 254 || !hostnamePattern.MatchString(value) {
+		return fmt.Errorf("invalid %s %q", name, value)
+	}
+	return nil
+}
+
+func checkHex(value, name string, length int) error {
+	if value == "" {
+		return fmt.Errorf("%s cannot be empty", name)
+	}
+	if _, err := hex.DecodeString(value); err != nil {
+		return fmt.Errorf("%s must be hexadecimal", name)
+	}
+	if length > 0 && len(value) != length {
+		return fmt.Errorf("%s must have %d hex digits, got %d", name, length, len(value))
+	}
+	return nil
+}
+
+// SRVData is the content of an SRV record
+type SRVData struct {
+	Priority int    `json:"priority" yaml:"priority"`
+	Weight   int    `json:"weight" yaml:"weight"`
+	Port     int    `json:"port" yaml:"port"`
+	Target   string `json:"target" yaml:"target"`
+}
+
+// ParseSRV parses SRV content. INWX keeps the priority in the prio field and the
+// content as "weight port target"; content starting with the priority is accepted too.
+func ParseSRV(content string, prio int) (*SRVData, error) {
+	fields, err := splitRData(content)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &SRVData{Priority: prio}
+	switch len(fields) {
+	case 3:
+	case 4:
+		if d.Priority, err = parseUint(fields[0], "SRV priority", 65535); err != nil {
+			return nil, err
+		}
+		fields = fields[1:]
+	default:
+		return nil, errors.New("SRV record must have format: weight port target (priority in prio)")
+	}
+
+	if d.Weight, err = parseUint(fields[0], "SRV weight", 65535); err != nil {
+		return nil, err
+	}
+	if d.Port, err = parseUint(fields[1], "SRV port", 65535); err != nil {
+		return nil, err
+	}
+	d.Target = fields[2]
+	return d, nil
+}
+
+func (d *SRVData) Type() string { return "SRV" }
+
+func (d *SRVData) Content() string {
+	return fmt.Sprintf("%d %d %s", d.Weight, d.Port, d.Target)
+}
+
+func (d *SRVData) Validate() error {
+	if err := checkRange(d.Priority, "SRV priority", 65535); err != nil {
+		return err
+	}
+	if err := checkRange(d.Weight, "SRV weight", 65535); err != nil {
+		return err
+	}
+	if err := checkRange(d.Port, "SRV port", 65535); err != nil {
+		return err
+	}
+	return checkHostname(d.Target, "SRV target")
+}
+
+// caaTags are the property tags registered for CAA records
+var caaTags = []string{"issue", "issuewild", "iodef", "issuemail", "issuevmc", "contactemail", "contactphone"}
+
+// CAAData is the content of a CAA record
+type CAAData struct {
+	Flags int    `json:"flags" yaml:"flags"`
+	Tag   string `json:"tag" yaml:"tag"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// ParseCAA parses CAA content, e.g. `0 issue "letsencrypt.org"`
+func ParseCAA(content string) (*CAAData, error) {
+	fields, err := splitRData(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < 3 {
+		return nil, errors.New(`CAA record must have format: flags tag "value"`)
+	}
+
+	// Unquoted values with parameters span several fields
+	d := &CAAData{Tag: strings.ToLower(fields[1]), Value: strings.Join(fields[2:], " ")}
+	if d.Flags, err = parseUint(fields[0], "CAA flags", 255); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *CAAData) Type() string { return "CAA" }
+
+func (d *CAAData) Content() string {
+	return fmt.Sprintf("%d %s %s", d.Flags, d.Tag, quoteRData(d.Value))
+}
+
+func (d *CAAData) Validate() error {
+	if err := checkRange(d.Flags, "CAA flags", 255); err != nil {
+		return err
+	}
+
+	known := false
+	for _, tag := range caaTags {
+		if d.Tag == tag {
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown CAA tag %q (must be one of %s)", d.Tag, strings.Join(caaTags, ", "))
+	}
+
+	switch d.Tag {
+	case "issue", "issuewild", "issuemail", "issuevmc":
+		// The issuer domain is optional, ";" alone forbids issuance
+		issuer, _, _ := strings.Cut(d.Value, ";")
+		if issuer = strings.TrimSpace(issuer); issuer != "" {
+			if err := checkHostname(issuer, "CAA issuer"); err != nil {
+				return err
+			}
+		}
+	case "iodef":
+		u, err := url.Parse(d.Value)
+		if err != nil || (u.Scheme != "mailto" && u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("CAA iodef value %q must be a mailto: or http(s) URL", d.Value)
+		}
+	}
+	return nil
+}
+
+// TLSAData is the content of a TLSA record
+type TLSAData struct {
+	Usage        int    `json:"usage" yaml:"usage"`
+	Selector     int    `json:"selector" yaml:"selector"`
+	MatchingType int    `json:"matching_type" yaml:"matching_type"`
+	Data         string `json:"data" yaml:"data"`
+}
+
+// ParseTLSA parses TLSA content, e.g. "3 1 1 <sha256>"
+func ParseTLSA(content string) (*TLSAData, error) {
+	fields, err := splitRData(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < 4 {
+		return nil, errors.New("TLSA record must have format: usage selector matching-type data")
+	}
+
+	d := &TLSAData{Data: strings.ToLower(strings.Join(fields[3:], ""))}
+	if d.Usage, err = parseUint(fields[0], "TLSA usage", 255); err != nil {
+		return nil, err
+	}
+	if d.Selector, err = parseUint(fields[1], "TLSA selector", 255); err != nil {
+		return nil, err
+	}
+	if d.MatchingType, err = parseUint(fields[2], "TLSA matching type", 255); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *TLSAData) Type() string { return "TLSA" }
+
+func (d *TLSAData) Content() string {
+	return fmt.Sprintf("%d %d %d %s", d.Usage, d.Selector, d.MatchingType, strings.ToLower(d.Data))
+}
+
+func (d *TLSAData) Validate() error {
+	if err := checkRange(d.Usage, "TLSA usage", 3); err != nil {
+		return err
+	}
+	if err := checkRange(d.Selector, "TLSA selector", 1); err != nil {
+		return err
+	}
+	if err := checkRange(d.MatchingType, "TLSA matching type", 2); err != nil {
+		return err
+	}
+	length := map[int]int{1: 64, 2: 128}[d.MatchingType]
+	return checkHex(d.Data, "TLSA data", length)
+}
+
+// SSHFPData is the content of an SSHFP record
+type SSHFPData struct {
+	Algorithm       int    `json:"algorithm" yaml:"algorithm"`
+	FingerprintType int    `json:"fingerprint_type" yaml:"fingerprint_type"`
+	Fingerprint     string `json:"fingerprint" yaml:"fingerprint"`
+}
+
+// ParseSSHFP parses SSHFP content, e.g. "4 2 <sha256>"
+func ParseSSHFP(content string) (*SSHFPData, error) {
+	fields, err := splitRData(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 3 {
+		return nil, errors.New("SSHFP record must have format: algorithm fingerprint-type fingerprint")
+	}
+
+	d := &SSHFPData{Fingerprint: strings.ToLower(fields[2])}
+	if d.Algorithm, err = parseUint(fields[0], "SSHFP algorithm", 255); err != nil {
+		return nil, err
+	}
+	if d.FingerprintType, err = parseUint(fields[1], "SSHFP fingerprint type", 255); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *SSHFPData) Type() string { return "SSHFP" }
+
+func (d *SSHFPData) Content() string {
+	return fmt.Sprintf("%d %d %s", d.Algorithm, d.FingerprintType, strings.ToLower(d.Fingerprint))
+}
+
+func (d *SSHFPData) Validate() error {
+	switch d.Algorithm {
+	case 1, 2, 3, 4, 6:
+	default:
+		return fmt.Errorf("invalid SSHFP algorithm %d (1 = RSA, 2 = DSA, 3 = ECDSA, 4 = Ed25519, 6 = Ed448)", d.Algorithm)
+	}
+	switch d.FingerprintType {
+	case 1:
+		return checkHex(d.Fingerprint, "SSHFP fingerprint", 40)
+	case 2:
+		return checkHex(d.Fingerprint, "SSHFP fingerprint", 64)
+	default:
+		return fmt.Errorf("invalid SSHFP fingerprint type %d (1 = SHA-1, 2 = SHA-256)", d.FingerprintType)
+	}
+}
+
+// SVCParam is a service parameter of an SVCB or HTTPS record
+type SVCParam struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// svcParamKeys are the registered service parameter keys
+var svcParamKeys = []string{"mandatory", "alpn", "no-default-alpn", "port", "ipv4hint", "ech", "ipv6hint", "dohpath", "ohttp"}
+
+// SVCBData is the content of an SVCB or HTTPS record
+type SVCBData struct {
+	RRType   string     `json:"type" yaml:"type"`
+	Priority int        `json:"priority" yaml:"priority"`
+	Target   string     `json:"target" yaml:"target"`
+	Params   []SVCParam `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// ParseSVCB parses SVCB or HTTPS content, e.g. "1 . alpn=h2,h3 ipv4hint=192.0.2.1"
+func ParseSVCB(recordType, content string) (*SVCBData, error) {
+	fields, err := splitRData(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("%s record must have format: priority target [key=value...]", recordType)
+	}
+
+	d := &SVCBData{RRType: recordType, Target: fields[1]}
+	if d.Priority, err = parseUint(fields[0], recordType+" priority", 65535); err != nil {
+		return nil, err
+	}
+	for _, field := range fields[2:] {
+		key, value, _ := strings.Cut(field, "=")
+		d.Params = append(d.Params, SVCParam{Key: strings.ToLower(key), Value: value})
+	}
+	return d, nil
+}
+
+// ParseSVCParams parses service parameters in the form key=value
+func ParseSVCParams(values []string) []SVCParam {
+	var params []SVCParam
+	for _, v := range values {
+		key, value, hasValue := strings.Cut(strings.TrimSpace(v), "=")
+		// Lists split at their commas, e.g. by command line parsing, are joined again
+		if n := len(params); n > 0 && !hasValue && svcParamKeyError(strings.ToLower(key)) != nil {
+			switch params[n-1].Key {
+			case "mandatory", "alpn", "ipv4hint", "ipv6hint":
+				params[n-1].Value += "," + key
+				continue
+			}
+		}
+		params = append(params, SVCParam{Key: strings.ToLower(key), Value: value})
+	}
+	return params
+}
+
+func (d *SVCBData) Type() string { return d.RRType }
+
+func (d *SVCBData) Content() string {
+	parts := []string{strconv.Itoa(d.Priority), d.Target}
+	for _, p := range d.Params {
+		switch {
+		case p.Value == "":
+			parts = append(parts, p.Key)
+		case strings.ContainsAny(p.Value, ` "`):
+			parts = append(parts, p.Key+"="+quoteRData(p.Value))
+		default:
+			parts = append(parts, p.Key+"="+p.Value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (d *SVCBData) Validate() error {
+	if err := checkRange(d.Priority, d.RRType+" priority", 65535); err != nil {
+		return err
+	}
+	if err := checkHostname(d.Target, d.RRType+" target"); err != nil {
+		return err
+	}
+	if d.Priority == 0 && len(d.Params) > 0 {
+		return fmt.Errorf("%s records with priority 0 (alias mode) cannot have parameters", d.RRType)
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range d.Params {
+		if seen[p.Key] {
+			return fmt.Errorf("duplicate %s parameter %q", d.RRType, p.Key)
+		}
+		seen[p.Key] = true
+	}
+
+	for _, p := range d.Params {
+		if err := p.validate(seen); err != nil {
+			return fmt.Errorf("invalid %s parameter %s: %w", d.RRType, p.Key, err)
+		}
+	}
+	return nil
+}
+
+// svcParamKeyError checks that key is a registered key or has the form keyNNNNN
+func svcParamKeyError(key string) error {
+	if strings.HasPrefix(key, "key") && len(key) > 3 {
+		_, err := parseUint(key[3:], "key number", 65535)
+		return err
+	}
+	for _, k := range svcParamKeys {
+		if key == k {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown key (must be one of %s or keyNNNNN)", strings.Join(svcParamKeys, ", "))
+}
+
+func (p SVCParam) validate(present map[string]bool) error {
+	if err := svcParamKeyError(p.Key); err != nil {
+		return err
+	}
+
+	values := strings.Split(p.Value, ",")
+	switch p.Key {
+	case "no-default-alpn", "ohttp":
+		if p.Value != "" {
+			return errors.New("takes no value")
+		}
+		return nil
+	case "port":
+		_, err := parseUint(p.Value, "port", 65535)
+		return err
+	case "ipv4hint", "ipv6hint":
+		for _, v := range values {
+			ip := net.ParseIP(v)
+			if ip == nil || (ip.To4() != nil) != (p.Key == "ipv4hint") {
+				return fmt.Errorf("invalid address %q", v)
+			}
+		}
+		return nil
+	case "ech":
+		if _, err := base64.StdEncoding.DecodeString(p.Value); err != nil || p.Value == "" {
+			return errors.New("must be base64 encoded")
+		}
+		return nil
+	case "mandatory":
+		for _, v := range values {
+			if v == "mandatory" || !present[v] {
+				return fmt.Errorf("lists %q, which is not present", v)
+			}
+		}
+		return nil
+	}
+
+	if p.Value == "" {
+		return errors.New("requires a value")
+	}
+	return nil
+}
+
+// NAPTRData is the content of a NAPTR record
+type NAPTRData struct {
+	Order       int    `json:"order" yaml:"order"`
+	Preference  int    `json:"preference" yaml:"preference"`
+	Flags       string `json:"flags" yaml:"flags"`
+	Service     string `json:"service" yaml:"service"`
+	Regexp      string `json:"regexp" yaml:"regexp"`
+	Replacement string `json:"replacement" yaml:"replacement"`
+}
+
+// ParseNAPTR parses NAPTR content, e.g. `100 10 "S" "SIP+D2U" "" _sip._udp.example.com.`
+func ParseNAPTR(content string) (*NAPTRData, error) {
+	fields, err := splitRData(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 6 {
+		return nil, errors.New(`NAPTR record must have format: order preference "flags" "service" "regexp" replacement`)
+	}
+
+	d := &NAPTRData{Flags: fields[2], Service: fields[3], Regexp: fields[4], Replacement: fields[5]}
+	if d.Order, err = parseUint(fields[0], "NAPTR order", 65535); err != nil {
+		return nil, err
+	}
+	if d.Preference, err = parseUint(fields[1], "NAPTR preference", 65535); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *NAPTRData) Type() string { return "NAPTR" }
+
+func (d *NAPTRData) Content() string {
+	return fmt.Sprintf("%d %d %s %s %s %s", d.Order, d.Preference,
+		quoteRData(d.Flags), quoteRData(d.Service), quoteRData(d.Regexp), d.Replacement)
+}
+
+func (d *NAPTRData) Validate() error {
+	if err := checkRange(d.Order, "NAPTR order", 65535); err != nil {
+		return err
+	}
+	if err := checkRange(d.Preference, "NAPTR preference", 65535); err != nil {
+		return err
+	}
+	for _, ch := range d.Flags {
+		if !(ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9') {
+			return fmt.Errorf("invalid NAPTR flags %q (letters and digits only)", d.Flags)
+		}
+	}
+	if d.Replacement == "" {
+		return errors.New("NAPTR replacement cannot be empty, use . with a regexp")
+	}
+	if err := checkHostname(d.Replacement, "NAPTR replacement"); err != nil {
+		return err
+	}
+	if d.Regexp != "" && d.Replacement != "." {
+		return errors.New("NAPTR records cannot have both a regexp and a replacement")
+	}
+	return nil
+}
+
+// LOCData is the content of a LOC record. Coordinates are decimal degrees, the other
+// values meters.
+type LOCData struct {
+	Latitude            float64 `json:"latitude" yaml:"latitude"`
+	Longitude           float64 `json:"longitude" yaml:"longitude"`
+	Altitude            float64 `json:"altitude" yaml:"altitude"`
+	Size                float64 `json:"size" yaml:"size"`
+	HorizontalPrecision float64 `json:"horizontal_precision" yaml:"horizontal_precision"`
+	VerticalPrecision   float64 `json:"vertical_precision" yaml:"vertical_precision"`
+}
+
+// NewLOCData returns LOC content with the default size and precisions of RFC 1876
+func NewLOCData(latitude, longitude, altitude float64) *LOCData {
+	return &LOCData{
+		Latitude:            latitude,
+		Longitude:           longitude,
+		Altitude:            altitude,
+		Size:                1,
+		HorizontalPrecision: 10000,
+		VerticalPrecision:   10,
+	}
+}
+
+// ParseLOC parses LOC content, e.g. "52 22 23.000 N 4 53 32.000 E -2.00m 1m 10000m 10m"
+func ParseLOC(content string) (*LOCData, error) {
+	fields, err := splitRData(content)
+	if err != nil {
+		return nil, err
+	}
+
+	parseCoordinate := func(positive, negative string) (float64, error) {
+		var parts []float64
+		for len(fields) > 0 && len(parts) < 3 {
+			if h := strings.ToUpper(fields[0]); h == positive || h == negative {
+				break
+			}
+			v, err := strconv.ParseFloat(fields[0], 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid LOC coordinate %q", fields[0])
+			}
+			parts = append(parts, v)
+			fields = fields[1:]
+		}
+		if len(parts) == 0 || len(fields) == 0 {
+			return 0, errors.New("LOC record must have format: d [m [s]] N|S d [m [s]] E|W alt[m] [size[m] [hp[m] [vp[m]]]]")
+		}
+		value := parts[0]
+		if len(parts) > 1 {
+			value += parts[1] / 60
+		}
+		if len(parts) > 2 {
+			value += parts[2] / 3600
+		}
+		hemisphere := strings.ToUpper(fields[0])
+		fields = fields[1:]
+		switch hemisphere {
+		case positive:
+			return value, nil
+		case negative:
+			return -value, nil
+		}
+		return 0, fmt.Errorf("invalid LOC hemisphere %q", hemisphere)
+	}
+
+	d := NewLOCData(0, 0, 0)
+	if d.Latitude, err = parseCoordinate("N", "S"); err != nil {
+		return nil, err
+	}
+	if d.Longitude, err = parseCoordinate("E", "W"); err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("LOC record requires an altitude")
+	}
+	if len(fields) > 4 {
+		return nil, errors.New("LOC record has too many fields")
+	}
+
+	targets := []*float64{&d.Altitude, &d.Size, &d.HorizontalPrecision, &d.VerticalPrecision}
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(field), "m"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LOC distance %q", field)
+		}
+		*targets[i] = v
+	}
+	return d, nil
+}
+
+func (d *LOCData) Type() string { return "LOC" }
+
+func (d *LOCData) Content() string {
+	coordinate := func(value float64, positive, negative string) string {
+		hemisphere := positive
+		if value < 0 {
+			hemisphere = negative
+			value = -value
+		}
+		// Round to thousandths of an arc second first to avoid 60.000 seconds
+		ms := int64(math.Round(value * 3600000))
+		return fmt.Sprintf("%d %d %.3f %s", ms/3600000, ms%3600000/60000, float64(ms%60000)/1000, hemisphere)
+	}
+	return fmt.Sprintf("%s %s %.2fm %.2fm %.2fm %.2fm",
+		coordinate(d.Latitude, "N", "S"), coordinate(d.Longitude, "E", "W"),
+		d.Altitude, d.Size, d.HorizontalPrecision, d.VerticalPrecision)
+}
+
+func (d *LOCData) Validate() error {
+	if d.Latitude < -90 || d.Latitude > 90 {
+		return fmt.Errorf("invalid LOC latitude %g (must be between -90 and 90)", d.Latitude)
+	}
+	if d.Longitude < -180 || d.Longitude > 180 {
+		return fmt.Errorf("invalid LOC longitude %g (must be between -180 and 180)", d.Longitude)
+	}
+	if d.Altitude < -100000 || d.Altitude > 42849672.95 {
+		return fmt.Errorf("invalid LOC altitude %g (must be between -100000 and 42849672.95)", d.Altitude)
+	}
+	for name, v := range map[string]float64{"size": d.Size, "horizontal precision": d.HorizontalPrecision, "vertical precision": d.VerticalPrecision} {
+		if v < 0 || v > 90000000 {
+			return fmt.Errorf("invalid LOC %s %g (must be between 0 and 90000000)", name, v)
+		}
+	}
+	return nil
+}
+
+// URIData is the content of a URI record
+type URIData struct {
+	Priority int    `json:"priority" yaml:"priority"`
+	Weight   int    `json:"weight" yaml:"weight"`
+	Target   string `json:"target" yaml:"target"`
+}
+
+// ParseURI parses URI content, e.g. `10 1 "https://example.com/"`
+func ParseURI(content string) (*URIData, error) {
+	fields, err := splitRData(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 3 {
+		return nil, errors.New(`URI record must have format: priority weight "target"`)
+	}
+
+	d := &URIData{Target: fields[2]}
+	if d.Priority, err = parseUint(fields[0], "URI priority", 65535); err != nil {
+		return nil, err
+	}
+	if d.Weight, err = parseUint(fields[1], "URI weight", 65535); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *URIData) Type() string { return "URI" }
+
+func (d *URIData) Content() string {
+	return fmt.Sprintf("%d %d %s", d.Priority, d.Weight, quoteRData(d.Target))
+}
+
+func (d *URIData) Validate() error {
+	if err := checkRange(d.Priority, "URI priority", 65535); err != nil {
+		return err
+	}
+	if err := checkRange(d.Weight, "URI weight", 65535); err != nil {
+		return err
+	}
+	u, err := url.Parse(d.Target)
+	if err != nil || u.Scheme == "" {
+		return fmt.Errorf("invalid URI target %q (must be an absolute URI)", d.Target)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package inwx
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Redirect types of URL records
+const (
+	URLRedirect301   = "HEADER301"
+	URLRedirect302   = "HEADER302"
+	URLRedirectFrame = "FRAME"
+)
+
+// ParseURLRedirectType returns the redirect type of a URL record for 301, 302 or frame.
+// The API names HEADER301, HEADER302 and FRAME are accepted as well.
+func ParseURLRedirectType(value string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "301", URLRedirect301:
+		return URLRedirect301, nil
+	case "302", URLRedirect302:
+		return URLRedirect302, nil
+	case "FRAME", "IFRAME":
+		return URLRedirectFrame, nil
+	default:
+		return "", fmt.Errorf("invalid redirect type %q (must be 301, 302 or frame)", value)
+	}
+}
+
+// ValidateRedirectTarget checks that the target of a URL record is an absolute http(s) URL
+func ValidateRedirectTarget(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("URL record target %q must be an absolute http(s) URL", target)
+	}
+	return nil
+}
+
+// HasURLRedirect reports whether any redirect parameter of the record is set
+func (r DNSRecord) HasURLRedirect() bool {
+	return r.URLRedirectType != "" || r.URLRedirectTitle != "" || r.URLRedirectDescription != "" ||
+		r.URLRedirectFavIcon != "" || r.URLRedirectKeywords != "" || r.URLAppend
+}
+
+// normalizeURLRecord checks the target and redirect parameters of URL records and
+// converts the redirect type to its API name. Redirect parameters on other record
+// types are rejected.
+func normalizeURLRecord(record *DNSRecord) error {
+	if !strings.EqualFold(record.Type, "URL") {
+		if record.HasURLRedirect() {
+			return fmt.Errorf("redirect parameters are only supported by URL records, not %s", record.Type)
+		}
+		return nil
+	}
+
+	if record.Content != "" {
+		if err := ValidateRedirectTarget(record.Content); err != nil {
+			return err
+		}
+	}
+	if record.URLRedirectType != "" {
+		redirectType, err := ParseURLRedirectType(record.URLRedirectType)
+		if err != nil {
+			return err
+		}
+		record.URLRedirectType = redirectType
+	}
+	if record.URLRedirectType != URLRedirectFrame &&
+		(record.URLRedirectTitle != "" || record.URLRedirectDescription != "" ||
+			record.URLRedirectFavIcon != "" || record.URLRedirectKeywords != "") {
+		return fmt.Errorf("title, description, favicon and keywords require the frame redirect type")
+	}
+	return nil
+}
+
+// addURLRedirectParams adds the redirect parameters that are set to API parameters
+func addURLRedirectParams(params map[string]interface{}, record DNSRecord) {
+	if record.URLRedirectType != "" {
+		params["urlRedirectType"] = record.URLRedirectType
+	}
+	if record.URLRedirectTitle != "" {
+		params["urlRedirectTitle"] = record.URLRedirectTitle
+	}
+	if record.URLRedirectDescription != "" {
+		params["urlRedirectDescription"] = record.URLRedirectDescription
+	}
+	if record.URLRedirectFavIcon != "" {
+		params["urlRedirectFavIcon"] = record.URLRedirectFavIcon
+	}
+	if record.URLRedirectKeywords != "" {
+		params["urlRedirectKeywords"] = record.URLRedirectKeywords
+	}
+	if record.URLAppend {
+		params["urlAppend"] = true
+	}
+}
+
+// setURLRedirectParams adds all redirect parameters to API parameters, including
+// empty ones, so that parameters cleared in record are cleared by an update
+func setURLRedirectParams(params map[string]interface{}, record DNSRecord) {
+	if record.URLRedirectType != "" {
+		params["urlRedirectType"] = record.URLRedirectType
+	}
+	params["urlRedirectTitle"] = record.URLRedirectTitle
+	params["urlRedirectDescription"] = record.URLRedirectDescription
+	params["urlRedirectFavIcon"] = record.URLRedirectFavIcon
+	params["urlRedirectKeywords"] = record.URLRedirectKeywords
+	params["urlAppend"] = record.URLAppend
+}
+
+// parseURLRedirectFields sets the redirect parameters of a record returned by the API
+func parseURLRedirectFields(record *DNSRecord, data map[string]interface{}) {
+	if v, ok := data["urlRedirectType"].(string); ok {
+		record.URLRedirectType = v
+	}
+	if v, ok := data["urlRedirectTitle"].(string); ok {
+		record.URLRedirectTitle = v
+	}
+	if v, ok := data["urlRedirectDescription"].(string); ok {
+		record.URLRedirectDescription = v
+	}
+	if v, ok := data["urlRedirectFavIcon"].(string); ok {
+		record.URLRedirectFavIcon = v
+	}
+	if v, ok := data["urlRedirectKeywords"].(string); ok {
+		record.URLRedirectKeywords = v
+	}
+	switch v := data["urlAppend"].(type) {
+	case bool:
+		record.URLAppend = v
+	case float64:
+		record.URLAppend = v != 0
+	}
+}
+
+// urlRedirectComment returns the redirect parameters of a URL record as a zone file
+// comment, e.g. `; redirect=FRAME title="Example"`, which zone files cannot express
+func urlRedirectComment(record DNSRecord) string {
+	var parts []string
+	if record.URLRedirectType != "" {
+		parts = append(parts, "redirect="+record.URLRedirectType)
+	}
+	for _, field := range []struct{ key, value string }{
+		{"title", record.URLRedirectTitle},
+		{"description", record.URLRedirectDescription},
+		{"favicon", record.URLRedirectFavIcon},
+		{"keywords", record.URLRedirectKeywords},
+	} {
+		if field.value != "" {
+			parts = append(parts, field.key+"="+quoteRData(field.value))
+		}
+	}
+	if record.URLAppend {
+		parts = append(parts, "append")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "; " + strings.Join(parts, " ")
+}
+
+// parseURLRedirectComment sets the redirect parameters from a zone file comment
+// written by urlRedirectComment
+func parseURLRedirectComment(record *DNSRecord, comment string) error {
+	fields, err := splitRData(comment)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		key, value, _ := strings.Cut(field, "=")
+		switch key {
+		case "redirect":
+			record.URLRedirectType = value
+		case "title":
+			record.URLRedirectTitle = value
+		case "description":
+			record.URLRedirectDescription = value
+		case "favicon":
+			record.URLRedirectFavIcon = value
+		case "keywords":
+			record.URLRedirectKeywords = value
+		case "append":
+			record.URLAppend = true
+		}
+	}
+	return nil
+}
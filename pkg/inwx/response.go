@@ -0,0 +1,147 @@
+package inwx
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiTimeLayouts lists the date formats returned by the DomRobot API
+var apiTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02 15:04:05",
+	"20060102T15:04:05",
+	"2006-01-02",
+}
+
+// getResData extracts the resData map from an API response
+func getResData(response map[string]interface{}) map[string]interface{} {
+	if response == nil {
+		return nil
+	}
+	if resData, ok := response["resData"].(map[string]interface{}); ok {
+		return resData
+	}
+	return nil
+}
+
+// getString returns the string value for key, converting numbers if needed
+func getString(m map[string]interface{}, key string) string {
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// getInt returns the integer value for key; the API sometimes sends numbers as strings
+func getInt(m map[string]interface{}, key string) int {
+	switch v := m[key].(type) {
+	case float64:
+		return int(v)
+	case string:
+		if i, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return i
+		}
+	}
+	return 0
+}
+
+// getFloat returns the float value for key; the API sometimes sends numbers as strings
+func getFloat(m map[string]interface{}, key string) float64 {
+	switch v := m[key].(type) {
+	case float64:
+		return v
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return f
+		}
+	}
+	return 0
+}
+
+// getBool returns the boolean value for key, accepting 0/1 and "true"/"false"
+func getBool(m map[string]interface{}, key string) bool {
+	switch v := m[key].(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
+
+// getTime parses a date or timestamp value for key; returns the zero time if missing or invalid
+func getTime(m map[string]interface{}, key string) time.Time {
+	return parseAPITime(getString(m, key))
+}
+
+// getStrings returns a list of strings for key
+func getStrings(m map[string]interface{}, key string) []string {
+	var result []string
+	if list, ok := m[key].([]interface{}); ok {
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+// getMaps returns a list of objects for key
+func getMaps(m map[string]interface{}, key string) []map[string]interface{} {
+	var result []map[string]interface{}
+	if list, ok := m[key].([]interface{}); ok {
+		for _, item := range list {
+			if obj, ok := item.(map[string]interface{}); ok {
+				result = append(result, obj)
+			}
+		}
+	}
+	return result
+}
+
+// parseAPITime parses a timestamp in any of the formats used by the API
+func parseAPITime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range apiTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// getBase64 decodes a base64 value for key. The JSON API either sends the encoded
+// string directly or wraps it in an object with a "scalar" field.
+func getBase64(m map[string]interface{}, key string) ([]byte, error) {
+	var encoded string
+	switch v := m[key].(type) {
+	case string:
+		encoded = v
+	case map[string]interface{}:
+		encoded = getString(v, "scalar")
+	}
+	if encoded == "" {
+		return nil, fmt.Errorf("response contains no %s data", key)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s data: %w", key, err)
+	}
+	return data, nil
+}
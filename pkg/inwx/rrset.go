@@ -0,0 +1,257 @@
+package inwx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Actions of RRSetChange
+const (
+	RRSetCreate = "create"
+	RRSetUpdate = "update"
+	RRSetDelete = "delete"
+)
+
+// RRSetChange is a change needed to set an RRset. Record is the desired record, or
+// the record to delete; Current is the record before an update.
+type RRSetChange struct {
+	Action  string     `json:"action" yaml:"action"`
+	Record  DNSRecord  `json:"record" yaml:"record"`
+	Current *DNSRecord `json:"current,omitempty" yaml:"current,omitempty"`
+}
+
+// RRSetName splits name into the domain and the record name. Names are relative to
+// the domain of the service; fully qualified names are matched against the domains of
+// the account if the service has no domain.
+func (s *DNSService) RRSetName(ctx context.Context, name string) (string, string, error) {
+	name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+
+	domain := strings.ToLower(s.domain)
+	if domain == "" {
+		domains, err := s.client.Domain().List(ctx)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to list domains: %w", err)
+		}
+		for _, d := range domains {
+			candidate := strings.ToLower(d.Name)
+			if (name == candidate || strings.HasSuffix(name, "."+candidate)) && len(candidate) > len(domain) {
+				domain = candidate
+			}
+		}
+		if domain == "" {
+			return "", "", fmt.Errorf("no domain of the account matches %s", name)
+		}
+	}
+
+	switch {
+	case name == "" || name == "@" || name == domain:
+		return domain, "@", nil
+	case strings.HasSuffix(name, "."+domain):
+		return domain, strings.TrimSuffix(name, "."+domain), nil
+	default:
+		return domain, name, nil
+	}
+}
+
+// rrsetRecords converts the values of an RRset to records. MX values may start with
+// the priority, e.g. "10 mail.example.com", as may SRV values.
+func rrsetRecords(domain, name, recordType string, values []string, ttl int) ([]DNSRecord, error) {
+	var records []DNSRecord
+	seen := make(map[string]bool)
+	for _, value := range values {
+		record := DNSRecord{Domain: domain, Name: name, Type: recordType, Content: strings.TrimSpace(value), TTL: ttl}
+
+		switch recordType {
+		case "A", "AAAA":
+			ip := net.ParseIP(record.Content)
+			if ip == nil || (ip.To4() != nil) != (recordType == "A") {
+				return nil, fmt.Errorf("invalid %s record address %q", recordType, value)
+			}
+			record.Content = ip.String()
+		case "MX":
+			if fields := strings.Fields(record.Content); len(fields) == 2 {
+				prio, err := parseUint(fields[0], "MX priority", 65535)
+				if err != nil {
+					return nil, err
+				}
+				record.Prio, record.Content = prio, fields[1]
+			}
+		case "TXT":
+			record.Content = SplitTXT(TXTValue(record.Content))
+		}
+
+		data, err := ParseRecordData(recordType, record.Content, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s record content %q: %w", recordType, value, err)
+		}
+		if data != nil {
+			record.Content = data.Content()
+			record.Prio = RecordPrio(data)
+		}
+
+		key := strconv.Itoa(record.Prio) + " " + rrsetContentKey(recordType, record.Content)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		records = append(records, record)
+	}
+
+	if recordType == "CNAME" && len(records) > 1 {
+		return nil, errors.New("a CNAME RRset can only have one record")
+	}
+	return records, nil
+}
+
+// rrsetContentKey returns the content used to compare records, ignoring the case and
+// trailing dots of host names and the quoting of TXT records
+func rrsetContentKey(recordType, content string) string {
+	if recordType == "TXT" {
+		return TXTValue(content)
+	}
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(content), "."))
+}
+
+// SameRecordData reports whether two records of a type carry the same data. The
+// priority of MX and SRV records is compared as well; the case and trailing dots of
+// host names and the quoting of TXT records are ignored.
+func SameRecordData(a, b DNSRecord) bool {
+	recordType := strings.ToUpper(b.Type)
+	if (recordType == "MX" || recordType == "SRV") && a.Prio != b.Prio {
+		return false
+	}
+	return rrsetContentKey(recordType, a.Content) == rrsetContentKey(recordType, b.Content)
+}
+
+// PlanRecordSet returns the changes that make desired the only records of an RRset
+// whose records are current. Records that are already present are kept, adjusting
+// only their TTL, and records whose value is no longer wanted are updated to a new
+// value before records are created or deleted.
+func PlanRecordSet(desired, current []DNSRecord) []RRSetChange {
+	var changes []RRSetChange
+	used := make([]bool, len(current))
+	var missing []DNSRecord
+	for _, d := range desired {
+		found := false
+		for i, c := range current {
+			if used[i] || !SameRecordData(c, d) {
+				continue
+			}
+			used[i], found = true, true
+			if c.TTL != d.TTL {
+				d.ID = c.ID
+				changes = append(changes, RRSetChange{Action: RRSetUpdate, Record: d, Current: &current[i]})
+			}
+			break
+		}
+		if !found {
+			missing = append(missing, d)
+		}
+	}
+
+	// Records that are no longer wanted take the new values
+	for _, d := range missing {
+		change := RRSetChange{Action: RRSetCreate, Record: d}
+		for i := range current {
+			if !used[i] {
+				used[i] = true
+				d.ID = current[i].ID
+				change = RRSetChange{Action: RRSetUpdate, Record: d, Current: &current[i]}
+				break
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	for i, c := range current {
+		if !used[i] {
+			changes = append(changes, RRSetChange{Action: RRSetDelete, Record: c})
+		}
+	}
+	return changes
+}
+
+// PlanRRSet returns the changes needed to make values the only records of the type at
+// name, see PlanRecordSet. A TTL of 0 uses the default TTL. No changes are returned if
+// the RRset is up to date.
+func (s *DNSService) PlanRRSet(ctx context.Context, name, recordType string, values []string, ttl int) ([]RRSetChange, error) {
+	recordType = strings.ToUpper(recordType)
+	if recordType == "SOA" {
+		return nil, errors.New("SOA records cannot be set")
+	}
+	if ttl == 0 {
+		ttl = s.defaultTTL
+	}
+
+	domain, name, err := s.RRSetName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := rrsetRecords(domain, name, recordType, values, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.ListRecords(ctx, WithDomainFilter(domain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records of %s: %w", domain, err)
+	}
+	var current []DNSRecord
+	for _, r := range records {
+		recordName := strings.ToLower(r.Name)
+		if recordName == "" {
+			recordName = "@"
+		}
+		if recordName == name && strings.EqualFold(r.Type, recordType) {
+			current = append(current, r)
+		}
+	}
+
+	return PlanRecordSet(desired, current), nil
+}
+
+// ApplyRRSet applies the changes returned by PlanRRSet. Records are created and
+// updated before others are deleted, so the name keeps resolving during the change.
+// The changes are recorded in the backup store of the service.
+func (s *DNSService) ApplyRRSet(ctx context.Context, changes []RRSetChange) error {
+	for _, action := range []string{RRSetUpdate, RRSetCreate, RRSetDelete} {
+		for _, change := range changes {
+			if change.Action != action {
+				continue
+			}
+
+			record := change.Record
+			var err error
+			switch action {
+			case RRSetUpdate:
+				_, err = s.ReplaceRecord(ctx, record.ID, record)
+			case RRSetCreate:
+				_, err = s.CreateRecord(ctx, record)
+			case RRSetDelete:
+				err = s.DeleteRecord(ctx, record.ID)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to %s %s record %s: %w", action, record.Type, record.Content, err)
+			}
+		}
+	}
+	return nil
+}
+
+// SetRRSet makes values the only records of the type at name, applying the minimal
+// set of changes. It does nothing if the RRset is up to date and returns the applied
+// changes.
+func (s *DNSService) SetRRSet(ctx context.Context, name, recordType string, values []string, ttl int) ([]RRSetChange, error) {
+	changes, err := s.PlanRRSet(ctx, name, recordType, values, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ApplyRRSet(ctx, changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
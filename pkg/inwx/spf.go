@@ -0,0 +1,741 @@
+package inwx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SPFLookupLimit is the maximum number of DNS lookups an SPF evaluation may cause
+// (RFC 7208 section 4.6.4)
+const SPFLookupLimit = 10
+
+// SPFVoidLookupLimit is the maximum number of lookups returning no records
+const SPFVoidLookupLimit = 2
+
+// spfMaxDepth stops the expansion of includes at this nesting level
+const spfMaxDepth = 10
+
+// MailResolver resolves the names referenced by SPF, DKIM and DMARC records.
+// *net.Resolver implements it.
+type MailResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// SPFTerm is a mechanism or modifier of an SPF record
+type SPFTerm struct {
+	Qualifier string `json:"qualifier,omitempty" yaml:"qualifier,omitempty"`
+	Name      string `json:"name" yaml:"name"`
+	Value     string `json:"value,omitempty" yaml:"value,omitempty"`
+	Modifier  bool   `json:"modifier,omitempty" yaml:"modifier,omitempty"`
+}
+
+// String returns the term as written in an SPF record
+func (t SPFTerm) String() string {
+	if t.Modifier {
+		return t.Name + "=" + t.Value
+	}
+	qualifier := t.Qualifier
+	if qualifier == "+" {
+		qualifier = ""
+	}
+	if t.Value == "" {
+		return qualifier + t.Name
+	}
+	if strings.HasPrefix(t.Value, "/") {
+		return qualifier + t.Name + t.Value
+	}
+	return qualifier + t.Name + ":" + t.Value
+}
+
+// CausesLookup reports whether evaluating the term counts against the lookup limit
+func (t SPFTerm) CausesLookup() bool {
+	switch t.Name {
+	case "include", "a", "mx", "ptr", "exists", "redirect":
+		return true
+	}
+	return false
+}
+
+// IsSPF reports whether a TXT value is an SPF record
+func IsSPF(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return value == "v=spf1" || strings.HasPrefix(value, "v=spf1 ")
+}
+
+// ParseSPF parses the terms of an SPF record
+func ParseSPF(value string) ([]SPFTerm, error) {
+	if !IsSPF(value) {
+		return nil, fmt.Errorf("not an SPF record: %q", value)
+	}
+
+	fields := strings.Fields(value)[1:]
+	terms := make([]SPFTerm, 0, len(fields))
+	for _, field := range fields {
+		term, err := parseSPFTerm(field)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+func parseSPFTerm(field string) (SPFTerm, error) {
+	// Modifiers are name=value, where the name does not contain ':' or '/'
+	if i := strings.Index(field, "="); i > 0 && !strings.ContainsAny(field[:i], ":/") {
+		return SPFTerm{Name: strings.ToLower(field[:i]), Value: field[i+1:], Modifier: true}, nil
+	}
+
+	term := SPFTerm{Qualifier: "+"}
+	if strings.ContainsAny(field[:1], "+-~?") {
+		term.Qualifier = field[:1]
+		field = field[1:]
+	}
+
+	name := field
+	if i := strings.IndexAny(field, ":/"); i >= 0 {
+		name = field[:i]
+		term.Value = strings.TrimPrefix(field[i:], ":")
+	}
+	term.Name = strings.ToLower(name)
+
+	switch term.Name {
+	case "all":
+		if term.Value != "" {
+			return SPFTerm{}, fmt.Errorf("invalid SPF term %q", field)
+		}
+	case "include", "exists":
+		if term.Value == "" {
+			return SPFTerm{}, fmt.Errorf("SPF mechanism %s requires a domain", term.Name)
+		}
+	case "ip4", "ip6":
+		if !validSPFNetwork(term.Name, term.Value) {
+			return SPFTerm{}, fmt.Errorf("invalid address in SPF term %q", field)
+		}
+	case "a", "mx", "ptr":
+	default:
+		return SPFTerm{}, fmt.Errorf("unknown SPF mechanism %q", term.Name)
+	}
+
+	return term, nil
+}
+
+func validSPFNetwork(mechanism, value string) bool {
+	var ip net.IP
+	if strings.Contains(value, "/") {
+		parsed, _, err := net.ParseCIDR(value)
+		if err != nil {
+			return false
+		}
+		ip = parsed
+	} else {
+		ip = net.ParseIP(value)
+	}
+	if ip == nil {
+		return false
+	}
+	return (ip.To4() != nil) == (mechanism == "ip4")
+}
+
+// SPFNode is an SPF record with its expanded includes
+type SPFNode struct {
+	Domain   string     `json:"domain" yaml:"domain"`
+	Record   string     `json:"record,omitempty" yaml:"record,omitempty"`
+	Terms    []SPFTerm  `json:"-" yaml:"-"`
+	Lookups  int        `json:"lookups" yaml:"lookups"`
+	Includes []*SPFNode `json:"includes,omitempty" yaml:"includes,omitempty"`
+	Error    string     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// spfExpansion tracks the state of a recursive SPF expansion
+type spfExpansion struct {
+	resolver    MailResolver
+	voidLookups int
+	issues      []ValidationIssue
+}
+
+// ExpandSPF parses the SPF record of domain and recursively resolves its includes and
+// redirect. The lookups of every node include those of its children, so the lookups of
+// the returned node are the total counted against SPFLookupLimit.
+func ExpandSPF(ctx context.Context, resolver MailResolver, domain, record string) (*SPFNode, []ValidationIssue) {
+	e := &spfExpansion{resolver: resolver}
+	node := e.expand(ctx, domain, record, map[string]bool{strings.ToLower(domain): true}, 0)
+
+	if node.Lookups > SPFLookupLimit {
+		e.issues = append(e.issues, ValidationIssue{
+			Severity:   "error",
+			Type:       "spf_lookup_limit",
+			Message:    fmt.Sprintf("SPF record of %s requires %d DNS lookups, the limit is %d (permerror)", domain, node.Lookups, SPFLookupLimit),
+			Suggestion: "Remove unused includes or flatten them with 'inwx mail spf flatten'",
+		})
+	} else if node.Lookups >= SPFLookupLimit-1 {
+		e.issues = append(e.issues, ValidationIssue{
+			Severity:   "warning",
+			Type:       "spf_lookup_limit",
+			Message:    fmt.Sprintf("SPF record of %s requires %d of %d DNS lookups", domain, node.Lookups, SPFLookupLimit),
+			Suggestion: "A single additional include will exceed the limit, consider flattening",
+		})
+	}
+
+	if e.voidLookups > SPFVoidLookupLimit {
+		e.issues = append(e.issues, ValidationIssue{
+			Severity:   "error",
+			Type:       "spf_void_lookups",
+			Message:    fmt.Sprintf("SPF record of %s causes %d lookups without result, the limit is %d (permerror)", domain, e.voidLookups, SPFVoidLookupLimit),
+			Suggestion: "Remove includes of domains without SPF record",
+		})
+	}
+
+	return node, e.issues
+}
+
+func (e *spfExpansion) expand(ctx context.Context, domain, record string, visiting map[string]bool, depth int) *SPFNode {
+	node := &SPFNode{Domain: domain, Record: record}
+
+	terms, err := ParseSPF(record)
+	if err != nil {
+		node.Error = err.Error()
+		e.issues = append(e.issues, ValidationIssue{
+			Severity:   "error",
+			Type:       "spf_syntax",
+			Message:    fmt.Sprintf("SPF record of %s is invalid: %v", domain, err),
+			Suggestion: "Fix the record syntax, see RFC 7208",
+		})
+		return node
+	}
+	node.Terms = terms
+
+	for _, term := range terms {
+		if !term.CausesLookup() {
+			continue
+		}
+		node.Lookups++
+
+		if term.Name != "include" && term.Name != "redirect" {
+			continue
+		}
+
+		target := strings.ToLower(strings.TrimSuffix(term.Value, "."))
+		if strings.Contains(target, "%") {
+			// Macros depend on the sender and cannot be expanded here
+			node.Includes = append(node.Includes, &SPFNode{Domain: target, Error: "contains macros, not expanded"})
+			continue
+		}
+		if visiting[target] || depth >= spfMaxDepth {
+			node.Includes = append(node.Includes, &SPFNode{Domain: target, Error: "include loop"})
+			e.issues = append(e.issues, ValidationIssue{
+				Severity:   "error",
+				Type:       "spf_include_loop",
+				Message:    fmt.Sprintf("SPF include of %s by %s creates a loop", target, domain),
+				Suggestion: "Remove the circular include",
+			})
+			continue
+		}
+
+		child := e.resolve(ctx, target, visiting, depth)
+		node.Includes = append(node.Includes, child)
+		node.Lookups += child.Lookups
+	}
+
+	return node
+}
+
+// resolve looks up and expands the SPF record of an included domain
+func (e *spfExpansion) resolve(ctx context.Context, domain string, visiting map[string]bool, depth int) *SPFNode {
+	records, err := LookupSPF(ctx, e.resolver, domain)
+
+	var dnsErr *net.DNSError
+	switch {
+	case err != nil && errors.As(err, &dnsErr) && dnsErr.IsNotFound, err == nil && len(records) == 0:
+		e.voidLookups++
+		e.issues = append(e.issues, ValidationIssue{
+			Severity:   "error",
+			Type:       "spf_missing_include",
+			Message:    fmt.Sprintf("Included domain %s has no SPF record (permerror)", domain),
+			Suggestion: fmt.Sprintf("Remove include:%s or ask its operator to publish an SPF record", domain),
+		})
+		return &SPFNode{Domain: domain, Error: "no SPF record"}
+	case err != nil:
+		e.issues = append(e.issues, ValidationIssue{
+			Severity:   "warning",
+			Type:       "spf_lookup_failed",
+			Message:    fmt.Sprintf("Failed to look up the SPF record of %s: %v", domain, err),
+			Suggestion: "Receivers treat this as a temporary error, check the nameservers of the domain",
+		})
+		return &SPFNode{Domain: domain, Error: err.Error()}
+	case len(records) > 1:
+		e.issues = append(e.issues, ValidationIssue{
+			Severity:   "error",
+			Type:       "spf_multiple",
+			Message:    fmt.Sprintf("Included domain %s publishes %d SPF records (permerror)", domain, len(records)),
+			Suggestion: "A domain must publish exactly one SPF record",
+		})
+		return &SPFNode{Domain: domain, Error: "multiple SPF records"}
+	}
+
+	visiting[domain] = true
+	defer delete(visiting, domain)
+	return e.expand(ctx, domain, records[0], visiting, depth+1)
+}
+
+// LookupSPF returns the SPF records published for domain
+func LookupSPF(ctx context.Context, resolver MailResolver, domain string) ([]string, error) {
+	values, err := resolver.LookupTXT(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []string
+	for _, value := range values {
+		if IsSPF(value) {
+			records = append(records, value)
+		}
+	}
+	return records, nil
+}
+
+// checkSPFPolicy reports weak or problematic terms of the top level SPF record
+func checkSPFPolicy(domain string, terms []SPFTerm) []ValidationIssue {
+	var issues []ValidationIssue
+
+	hasAll := false
+	hasRedirect := false
+	for _, term := range terms {
+		switch {
+		case term.Name == "all":
+			hasAll = true
+			switch term.Qualifier {
+			case "+":
+				issues = append(issues, ValidationIssue{
+					Severity:   "error",
+					Type:       "spf_all_pass",
+					Message:    fmt.Sprintf("SPF record of %s ends with +all and authorizes every server", domain),
+					Suggestion: "Use -all or ~all",
+				})
+			case "?":
+				issues = append(issues, ValidationIssue{
+					Severity:   "warning",
+					Type:       "spf_all_neutral",
+					Message:    fmt.Sprintf("SPF record of %s ends with ?all, which does not protect the domain", domain),
+					Suggestion: "Use -all or ~all once all senders are listed",
+				})
+			}
+		case term.Name == "redirect":
+			hasRedirect = true
+		case term.Name == "ptr":
+			issues = append(issues, ValidationIssue{
+				Severity:   "warning",
+				Type:       "spf_ptr",
+				Message:    fmt.Sprintf("SPF record of %s uses the ptr mechanism, which is deprecated", domain),
+				Suggestion: "Replace ptr with ip4/ip6 or a mechanisms",
+			})
+		}
+	}
+
+	if !hasAll && !hasRedirect {
+		issues = append(issues, ValidationIssue{
+			Severity:   "warning",
+			Type:       "spf_no_all",
+			Message:    fmt.Sprintf("SPF record of %s has no all mechanism, unlisted servers are neutral", domain),
+			Suggestion: "End the record with -all or ~all",
+		})
+	}
+
+	return issues
+}
+
+// errSPFNotFlattenable marks includes that depend on the sender, e.g. through macros
+var errSPFNotFlattenable = errors.New("not flattenable")
+
+// MinSPFRecordLength is the smallest record length FlattenSPF accepts
+const MinSPFRecordLength = 100
+
+// SPFRecord is a record of a flattened SPF record chain
+type SPFRecord struct {
+	// Name is relative to the domain, "@" for the domain itself
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// FlattenedSPF is an SPF record whose includes have been resolved to networks
+type FlattenedSPF struct {
+	Records  []SPFRecord `json:"records" yaml:"records"`
+	Networks int         `json:"networks" yaml:"networks"`
+	Lookups  int         `json:"lookups" yaml:"lookups"`
+	Warnings []string    `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// SPFChainName returns the name of the n-th record of a flattened SPF chain
+func SPFChainName(n int) string {
+	return fmt.Sprintf("_spf%d", n)
+}
+
+// IsSPFChainName reports whether name is the name of a flattened SPF chain record
+func IsSPFChainName(name string) bool {
+	n, ok := strings.CutPrefix(strings.ToLower(name), "_spf")
+	if !ok || n == "" {
+		return false
+	}
+	for _, ch := range n {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FlattenSPF resolves the includes of an SPF record to ip4 and ip6 mechanisms. If the
+// result is longer than maxLength, the networks are moved to a chain of records named
+// _spf1, _spf2, ... below the domain, each including the next one. Includes that
+// depend on the sender and those with a qualifier other than + are kept as they are.
+func FlattenSPF(ctx context.Context, resolver MailResolver, domain, source string, maxLength int) (*FlattenedSPF, error) {
+	if maxLength < MinSPFRecordLength {
+		return nil, fmt.Errorf("maximum record length must be at least %d", MinSPFRecordLength)
+	}
+
+	terms, err := ParseSPF(source)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &spfFlattener{resolver: resolver}
+	result := &FlattenedSPF{}
+
+	// Root terms in their original order; the networks take the position of the
+	// first flattened term
+	var root []string
+	placeholder := -1
+	var allTerm string
+	var nets []*net.IPNet
+
+	hasAll := false
+	for _, term := range terms {
+		hasAll = hasAll || term.Name == "all"
+	}
+
+	for _, term := range terms {
+		flatten := term.Qualifier == "+" || term.Modifier
+		switch {
+		case term.Name == "all":
+			allTerm = term.String()
+			continue
+		case term.Name == "redirect" && hasAll:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Dropped %s, which is ignored next to an all mechanism", term))
+			continue
+		case flatten && (term.Name == "ip4" || term.Name == "ip6"):
+			n, err := parseSPFNetwork(term.Value)
+			if err != nil {
+				return nil, err
+			}
+			nets = append(nets, n)
+		case flatten && (term.Name == "include" || term.Name == "redirect"):
+			collected, all, err := f.collect(ctx, term.Value, map[string]bool{strings.ToLower(domain): true}, 0)
+			if errors.Is(err, errSPFNotFlattenable) {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Kept %s: %v", term, err))
+				root = append(root, term.String())
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			nets = append(nets, collected...)
+			// A redirect supplies the result for mail that matches nothing else
+			if term.Name == "redirect" && all != "" {
+				allTerm = (SPFTerm{Qualifier: all, Name: "all"}).String()
+			}
+		default:
+			if term.Name == "include" || term.Name == "ip4" || term.Name == "ip6" {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Kept %s because of its qualifier", term))
+			}
+			root = append(root, term.String())
+			continue
+		}
+		if placeholder < 0 {
+			placeholder = len(root)
+		}
+	}
+
+	networks := spfNetworkTerms(nets)
+	result.Networks = len(networks)
+	if placeholder < 0 {
+		placeholder = len(root)
+	}
+
+	build := func(middle []string) string {
+		parts := append([]string{"v=spf1"}, root[:placeholder]...)
+		parts = append(parts, middle...)
+		parts = append(parts, root[placeholder:]...)
+		if allTerm != "" {
+			parts = append(parts, allTerm)
+		}
+		return strings.Join(parts, " ")
+	}
+
+	if value := build(networks); len(value) <= maxLength {
+		result.Records = []SPFRecord{{Name: "@", Value: value}}
+	} else {
+		chainDomain := func(n int) string {
+			return SPFChainName(n) + "." + strings.ToLower(domain)
+		}
+		value := build([]string{"include:" + chainDomain(1)})
+		if len(value) > maxLength {
+			return nil, fmt.Errorf("the flattened SPF record is longer than %d characters without networks", maxLength)
+		}
+		result.Records = []SPFRecord{{Name: "@", Value: value}}
+
+		current := "v=spf1"
+		n := 1
+		for _, network := range networks {
+			next := " include:" + chainDomain(n+1)
+			if current != "v=spf1" && len(current)+1+len(network)+len(next) > maxLength {
+				result.Records = append(result.Records, SPFRecord{Name: SPFChainName(n), Value: current + next})
+				current = "v=spf1"
+				n++
+			}
+			current += " " + network
+		}
+		result.Records = append(result.Records, SPFRecord{Name: SPFChainName(n), Value: current})
+	}
+
+	for _, record := range result.Records {
+		terms, _ := ParseSPF(record.Value)
+		for _, term := range terms {
+			if !term.CausesLookup() {
+				continue
+			}
+			if term.Name == "include" && !strings.HasPrefix(strings.ToLower(term.Value), "_spf") {
+				node, _ := ExpandSPF(ctx, resolver, domain, "v=spf1 "+term.String())
+				result.Lookups += node.Lookups
+				continue
+			}
+			result.Lookups++
+		}
+	}
+	if result.Lookups > SPFLookupLimit {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("The flattened record still requires %d DNS lookups, the limit is %d", result.Lookups, SPFLookupLimit))
+	}
+
+	return result, nil
+}
+
+// spfFlattener resolves included SPF records to networks
+type spfFlattener struct {
+	resolver MailResolver
+}
+
+// collect returns the networks an SPF record passes and the qualifier of its all
+// mechanism
+func (f *spfFlattener) collect(ctx context.Context, domain string, visiting map[string]bool, depth int) ([]*net.IPNet, string, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if strings.Contains(domain, "%") {
+		return nil, "", fmt.Errorf("%w: %s contains macros", errSPFNotFlattenable, domain)
+	}
+	if visiting[domain] || depth >= spfMaxDepth {
+		return nil, "", fmt.Errorf("SPF include of %s creates a loop", domain)
+	}
+
+	records, err := LookupSPF(ctx, f.resolver, domain)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to look up the SPF record of %s: %w", domain, err)
+	}
+	switch len(records) {
+	case 0:
+		return nil, "", fmt.Errorf("%s has no SPF record", domain)
+	case 1:
+	default:
+		return nil, "", fmt.Errorf("%s publishes %d SPF records", domain, len(records))
+	}
+
+	terms, err := ParseSPF(records[0])
+	if err != nil {
+		return nil, "", fmt.Errorf("SPF record of %s: %w", domain, err)
+	}
+
+	visiting[domain] = true
+	defer delete(visiting, domain)
+
+	var nets []*net.IPNet
+	all := ""
+	for _, term := range terms {
+		if strings.Contains(term.Value, "%") && term.Name != "exp" {
+			return nil, "", fmt.Errorf("%w: %s in %s uses macros", errSPFNotFlattenable, term, domain)
+		}
+		// Only mechanisms that pass make an include match
+		if !term.Modifier && term.Qualifier != "+" && term.Name != "all" {
+			continue
+		}
+
+		switch term.Name {
+		case "all":
+			all = term.Qualifier
+		case "ip4", "ip6":
+			n, err := parseSPFNetwork(term.Value)
+			if err != nil {
+				return nil, "", err
+			}
+			nets = append(nets, n)
+		case "a", "mx":
+			collected, err := f.addresses(ctx, domain, term)
+			if err != nil {
+				return nil, "", err
+			}
+			nets = append(nets, collected...)
+		case "include", "redirect":
+			collected, redirectAll, err := f.collect(ctx, term.Value, visiting, depth+1)
+			if err != nil {
+				return nil, "", err
+			}
+			nets = append(nets, collected...)
+			if term.Name == "redirect" && all == "" {
+				all = redirectAll
+			}
+		case "ptr", "exists":
+			return nil, "", fmt.Errorf("%w: %s in %s depends on the sender", errSPFNotFlattenable, term, domain)
+		}
+	}
+
+	return nets, all, nil
+}
+
+// addresses resolves an a or mx mechanism to networks
+func (f *spfFlattener) addresses(ctx context.Context, domain string, term SPFTerm) ([]*net.IPNet, error) {
+	host, v4Bits, v6Bits, err := parseSPFHost(term.Value, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := []string{host}
+	if term.Name == "mx" {
+		mxs, err := f.resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up the MX records of %s: %w", host, err)
+		}
+		hosts = hosts[:0]
+		for _, mx := range mxs {
+			hosts = append(hosts, strings.TrimSuffix(mx.Host, "."))
+		}
+	}
+
+	var nets []*net.IPNet
+	for _, h := range hosts {
+		addrs, err := f.resolver.LookupIPAddr(ctx, h)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up the addresses of %s: %w", h, err)
+		}
+		for _, addr := range addrs {
+			if ip4 := addr.IP.To4(); ip4 != nil {
+				nets = append(nets, &net.IPNet{IP: ip4.Mask(net.CIDRMask(v4Bits, 32)), Mask: net.CIDRMask(v4Bits, 32)})
+			} else {
+				nets = append(nets, &net.IPNet{IP: addr.IP.Mask(net.CIDRMask(v6Bits, 128)), Mask: net.CIDRMask(v6Bits, 128)})
+			}
+		}
+	}
+	return nets, nil
+}
+
+// parseSPFHost parses the domain and prefix lengths of an a or mx mechanism, e.g.
+// "mail.example.com/24//64"
+func parseSPFHost(value, domain string) (string, int, int, error) {
+	host := value
+	v4Bits, v6Bits := 32, 128
+
+	if i := strings.Index(value, "/"); i >= 0 {
+		host = value[:i]
+		cidr := value[i+1:]
+		v4, v6, dual := strings.Cut(cidr, "/")
+		if dual {
+			v6 = strings.TrimPrefix(v6, "/")
+		} else {
+			v6 = ""
+		}
+		var err error
+		if v4 != "" {
+			if v4Bits, err = strconv.Atoi(v4); err != nil || v4Bits < 0 || v4Bits > 32 {
+				return "", 0, 0, fmt.Errorf("invalid prefix length in %q", value)
+			}
+		}
+		if v6 != "" {
+			if v6Bits, err = strconv.Atoi(v6); err != nil || v6Bits < 0 || v6Bits > 128 {
+				return "", 0, 0, fmt.Errorf("invalid prefix length in %q", value)
+			}
+		}
+	}
+	if host == "" {
+		host = domain
+	}
+	return host, v4Bits, v6Bits, nil
+}
+
+// parseSPFNetwork parses the address or network of an ip4 or ip6 mechanism
+func parseSPFNetwork(value string) (*net.IPNet, error) {
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid address %q", value)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+
+	_, n, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network %q", value)
+	}
+	return n, nil
+}
+
+// spfNetworkTerms returns sorted ip4 and ip6 mechanisms for the networks. Duplicates
+// and networks contained in others are left out.
+func spfNetworkTerms(nets []*net.IPNet) []string {
+	sort.Slice(nets, func(i, j int) bool {
+		a, b := nets[i], nets[j]
+		if len(a.IP) != len(b.IP) {
+			return len(a.IP) < len(b.IP)
+		}
+		if c := bytes.Compare(a.IP, b.IP); c != 0 {
+			return c < 0
+		}
+		ai, _ := a.Mask.Size()
+		bi, _ := b.Mask.Size()
+		return ai < bi
+	})
+
+	var kept []*net.IPNet
+	var terms []string
+	for _, n := range nets {
+		ones, bits := n.Mask.Size()
+		contained := false
+		for _, k := range kept {
+			kOnes, kBits := k.Mask.Size()
+			if kBits == bits && kOnes <= ones && k.Contains(n.IP) {
+				contained = true
+				break
+			}
+		}
+		if contained {
+			continue
+		}
+		kept = append(kept, n)
+
+		mechanism := "ip6:"
+		if bits == 32 {
+			mechanism = "ip4:"
+		}
+		if ones == bits {
+			terms = append(terms, mechanism+n.IP.String())
+		} else {
+			terms = append(terms, mechanism+n.String())
+		}
+	}
+	return terms
+}
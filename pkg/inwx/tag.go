@@ -0,0 +1,178 @@
+package inwx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type TagService struct {
+	client *Client
+}
+
+// Tag is a label that can be attached to domains to organise them
+type Tag struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	DomainCount int    `json:"domainCount"`
+}
+
+// Tag creates a new tag service instance for managing tags
+func (c *Client) Tag() *TagService {
+	return &TagService{
+		client: c,
+	}
+}
+
+// List returns all tags along with the number of tagged domains
+func (s *TagService) List(ctx context.Context) ([]Tag, error) {
+	tags, err := s.list(ctx, map[string]interface{}{})
+	if err != nil || len(tags) == 0 {
+		return tags, err
+	}
+
+	// Domain counts are only returned when searching by tag ID
+	ids := make([]int, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	return s.list(ctx, map[string]interface{}{"id": ids})
+}
+
+// ListByDomain returns the tags attached to any of the given domains
+func (s *TagService) ListByDomain(ctx context.Context, domains ...string) ([]Tag, error) {
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("no domains specified")
+	}
+	return s.list(ctx, map[string]interface{}{"domain": domains})
+}
+
+func (s *TagService) list(ctx context.Context, params map[string]interface{}) ([]Tag, error) {
+	response, err := s.client.transport.Call(ctx, "tag.list", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	if resData := getResData(response); resData != nil {
+		for _, item := range getMaps(resData, "tag") {
+			tags = append(tags, parseTag(item))
+		}
+	}
+
+	return tags, nil
+}
+
+// Info retrieves a tag by ID
+func (s *TagService) Info(ctx context.Context, id int) (*Tag, error) {
+	response, err := s.client.transport.Call(ctx, "tag.info", map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resData := getResData(response)
+	if resData == nil {
+		return nil, fmt.Errorf("tag %d not found", id)
+	}
+
+	tag := parseTag(resData)
+	return &tag, nil
+}
+
+// Resolve looks up a tag by ID or by its (case-insensitive) name
+func (s *TagService) Resolve(ctx context.Context, ref string) (*Tag, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil {
+		return s.Info(ctx, id)
+	}
+
+	tags, err := s.list(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range tags {
+		if strings.EqualFold(tags[i].Name, ref) {
+			return &tags[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("tag %q not found", ref)
+}
+
+// Create creates a tag and returns it with the name as stored by the API. If the tag
+// already exists, the existing tag is returned.
+func (s *TagService) Create(ctx context.Context, name string) (*Tag, error) {
+	response, err := s.client.transport.Call(ctx, "tag.create", map[string]interface{}{
+		"name": name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &Tag{Name: name}
+	if resData := getResData(response); resData != nil {
+		tag.ID = getInt(resData, "id")
+		if cleaned := getString(resData, "name"); cleaned != "" {
+			tag.Name = cleaned
+		}
+	}
+
+	return tag, nil
+}
+
+// Rename changes the name of a tag
+func (s *TagService) Rename(ctx context.Context, id int, name string) error {
+	_, err := s.client.transport.Call(ctx, "tag.update", map[string]interface{}{
+		"id":   id,
+		"name": name,
+	})
+	return err
+}
+
+// AddDomains attaches a tag to the domains with the given repository object IDs
+func (s *TagService) AddDomains(ctx context.Context, id int, domainIDs []int) error {
+	if len(domainIDs) == 0 {
+		return fmt.Errorf("no domains specified")
+	}
+
+	_, err := s.client.transport.Call(ctx, "tag.update", map[string]interface{}{
+		"id":  id,
+		"add": map[string]interface{}{"domainId": domainIDs},
+	})
+	return err
+}
+
+// RemoveDomains detaches a tag from the domains with the given repository object IDs
+func (s *TagService) RemoveDomains(ctx context.Context, id int, domainIDs []int) error {
+	if len(domainIDs) == 0 {
+		return fmt.Errorf("no domains specified")
+	}
+
+	_, err := s.client.transport.Call(ctx, "tag.update", map[string]interface{}{
+		"id":  id,
+		"rem": map[string]interface{}{"domainId": domainIDs},
+	})
+	return err
+}
+
+// Delete removes a tag from all objects and deletes it
+func (s *TagService) Delete(ctx context.Context, id int) error {
+	_, err := s.client.transport.Call(ctx, "tag.delete", map[string]interface{}{
+		"id": id,
+	})
+	return err
+}
+
+func parseTag(item map[string]interface{}) Tag {
+	tag := Tag{
+		ID:   getInt(item, "id"),
+		Name: getString(item, "name"),
+	}
+	if count, ok := item["count"].(map[string]interface{}); ok {
+		tag.DomainCount = getInt(count, "domain")
+	}
+	return tag
+}
@@ -0,0 +1,110 @@
+package inwx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MaxTXTStringLength is the maximum length of a single character string in a TXT record
+const MaxTXTStringLength = 255
+
+// TXTValue returns the value of TXT record content. Quoted character strings are
+// unquoted and joined, unquoted content is returned as is.
+func TXTValue(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, `"`) {
+		return content
+	}
+
+	var b strings.Builder
+	inQuotes := false
+	for i := 0; i < len(content); i++ {
+		ch := content[i]
+		switch {
+		case ch == '\\' && inQuotes && i+1 < len(content):
+			i++
+			b.WriteByte(content[i])
+		case ch == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// SplitTXT returns TXT record content for a value. Values longer than
+// MaxTXTStringLength are split into several quoted character strings, which resolvers
+// join again.
+func SplitTXT(value string) string {
+	if len(value) <= MaxTXTStringLength {
+		return value
+	}
+
+	var parts []string
+	for len(value) > 0 {
+		n := MaxTXTStringLength
+		if len(value) < n {
+			n = len(value)
+		}
+		chunk := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value[:n])
+		parts = append(parts, `"`+chunk+`"`)
+		value = value[n:]
+	}
+	return strings.Join(parts, " ")
+}
+
+// FindTXT returns the TXT records at name whose value matches, e.g. the SPF record of
+// a domain. A nil match returns all TXT records at name.
+func FindTXT(records []DNSRecord, name string, match func(value string) bool) []DNSRecord {
+	var found []DNSRecord
+	for _, r := range records {
+		if r.Type != "TXT" || !strings.EqualFold(r.Name, name) {
+			continue
+		}
+		if match == nil || match(TXTValue(r.Content)) {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+// ReplaceTXT sets the value of the matching TXT records at name. The first record is
+// updated and the others are deleted, a record is created if none matches. Long values
+// are split with SplitTXT. It reports whether anything changed.
+func (s *DNSService) ReplaceTXT(ctx context.Context, domain, name, value string, ttl int, match func(value string) bool) (bool, error) {
+	records, err := s.ListRecords(ctx, WithDomainFilter(domain))
+	if err != nil {
+		return false, fmt.Errorf("failed to list records: %w", err)
+	}
+
+	content := SplitTXT(value)
+	current := FindTXT(records, name, match)
+	if len(current) == 0 {
+		_, err := s.CreateRecord(ctx, DNSRecord{
+			Domain:  domain,
+			Name:    name,
+			Type:    "TXT",
+			Content: content,
+			TTL:     ttl,
+		})
+		return err == nil, err
+	}
+
+	changed := false
+	first := current[0]
+	if TXTValue(first.Content) != value || first.TTL != ttl {
+		if _, err := s.UpdateRecord(ctx, first.ID, DNSRecord{Content: content, TTL: ttl}); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	for _, r := range current[1:] {
+		if err := s.DeleteRecord(ctx, r.ID); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
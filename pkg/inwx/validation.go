@@ -37,8 +37,6 @@ type ValidationSummary struct {
 func (s *DNSService) ValidateDomain(ctx context.Context, domain string) (*ValidationResult, error) {
 	log.Debug().Str("domain", domain).Msg("Validating domain")
 
-	result := &ValidationResult{Domain: domain}
-
 	// Get all records for the domain
 	records, err := s.ListRecords(ctx, WithDomainFilter(domain))
 	if err != nil {
@@ -47,6 +45,23 @@ func (s *DNSService) ValidateDomain(ctx context.Context, domain string) (*Valida
 
 	log.Debug().Int("count", len(records)).Msg("Retrieved records for validation")
 
+	result := s.ValidateRecords(ctx, domain, records)
+
+	log.Debug().
+		Int("total", result.Summary.Total).
+		Int("errors", result.Summary.Errors).
+		Int("warnings", result.Summary.Warnings).
+		Int("info", result.Summary.Info).
+		Msg("Validation complete")
+
+	return result, nil
+}
+
+// ValidateRecords runs the validation checks on a set of records of a domain, e.g. to
+// check the outcome of planned changes before applying them
+func (s *DNSService) ValidateRecords(ctx context.Context, domain string, records []DNSRecord) *ValidationResult {
+	result := &ValidationResult{Domain: domain}
+
 	// Build lookup maps for efficient checking
 	recordsByName := make(map[string][]DNSRecord)
 	aRecords := make(map[string]bool) // Has A or AAAA record
@@ -67,32 +82,32 @@ func (s *DNSService) ValidateDomain(ctx context.Context, domain string) (*Valida
 	s.checkOrphanedCNAMEs(domain, records, aRecords, &result.Issues)
 	s.checkMXTargets(domain, records, aRecords, &result.Issues)
 	s.checkNSTargets(domain, records, aRecords, &result.Issues)
+	s.checkGlueHosts(ctx, domain, records, &result.Issues)
 	s.checkCNAMEConflicts(recordsByName, &result.Issues)
 	s.checkSRVTargets(domain, records, aRecords, &result.Issues)
 	s.checkCommonBestPractices(domain, recordsByName, recordsByType, &result.Issues)
 	s.checkTTLValues(records, &result.Issues)
 
-	// Calculate summary
-	for _, issue := range result.Issues {
-		result.Summary.Total++
+	result.Summary = summarizeIssues(result.Issues)
+
+	return result
+}
+
+// summarizeIssues counts issues by severity
+func summarizeIssues(issues []ValidationIssue) ValidationSummary {
+	var summary ValidationSummary
+	for _, issue := range issues {
+		summary.Total++
 		switch issue.Severity {
 		case "error":
-			result.Summary.Errors++
+			summary.Errors++
 		case "warning":
-			result.Summary.Warnings++
+			summary.Warnings++
 		case "info":
-			result.Summary.Info++
+			summary.Info++
 		}
 	}
-
-	log.Debug().
-		Int("total", result.Summary.Total).
-		Int("errors", result.Summary.Errors).
-		Int("warnings", result.Summary.Warnings).
-		Int("info", result.Summary.Info).
-		Msg("Validation complete")
-
-	return result, nil
+	return summary
 }
 
 // getFullName returns the fully qualified name for a record
@@ -200,6 +215,52 @@ func (s *DNSService) checkNSTargets(domain string, records []DNSRecord, aRecords
 	}
 }
 
+// checkGlueHosts validates that in-bailiwick nameservers of the domain itself are
+// registered as host objects, since the parent zone needs their glue records
+func (s *DNSService) checkGlueHosts(ctx context.Context, domain string, records []DNSRecord, issues *[]ValidationIssue) {
+	var nsRecords []DNSRecord
+	for _, record := range records {
+		if record.Type != "NS" || (record.Name != "" && record.Name != "@") {
+			continue
+		}
+		target := strings.ToLower(strings.TrimSuffix(record.Content, "."))
+		if strings.HasSuffix(target, "."+domain) || target == domain {
+			nsRecords = append(nsRecords, record)
+		}
+	}
+	if len(nsRecords) == 0 {
+		return
+	}
+
+	hosts, err := s.client.Host().List(ctx, "*."+domain)
+	if err != nil {
+		log.Debug().Err(err).Str("domain", domain).Msg("Failed to list hosts, skipping glue check")
+		return
+	}
+
+	glue := make(map[string]bool)
+	for _, host := range hosts {
+		glue[strings.ToLower(strings.TrimSuffix(host.Hostname, "."))] = true
+	}
+
+	for _, record := range nsRecords {
+		target := strings.ToLower(strings.TrimSuffix(record.Content, "."))
+		if glue[target] {
+			continue
+		}
+
+		rec := record
+		*issues = append(*issues, ValidationIssue{
+			Severity:   "warning",
+			Type:       "missing_glue_host",
+			RecordID:   record.ID,
+			Record:     &rec,
+			Message:    fmt.Sprintf("Nameserver %s is inside %s but has no glue host", target, domain),
+			Suggestion: fmt.Sprintf("Register the glue record with 'inwx host create %s --ip <address>'", target),
+		})
+	}
+}
+
 // checkCNAMEConflicts detects CNAME records coexisting with other record types
 func (s *DNSService) checkCNAMEConflicts(recordsByName map[string][]DNSRecord, issues *[]ValidationIssue) {
 	for name, recs := range recordsByName {
@@ -263,9 +324,8 @@ func (s *DNSService) checkSRVTargets(domain string, records []DNSRecord, aRecord
 			continue
 		}
 
-		// SRV format: "priority weight port target"
-		parts := strings.Fields(record.Content)
-		if len(parts) != 4 {
+		srv, err := ParseSRV(record.Content, record.Prio)
+		if err != nil {
 			rec := record
 			*issues = append(*issues, ValidationIssue{
 				Severity:   "error",
@@ -273,12 +333,12 @@ func (s *DNSService) checkSRVTargets(domain string, records []DNSRecord, aRecord
 				RecordID:   record.ID,
 				Record:     &rec,
 				Message:    fmt.Sprintf("SRV record has invalid format: %s", record.Content),
-				Suggestion: "SRV records should be in format 'priority weight port target'",
+				Suggestion: "SRV records should be in format 'weight port target' with the priority in prio",
 			})
 			continue
 		}
 
-		srvTarget := strings.TrimSuffix(parts[3], ".")
+		srvTarget := strings.TrimSuffix(srv.Target, ".")
 
 		// Only check targets within the same domain
 		if strings.HasSuffix(srvTarget, "."+domain) || srvTarget == domain {
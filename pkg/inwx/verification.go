@@ -141,6 +141,12 @@ func (s *DNSService) VerifyDomainRecords(ctx context.Context, domain, name, reco
 	return result, nil
 }
 
+// VerifyRecordGroup queries the authoritative nameservers of domain and the public
+// resolvers for hostname and compares the answers with the expected values
+func (s *DNSService) VerifyRecordGroup(ctx context.Context, domain, hostname, recordType string, expected []string) RecordVerification {
+	return s.verifyRecordGroup(ctx, domain, hostname, recordType, expected)
+}
+
 // verifyRecordGroup verifies a single hostname+type combination
 func (s *DNSService) verifyRecordGroup(ctx context.Context, domain, hostname, recordType string, expected []string) RecordVerification {
 	verification := RecordVerification{
@@ -85,6 +85,11 @@ func formatZoneRecord(record DNSRecord) string {
 	}
 
 	parts = append(parts, record.Content)
+	if record.Type == "URL" {
+		if comment := urlRedirectComment(record); comment != "" {
+			parts = append(parts, comment)
+		}
+	}
 
 	return strings.Join(parts, " ")
 }
@@ -133,5 +138,15 @@ func parseZoneRecord(line, domain string) (*DNSRecord, error) {
 		record.Content = strings.Join(fields[contentStart:], " ")
 	}
 
+	// Redirect parameters of URL records are kept in a trailing comment
+	if strings.EqualFold(record.Type, "URL") {
+		if content, comment, ok := strings.Cut(record.Content, " ; "); ok {
+			record.Content = content
+			if err := parseURLRedirectComment(record, comment); err != nil {
+				return nil, fmt.Errorf("invalid redirect parameters: %w", err)
+			}
+		}
+	}
+
 	return record, nil
 }